@@ -0,0 +1,334 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package configsource
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/appconfigdata"
+	"github.com/aws/aws-sdk-go/service/appconfigdata/appconfigdataiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/cfg/commonconfig"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	"github.com/aws/amazon-cloudwatch-agent/translator/util"
+)
+
+const (
+	// maxContentLength is the largest config accepted from any location.
+	maxContentLength = 10 * 1024 * 1024 // 10MB
+	defaultTimeout   = 30 * time.Second
+	// maxRedirects matches the limit of the default http client.
+	maxRedirects = 10
+)
+
+// Result is the content fetched from a location.
+type Result struct {
+	Content []byte
+	// Version identifies the fetched content. It is the ETag for s3 and https,
+	// the parameter version for ssm, the modification time for file and the
+	// version label for appconfig. If the location does not provide one, a
+	// checksum of the content is used instead.
+	Version string
+	// NotModified is set when the location still has the version passed to
+	// Fetch. The content may be empty in that case.
+	NotModified bool
+}
+
+// Fetcher downloads configs from the supported locations.
+type Fetcher struct {
+	region      string
+	mode        string
+	credsConfig map[string]string
+
+	httpClient      *http.Client
+	ssmClient       ssmiface.SSMAPI
+	s3Client        s3iface.S3API
+	appConfigClient appconfigdataiface.AppConfigDataAPI
+	// appConfigSessions are the open appconfig sessions by location.
+	appConfigSessions map[string]*appConfigSession
+}
+
+// appConfigSession is an appconfig configuration session. AppConfig only
+// returns the configuration from a session when it changed since the last
+// token, so the version of the last configuration is kept with the token.
+type appConfigSession struct {
+	token   *string
+	version string
+}
+
+func NewFetcher(region, mode string, credsConfig map[string]string) *Fetcher {
+	return &Fetcher{
+		region:      region,
+		mode:        mode,
+		credsConfig: credsConfig,
+		httpClient:  &http.Client{Timeout: defaultTimeout, CheckRedirect: checkRedirect},
+	}
+}
+
+// checkRedirect only follows redirects to https, so a config that is fetched
+// over https is never downloaded in plain text.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if req.URL.Scheme != "https" {
+		return fmt.Errorf("redirect to %s is not https", req.URL.Redacted())
+	}
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	return nil
+}
+
+// Fetch downloads the content of the location. If version is not empty and
+// the location still has the same version, the result is marked as not
+// modified. For s3 and https this uses conditional requests so the content is
+// not downloaded again.
+func (f *Fetcher) Fetch(location Location, version string) (*Result, error) {
+	var result *Result
+	var err error
+	switch location.Type {
+	case LocationDefault:
+		result = &Result{Content: []byte(config.DefaultJsonConfig(config.ToValidOs(""), f.mode))}
+	case LocationSSM:
+		result, err = f.fetchFromSSM(location.Path)
+	case LocationFile:
+		result, err = fetchFromFile(location.Path)
+	case LocationS3:
+		result, err = f.fetchFromS3(location, version)
+	case LocationHTTPS:
+		result, err = f.fetchFromHTTPS(location.Path, version)
+	case LocationAppConfig:
+		result, err = f.fetchFromAppConfig(location, version)
+	default:
+		return nil, fmt.Errorf("location type %s is not supported", location.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if result.Version == "" && !result.NotModified {
+		result.Version = checksumVersion(result.Content)
+	}
+	if version != "" && result.Version == version {
+		result.NotModified = true
+	}
+	return result, nil
+}
+
+func (f *Fetcher) session() (*session.Session, error) {
+	credsMap := util.GetCredentials(f.mode, f.credsConfig)
+	profile, profileOk := credsMap[commonconfig.CredentialProfile]
+	sharedConfigFile, sharedConfigFileOk := credsMap[commonconfig.CredentialFile]
+	rootconfig := &aws.Config{
+		Region:   aws.String(f.region),
+		LogLevel: configaws.SDKLogLevel(),
+		Logger:   configaws.SDKLogger{},
+	}
+	if profileOk || sharedConfigFileOk {
+		rootconfig.Credentials = credentials.NewCredentials(&credentials.SharedCredentialsProvider{
+			Filename: sharedConfigFile,
+			Profile:  profile,
+		})
+	}
+	ses, err := session.NewSession(rootconfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create session: %w", err)
+	}
+	return ses, nil
+}
+
+func (f *Fetcher) fetchFromSSM(parameterStoreName string) (*Result, error) {
+	if f.ssmClient == nil {
+		ses, err := f.session()
+		if err != nil {
+			return nil, err
+		}
+		f.ssmClient = ssm.New(ses)
+	}
+	output, err := f.ssmClient.GetParameter(&ssm.GetParameterInput{
+		Name:           aws.String(parameterStoreName),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve parameter store content: %w", err)
+	}
+	return &Result{
+		Content: []byte(aws.StringValue(output.Parameter.Value)),
+		Version: strconv.FormatInt(aws.Int64Value(output.Parameter.Version), 10),
+	}, nil
+}
+
+func fetchFromFile(filePath string) (*Result, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Content: content, Version: info.ModTime().UTC().Format(time.RFC3339Nano)}, nil
+}
+
+func (f *Fetcher) fetchFromS3(location Location, version string) (*Result, error) {
+	if f.s3Client == nil {
+		ses, err := f.session()
+		if err != nil {
+			return nil, err
+		}
+		f.s3Client = s3.New(ses)
+	}
+	bucket, key := location.Bucket()
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if version != "" {
+		input.IfNoneMatch = aws.String(version)
+	}
+	output, err := f.s3Client.GetObject(input)
+	if err != nil {
+		var requestFailure awserr.RequestFailure
+		if errors.As(err, &requestFailure) && requestFailure.StatusCode() == http.StatusNotModified {
+			return &Result{Version: version, NotModified: true}, nil
+		}
+		return nil, fmt.Errorf("unable to get %s: %w", location, err)
+	}
+	defer output.Body.Close()
+	content, err := readLimited(output.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", location, err)
+	}
+	return &Result{Content: content, Version: aws.StringValue(output.ETag)}, nil
+}
+
+func (f *Fetcher) fetchFromHTTPS(url, version string) (*Result, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if version != "" {
+		req.Header.Set("If-None-Match", version)
+	}
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get response from %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return &Result{Version: version, NotModified: true}, nil
+	default:
+		return nil, fmt.Errorf("unable to get response from %s, status code: %d", url, resp.StatusCode)
+	}
+	content, err := readLimited(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read response from %s: %w", url, err)
+	}
+	return &Result{Content: content, Version: resp.Header.Get("ETag")}, nil
+}
+
+// fetchFromAppConfig gets the latest configuration using the session of the
+// location, which is started on the first fetch and restarted if its token is
+// no longer valid or the caller does not have the version of the session.
+func (f *Fetcher) fetchFromAppConfig(location Location, version string) (*Result, error) {
+	if f.appConfigClient == nil {
+		ses, err := f.session()
+		if err != nil {
+			return nil, err
+		}
+		f.appConfigClient = appconfigdata.New(ses)
+	}
+	key := location.String()
+	current, ok := f.appConfigSessions[key]
+	if ok && version != current.version {
+		current, ok = nil, false
+	}
+	if ok {
+		result, err := f.getLatestAppConfig(location, current)
+		if err == nil {
+			return result, nil
+		}
+		// tokens expire after 24 hours and are only valid once
+		var awsErr awserr.Error
+		if !errors.As(err, &awsErr) || awsErr.Code() != appconfigdata.ErrCodeBadRequestException {
+			return nil, err
+		}
+	}
+	application, environment, profile := location.AppConfig()
+	started, err := f.appConfigClient.StartConfigurationSession(&appconfigdata.StartConfigurationSessionInput{
+		ApplicationIdentifier:          aws.String(application),
+		EnvironmentIdentifier:          aws.String(environment),
+		ConfigurationProfileIdentifier: aws.String(profile),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to start appconfig session for %s: %w", location, err)
+	}
+	return f.getLatestAppConfig(location, &appConfigSession{token: started.InitialConfigurationToken})
+}
+
+func (f *Fetcher) getLatestAppConfig(location Location, current *appConfigSession) (*Result, error) {
+	key := location.String()
+	delete(f.appConfigSessions, key)
+	output, err := f.appConfigClient.GetLatestConfiguration(&appconfigdata.GetLatestConfigurationInput{
+		ConfigurationToken: current.token,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get appconfig configuration for %s: %w", location, err)
+	}
+	if len(output.Configuration) > maxContentLength {
+		return nil, fmt.Errorf("appconfig configuration for %s exceeds %d bytes", location, maxContentLength)
+	}
+	next := &appConfigSession{token: output.NextPollConfigurationToken, version: current.version}
+	var result *Result
+	if len(output.Configuration) == 0 && current.version != "" {
+		result = &Result{Version: current.version, NotModified: true}
+	} else {
+		next.version = aws.StringValue(output.VersionLabel)
+		if next.version == "" {
+			next.version = checksumVersion(output.Configuration)
+		}
+		result = &Result{Content: output.Configuration, Version: next.version}
+	}
+	if next.token != nil {
+		if f.appConfigSessions == nil {
+			f.appConfigSessions = make(map[string]*appConfigSession)
+		}
+		f.appConfigSessions[key] = next
+	}
+	return result, nil
+}
+
+func readLimited(reader io.Reader) ([]byte, error) {
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(reader, maxContentLength+1))
+	if err != nil {
+		return nil, err
+	}
+	if n > maxContentLength {
+		return nil, fmt.Errorf("content exceeds %d bytes", maxContentLength)
+	}
+	return buf.Bytes(), nil
+}
+
+func checksumVersion(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
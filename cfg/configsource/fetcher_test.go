@@ -0,0 +1,266 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package configsource
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/appconfigdata"
+	"github.com/aws/aws-sdk-go/service/appconfigdata/appconfigdataiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSSM struct {
+	ssmiface.SSMAPI
+	value   string
+	version int64
+}
+
+func (m *mockSSM) GetParameter(*ssm.GetParameterInput) (*ssm.GetParameterOutput, error) {
+	return &ssm.GetParameterOutput{Parameter: &ssm.Parameter{Value: aws.String(m.value), Version: aws.Int64(m.version)}}, nil
+}
+
+type mockS3 struct {
+	s3iface.S3API
+	content string
+	etag    string
+	input   *s3.GetObjectInput
+}
+
+func (m *mockS3) GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	m.input = input
+	if aws.StringValue(input.IfNoneMatch) == m.etag {
+		return nil, awserr.NewRequestFailure(awserr.New("NotModified", "Not Modified", nil), http.StatusNotModified, "")
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(strings.NewReader(m.content)), ETag: aws.String(m.etag)}, nil
+}
+
+// mockAppConfig hands out single use tokens and only returns the
+// configuration if it changed since the token was issued.
+type mockAppConfig struct {
+	appconfigdataiface.AppConfigDataAPI
+	content  string
+	sessions int
+	tokens   map[string]string
+	next     int
+}
+
+func (m *mockAppConfig) token(content string) *string {
+	if m.tokens == nil {
+		m.tokens = make(map[string]string)
+	}
+	m.next++
+	token := strconv.Itoa(m.next)
+	m.tokens[token] = content
+	return aws.String(token)
+}
+
+func (m *mockAppConfig) StartConfigurationSession(input *appconfigdata.StartConfigurationSessionInput) (*appconfigdata.StartConfigurationSessionOutput, error) {
+	if aws.StringValue(input.ApplicationIdentifier) != "app" || aws.StringValue(input.EnvironmentIdentifier) != "prod" || aws.StringValue(input.ConfigurationProfileIdentifier) != "agent" {
+		return nil, awserr.New(appconfigdata.ErrCodeResourceNotFoundException, "not found", nil)
+	}
+	m.sessions++
+	return &appconfigdata.StartConfigurationSessionOutput{InitialConfigurationToken: m.token("")}, nil
+}
+
+func (m *mockAppConfig) GetLatestConfiguration(input *appconfigdata.GetLatestConfigurationInput) (*appconfigdata.GetLatestConfigurationOutput, error) {
+	token := aws.StringValue(input.ConfigurationToken)
+	last, ok := m.tokens[token]
+	if !ok {
+		return nil, awserr.New(appconfigdata.ErrCodeBadRequestException, "invalid token", nil)
+	}
+	delete(m.tokens, token)
+	output := &appconfigdata.GetLatestConfigurationOutput{NextPollConfigurationToken: m.token(m.content)}
+	if last != m.content {
+		output.Configuration = []byte(m.content)
+	}
+	return output, nil
+}
+
+func TestFetchFromSSM(t *testing.T) {
+	f := NewFetcher("us-east-1", "ec2", nil)
+	f.ssmClient = &mockSSM{value: `{"agent":{}}`, version: 3}
+	got, err := f.Fetch(Location{Type: LocationSSM, Path: "param"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, `{"agent":{}}`, string(got.Content))
+	assert.Equal(t, "3", got.Version)
+	assert.False(t, got.NotModified)
+
+	got, err = f.Fetch(Location{Type: LocationSSM, Path: "param"}, "3")
+	require.NoError(t, err)
+	assert.True(t, got.NotModified)
+}
+
+func TestFetchFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"logs":{}}`), 0644))
+	f := NewFetcher("", "onPremise", nil)
+	got, err := f.Fetch(Location{Type: LocationFile, Path: path}, "")
+	require.NoError(t, err)
+	assert.Equal(t, `{"logs":{}}`, string(got.Content))
+	assert.NotEmpty(t, got.Version)
+
+	got, err = f.Fetch(Location{Type: LocationFile, Path: path}, got.Version)
+	require.NoError(t, err)
+	assert.True(t, got.NotModified)
+
+	_, err = f.Fetch(Location{Type: LocationFile, Path: filepath.Join(t.TempDir(), "missing.json")}, "")
+	assert.Error(t, err)
+}
+
+func TestFetchFromS3(t *testing.T) {
+	client := &mockS3{content: `{"metrics":{}}`, etag: `"abc"`}
+	f := NewFetcher("us-east-1", "ec2", nil)
+	f.s3Client = client
+	location := Location{Type: LocationS3, Path: "bucket/path/config.json"}
+
+	got, err := f.Fetch(location, "")
+	require.NoError(t, err)
+	assert.Equal(t, "bucket", aws.StringValue(client.input.Bucket))
+	assert.Equal(t, "path/config.json", aws.StringValue(client.input.Key))
+	assert.Nil(t, client.input.IfNoneMatch)
+	assert.Equal(t, `{"metrics":{}}`, string(got.Content))
+	assert.Equal(t, `"abc"`, got.Version)
+
+	got, err = f.Fetch(location, `"abc"`)
+	require.NoError(t, err)
+	assert.Equal(t, `"abc"`, aws.StringValue(client.input.IfNoneMatch))
+	assert.True(t, got.NotModified)
+	assert.Empty(t, got.Content)
+
+	got, err = f.Fetch(location, `"old"`)
+	require.NoError(t, err)
+	assert.False(t, got.NotModified)
+	assert.Equal(t, `"abc"`, got.Version)
+}
+
+func TestFetchFromHTTPS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config.json":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(`{"traces":{}}`))
+		case "/no-etag.json":
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	f := NewFetcher("", "ec2", nil)
+	f.httpClient = server.Client()
+
+	got, err := f.Fetch(Location{Type: LocationHTTPS, Path: server.URL + "/config.json"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, `{"traces":{}}`, string(got.Content))
+	assert.Equal(t, `"v1"`, got.Version)
+
+	got, err = f.Fetch(Location{Type: LocationHTTPS, Path: server.URL + "/config.json"}, `"v1"`)
+	require.NoError(t, err)
+	assert.True(t, got.NotModified)
+
+	got, err = f.Fetch(Location{Type: LocationHTTPS, Path: server.URL + "/no-etag.json"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a", got.Version)
+
+	_, err = f.Fetch(Location{Type: LocationHTTPS, Path: server.URL + "/missing.json"}, "")
+	assert.ErrorContains(t, err, "status code: 404")
+}
+
+func TestFetchFromHTTPSRedirect(t *testing.T) {
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer plain.Close()
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config.json":
+			w.Write([]byte(`{"traces":{}}`))
+		case "/https":
+			http.Redirect(w, r, server.URL+"/config.json", http.StatusFound)
+		case "/http":
+			http.Redirect(w, r, plain.URL+"/config.json", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+	f := NewFetcher("", "ec2", nil)
+	client := server.Client()
+	client.CheckRedirect = f.httpClient.CheckRedirect
+	f.httpClient = client
+
+	got, err := f.Fetch(Location{Type: LocationHTTPS, Path: server.URL + "/https"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, `{"traces":{}}`, string(got.Content))
+
+	_, err = f.Fetch(Location{Type: LocationHTTPS, Path: server.URL + "/http"}, "")
+	assert.ErrorContains(t, err, "is not https")
+}
+
+func TestFetchFromAppConfig(t *testing.T) {
+	f := NewFetcher("us-east-1", "ec2", nil)
+	f.appConfigClient = &mockAppConfig{content: `{"agent":{"debug":true}}`}
+	got, err := f.Fetch(Location{Type: LocationAppConfig, Path: "app/prod/agent"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, `{"agent":{"debug":true}}`, string(got.Content))
+	assert.True(t, strings.HasPrefix(got.Version, "sha256:"))
+}
+
+func TestFetchFromAppConfigSession(t *testing.T) {
+	f := NewFetcher("us-east-1", "ec2", nil)
+	client := &mockAppConfig{content: "v1"}
+	f.appConfigClient = client
+	location := Location{Type: LocationAppConfig, Path: "app/prod/agent"}
+	first, err := f.Fetch(location, "")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(first.Content))
+
+	// the session is kept and an unchanged configuration is not returned again
+	got, err := f.Fetch(location, first.Version)
+	require.NoError(t, err)
+	assert.True(t, got.NotModified)
+	assert.Equal(t, first.Version, got.Version)
+	assert.Equal(t, 1, client.sessions)
+
+	client.content = "v2"
+	got, err = f.Fetch(location, first.Version)
+	require.NoError(t, err)
+	assert.False(t, got.NotModified)
+	assert.Equal(t, "v2", string(got.Content))
+	assert.Equal(t, 1, client.sessions)
+
+	// a caller without the content of the session gets it from a new session
+	got, err = f.Fetch(location, "")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(got.Content))
+	assert.Equal(t, 2, client.sessions)
+
+	// an expired token starts a new session
+	client.tokens = nil
+	again, err := f.Fetch(location, got.Version)
+	require.NoError(t, err)
+	assert.True(t, again.NotModified)
+	assert.Equal(t, 3, client.sessions)
+
+	_, err = f.Fetch(Location{Type: LocationAppConfig, Path: "app/prod/missing"}, "")
+	assert.ErrorContains(t, err, "unable to start appconfig session")
+}
@@ -0,0 +1,165 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package configsource
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultPollInterval = 5 * time.Minute
+	MinPollInterval     = time.Minute
+	// DefaultPollJitter spreads the polls of a fleet of agents over 10% of the
+	// interval on either side.
+	DefaultPollJitter = 0.1
+)
+
+type fetcher interface {
+	Fetch(location Location, version string) (*Result, error)
+}
+
+// Poller periodically fetches a location and applies new versions of the
+// config. Every decision is written to the agent log as an audit entry with
+// the old and new version.
+type Poller struct {
+	fetcher  fetcher
+	location Location
+	interval time.Duration
+	jitter   float64
+
+	// Current returns the config that is in use so that the first poll does
+	// not apply an unchanged config again. Optional.
+	Current func() []byte
+	// Verification is the signature a new version has to match before it is
+	// validated, the same as for a downloaded config. Optional.
+	Verification Verification
+	// Validate is the gate a new version has to pass before it is applied.
+	// Optional.
+	Validate func(content []byte) error
+	// Apply installs the new version of the config.
+	Apply func(content []byte) error
+	// OnVersion is called with the version in use after each poll. Optional.
+	OnVersion func(version string)
+
+	mu      sync.Mutex
+	version string
+	// rejected is the last version that did not pass the validation, so it
+	// is not validated again on every poll.
+	rejected string
+}
+
+func NewPoller(fetcher *Fetcher, location Location, interval time.Duration, jitter float64) *Poller {
+	return newPoller(fetcher, location, interval, jitter)
+}
+
+func newPoller(fetcher fetcher, location Location, interval time.Duration, jitter float64) *Poller {
+	if interval < MinPollInterval {
+		interval = MinPollInterval
+	}
+	if jitter < 0 || jitter >= 1 {
+		jitter = DefaultPollJitter
+	}
+	return &Poller{
+		fetcher:  fetcher,
+		location: location,
+		interval: interval,
+		jitter:   jitter,
+	}
+}
+
+// Location returns the polled location.
+func (p *Poller) Location() Location {
+	return p.location
+}
+
+// Version returns the version of the config in use or an empty string if it
+// is not known yet.
+func (p *Poller) Version() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.version
+}
+
+// Run polls immediately and then after every jittered interval until the
+// context is done.
+func (p *Poller) Run(ctx context.Context) {
+	log.Printf("I! Polling config at %s every %v", p.location, p.interval)
+	for {
+		p.Poll()
+		timer := time.NewTimer(p.nextInterval())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Poll fetches the location once and applies the config if there is a new
+// version. Returns true if a new version was applied.
+func (p *Poller) Poll() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	applied := p.poll()
+	if p.OnVersion != nil && p.version != "" {
+		p.OnVersion(p.version)
+	}
+	return applied
+}
+
+func (p *Poller) poll() bool {
+	result, err := p.fetcher.Fetch(p.location, p.version)
+	if err != nil {
+		log.Printf("E! Unable to poll config at %s: %v", p.location, err)
+		return false
+	}
+	if result.NotModified {
+		log.Printf("D! Config at %s is unchanged at version %s", p.location, p.version)
+		return false
+	}
+	if p.version == "" && p.Current != nil && bytes.Equal(p.Current(), result.Content) {
+		log.Printf("I! Config audit: location=%s version=%s result=in_use", p.location, result.Version)
+		p.version = result.Version
+		return false
+	}
+	if result.Version == p.rejected {
+		log.Printf("D! Config at %s is still at rejected version %s", p.location, p.rejected)
+		return false
+	}
+	if err = p.Verification.verify(p.fetcher, result.Content); err != nil {
+		log.Printf("E! Config audit: location=%s old_version=%s new_version=%s result=rejected reason=%q", p.location, p.version, result.Version, err)
+		p.rejected = result.Version
+		return false
+	}
+	if p.Validate != nil {
+		if err = p.Validate(result.Content); err != nil {
+			log.Printf("E! Config audit: location=%s old_version=%s new_version=%s result=rejected reason=%q", p.location, p.version, result.Version, err)
+			p.rejected = result.Version
+			return false
+		}
+	}
+	if err = p.Apply(result.Content); err != nil {
+		log.Printf("E! Config audit: location=%s old_version=%s new_version=%s result=failed reason=%q", p.location, p.version, result.Version, err)
+		return false
+	}
+	log.Printf("I! Config audit: location=%s old_version=%s new_version=%s result=applied", p.location, p.version, result.Version)
+	p.version = result.Version
+	return true
+}
+
+// nextInterval returns the interval shifted by a random amount within the
+// jitter so that agents started together do not poll together.
+func (p *Poller) nextInterval() time.Duration {
+	if p.jitter == 0 {
+		return p.interval
+	}
+	offset := (rand.Float64()*2 - 1) * p.jitter * float64(p.interval)
+	return p.interval + time.Duration(offset)
+}
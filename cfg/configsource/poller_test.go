@@ -0,0 +1,156 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package configsource
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockFetcher struct {
+	content string
+	version string
+	err     error
+}
+
+func (m *mockFetcher) Fetch(_ Location, version string) (*Result, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if version == m.version {
+		return &Result{Version: version, NotModified: true}, nil
+	}
+	return &Result{Content: []byte(m.content), Version: m.version}, nil
+}
+
+func TestPoller(t *testing.T) {
+	f := &mockFetcher{content: "v1", version: "1"}
+	p := newPoller(f, Location{Type: LocationSSM, Path: "param"}, time.Minute, 0)
+	var applied []string
+	var validated int
+	var versions []string
+	p.Current = func() []byte { return []byte("v1") }
+	p.Validate = func(content []byte) error {
+		validated++
+		if string(content) == "invalid" {
+			return errors.New("invalid config")
+		}
+		return nil
+	}
+	p.Apply = func(content []byte) error {
+		applied = append(applied, string(content))
+		return nil
+	}
+	p.OnVersion = func(version string) {
+		versions = append(versions, version)
+	}
+
+	// the config in use is the same, so it is only recorded
+	assert.False(t, p.Poll())
+	assert.Equal(t, "1", p.Version())
+	assert.Empty(t, applied)
+
+	assert.False(t, p.Poll())
+	assert.Equal(t, 0, validated)
+
+	f.content, f.version = "v2", "2"
+	assert.True(t, p.Poll())
+	assert.Equal(t, []string{"v2"}, applied)
+	assert.Equal(t, "2", p.Version())
+
+	// rejected versions are not applied or validated again
+	f.content, f.version = "invalid", "3"
+	assert.False(t, p.Poll())
+	assert.False(t, p.Poll())
+	assert.Equal(t, 2, validated)
+	assert.Equal(t, "2", p.Version())
+
+	f.err = errors.New("access denied")
+	assert.False(t, p.Poll())
+	assert.Equal(t, "2", p.Version())
+
+	f.err = nil
+	f.content, f.version = "v4", "4"
+	p.Apply = func([]byte) error { return errors.New("unable to write") }
+	assert.False(t, p.Poll())
+	assert.Equal(t, "2", p.Version())
+	assert.Equal(t, []string{"1", "1", "2", "2", "2", "2", "2"}, versions)
+}
+
+func TestPollerInterval(t *testing.T) {
+	p := newPoller(&mockFetcher{}, Location{}, time.Second, -1)
+	assert.Equal(t, MinPollInterval, p.interval)
+	assert.Equal(t, DefaultPollJitter, p.jitter)
+	for i := 0; i < 100; i++ {
+		next := p.nextInterval()
+		assert.GreaterOrEqual(t, next, 54*time.Second)
+		assert.LessOrEqual(t, next, 66*time.Second)
+	}
+	p = newPoller(&mockFetcher{}, Location{}, 10*time.Minute, 0)
+	assert.Equal(t, 10*time.Minute, p.nextInterval())
+}
+
+// locationFetcher returns the config and its signature by location path.
+type locationFetcher struct {
+	contents map[string]string
+	version  string
+}
+
+func (m *locationFetcher) Fetch(location Location, _ string) (*Result, error) {
+	content, ok := m.contents[location.Path]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &Result{Content: []byte(content), Version: m.version}, nil
+}
+
+func TestPollerVerification(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	publicKeyPath := filepath.Join(t.TempDir(), "config.pem")
+	require.NoError(t, os.WriteFile(publicKeyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+
+	f := &locationFetcher{contents: map[string]string{
+		"param":     "v1",
+		"signature": string(ed25519.Sign(privateKey, []byte("v1"))),
+	}, version: "1"}
+	p := newPoller(f, Location{Type: LocationSSM, Path: "param"}, time.Minute, 0)
+	p.Verification = Verification{SignatureLocation: "ssm:signature", PublicKeyPath: publicKeyPath}
+	var validated []string
+	p.Current = func() []byte { return nil }
+	p.Validate = func(content []byte) error {
+		validated = append(validated, string(content))
+		return nil
+	}
+	p.Apply = func([]byte) error { return nil }
+
+	assert.True(t, p.Poll())
+	assert.Equal(t, "1", p.Version())
+
+	// a version with a signature of other content is rejected before validation
+	f.contents["param"], f.version = "v2", "2"
+	assert.False(t, p.Poll())
+	assert.Equal(t, "1", p.Version())
+
+	// a version that does not match the checksum is rejected
+	p.Verification = Verification{Checksum: "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"}
+	f.version = "3"
+	assert.False(t, p.Poll())
+	f.contents["param"], f.version = "foo", "4"
+	assert.True(t, p.Poll())
+	assert.Equal(t, "4", p.Version())
+	assert.Equal(t, []string{"v1", "foo"}, validated)
+}
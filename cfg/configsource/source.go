@@ -0,0 +1,159 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package configsource
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	LocationDefault   = "default"
+	LocationSSM       = "ssm"
+	LocationFile      = "file"
+	LocationS3        = "s3"
+	LocationHTTPS     = "https"
+	LocationAppConfig = "appconfig"
+
+	locationSeparator = ":"
+
+	// httpsFileNameMaxLength is the maximum length of the host and path part of
+	// the file name of an https location.
+	httpsFileNameMaxLength = 64
+	// httpsFileNameHashLength is the number of hex digits of the url hash in
+	// the file name of an https location.
+	httpsFileNameHashLength = 16
+)
+
+// Location is a parsed download source, e.g. ssm:my-parameter or
+// s3://bucket/key.
+type Location struct {
+	Type string
+	// Path is the location without the type prefix. For s3 this is the bucket
+	// and key, for https the full URL and for appconfig the application,
+	// environment and configuration profile joined by /.
+	Path string
+}
+
+func (l Location) String() string {
+	switch l.Type {
+	case LocationDefault:
+		return LocationDefault
+	case LocationS3:
+		return "s3://" + l.Path
+	case LocationHTTPS:
+		return l.Path
+	}
+	return l.Type + locationSeparator + l.Path
+}
+
+// FileName returns the name the config from the location is saved as in the
+// json config dir.
+func (l Location) FileName() string {
+	switch l.Type {
+	case LocationDefault:
+		return LocationDefault
+	case LocationFile:
+		return LocationFile + "_" + escapeFilePath(filepath.Base(l.Path))
+	case LocationHTTPS:
+		return httpsFileName(l.Path)
+	}
+	return l.Type + "_" + escapeFilePath(l.Path)
+}
+
+// Bucket returns the bucket and key of an s3 location.
+func (l Location) Bucket() (string, string) {
+	bucket, key, _ := strings.Cut(l.Path, "/")
+	return bucket, key
+}
+
+// AppConfig returns the application, environment and configuration profile of
+// an appconfig location.
+func (l Location) AppConfig() (string, string, string) {
+	parts := strings.SplitN(l.Path, "/", 3)
+	for len(parts) < 3 {
+		parts = append(parts, "")
+	}
+	return parts[0], parts[1], parts[2]
+}
+
+// Parse parses the download source. Supported formats are:
+//
+//	default
+//	ssm:<parameter-store-name>
+//	file:<file-path>
+//	s3://<bucket>/<key>
+//	https://<host>/<path>
+//	appconfig:<application>/<environment>/<configuration-profile>
+func Parse(location string) (Location, error) {
+	if location == LocationDefault {
+		return Location{Type: LocationDefault}, nil
+	}
+	if strings.HasPrefix(location, "s3://") {
+		path := strings.TrimPrefix(location, "s3://")
+		bucket, key, _ := strings.Cut(path, "/")
+		if bucket == "" || key == "" {
+			return Location{}, fmt.Errorf("s3 location %s must be in the format s3://<bucket>/<key>", location)
+		}
+		return Location{Type: LocationS3, Path: path}, nil
+	}
+	if strings.HasPrefix(location, "http://") {
+		return Location{}, errors.New("http locations are not supported, use https instead")
+	}
+	if strings.HasPrefix(location, "https://") {
+		u, err := url.Parse(location)
+		if err != nil || u.Host == "" {
+			return Location{}, fmt.Errorf("https location %s is not a valid url", location)
+		}
+		return Location{Type: LocationHTTPS, Path: location}, nil
+	}
+	locationType, path, ok := strings.Cut(location, locationSeparator)
+	if !ok || path == "" {
+		return Location{}, fmt.Errorf("location %s is malformated", location)
+	}
+	switch locationType {
+	case LocationSSM, LocationFile:
+	case LocationAppConfig:
+		parts := strings.Split(path, "/")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return Location{}, fmt.Errorf("appconfig location %s must be in the format appconfig:<application>/<environment>/<configuration-profile>", location)
+		}
+	default:
+		return Location{}, fmt.Errorf("location type %s is not supported", locationType)
+	}
+	return Location{Type: locationType, Path: path}, nil
+}
+
+func escapeFilePath(filePath string) string {
+	escapedFilePath := filepath.ToSlash(filePath)
+	escapedFilePath = strings.Replace(escapedFilePath, "/", "_", -1)
+	escapedFilePath = strings.Replace(escapedFilePath, " ", "_", -1)
+	escapedFilePath = strings.Replace(escapedFilePath, ":", "_", -1)
+	return escapedFilePath
+}
+
+// httpsFileName names the file after the sanitized host and path of the url
+// and a hash of the full url. The query, which can hold the credentials of a
+// presigned url, is left out of the readable part.
+func httpsFileName(location string) string {
+	var name string
+	if u, err := url.Parse(location); err == nil {
+		name = strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+				return r
+			}
+			return '_'
+		}, u.Host+u.Path)
+	}
+	if len(name) > httpsFileNameMaxLength {
+		name = name[:httpsFileNameMaxLength]
+	}
+	sum := sha256.Sum256([]byte(location))
+	return LocationHTTPS + "_" + name + "_" + hex.EncodeToString(sum[:])[:httpsFileNameHashLength]
+}
@@ -0,0 +1,82 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package configsource
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	testCases := map[string]struct {
+		input   string
+		want    Location
+		wantErr string
+	}{
+		"WithDefault":   {input: "default", want: Location{Type: LocationDefault}},
+		"WithSSM":       {input: "ssm:AmazonCloudWatch-Config.json", want: Location{Type: LocationSSM, Path: "AmazonCloudWatch-Config.json"}},
+		"WithFile":      {input: "file:/tmp/config.json", want: Location{Type: LocationFile, Path: "/tmp/config.json"}},
+		"WithS3":        {input: "s3://bucket/path/config.json", want: Location{Type: LocationS3, Path: "bucket/path/config.json"}},
+		"WithHTTPS":     {input: "https://example.com/config.json", want: Location{Type: LocationHTTPS, Path: "https://example.com/config.json"}},
+		"WithAppConfig": {input: "appconfig:app/prod/agent", want: Location{Type: LocationAppConfig, Path: "app/prod/agent"}},
+		"WithS3NoKey":   {input: "s3://bucket", wantErr: "must be in the format s3://<bucket>/<key>"},
+		"WithHTTP":      {input: "http://example.com/config.json", wantErr: "use https instead"},
+		"WithAppConfigMissingProfile": {
+			input:   "appconfig:app/prod",
+			wantErr: "must be in the format appconfig:<application>/<environment>/<configuration-profile>",
+		},
+		"WithUnsupported": {input: "ftp:config.json", wantErr: "location type ftp is not supported"},
+		"WithMalformed":   {input: "ssm", wantErr: "location ssm is malformated"},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse(testCase.input)
+			if testCase.wantErr != "" {
+				assert.ErrorContains(t, err, testCase.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+			assert.Equal(t, testCase.input, got.String())
+		})
+	}
+}
+
+func TestLocationParts(t *testing.T) {
+	bucket, key := Location{Type: LocationS3, Path: "bucket/path/config.json"}.Bucket()
+	assert.Equal(t, "bucket", bucket)
+	assert.Equal(t, "path/config.json", key)
+	application, environment, profile := Location{Type: LocationAppConfig, Path: "app/prod/agent"}.AppConfig()
+	assert.Equal(t, []string{"app", "prod", "agent"}, []string{application, environment, profile})
+}
+
+func TestLocationFileName(t *testing.T) {
+	testCases := map[string]string{
+		"default":                         "default",
+		"ssm:AmazonCloudWatch-linux":      "ssm_AmazonCloudWatch-linux",
+		"file:/tmp/config dir/agent.json": "file_agent.json",
+		"s3://bucket/path/config.json":    "s3_bucket_path_config.json",
+		"https://example.com:8443/a.json": "https_example.com_8443_a.json_b92b8e6144236539",
+		"appconfig:app/prod/agent":        "appconfig_app_prod_agent",
+	}
+	for input, want := range testCases {
+		location, err := Parse(input)
+		assert.NoError(t, err)
+		assert.Equal(t, want, location.FileName(), input)
+	}
+}
+
+func TestLocationFileNamePresignedURL(t *testing.T) {
+	first, err := Parse("https://bucket.s3.amazonaws.com/" + strings.Repeat("dir/", 30) + "config.json?X-Amz-Credential=AKIA%2F20240101&X-Amz-Signature=abc*def")
+	assert.NoError(t, err)
+	second, err := Parse("https://bucket.s3.amazonaws.com/" + strings.Repeat("dir/", 30) + "config.json?X-Amz-Credential=AKIA%2F20240101&X-Amz-Signature=123*456")
+	assert.NoError(t, err)
+	name := first.FileName()
+	assert.Regexp(t, `^https_bucket\.s3\.amazonaws\.com_dir_[a-z_]+_[0-9a-f]{16}$`, name)
+	assert.LessOrEqual(t, len(name), len("https_")+httpsFileNameMaxLength+1+httpsFileNameHashLength)
+	assert.NotContains(t, name, "Signature")
+	assert.NotEqual(t, name, second.FileName())
+}
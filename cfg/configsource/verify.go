@@ -0,0 +1,163 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package configsource
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"strings"
+)
+
+var (
+	ErrChecksumMismatch = errors.New("checksum does not match")
+	ErrInvalidSignature = errors.New("signature verification failed")
+)
+
+// Verification is the checksum and the detached signature a config has to
+// match, set from the -checksum, -signature and -public-key options of the
+// config-downloader or from the agent.config_polling section. Polled configs
+// are only verified by signature, since a checksum matches a single version.
+type Verification struct {
+	// Checksum in the format <algorithm>:<hex>. Optional.
+	Checksum string
+	// SignatureLocation is the location of a detached signature of the
+	// config, in the same format as the config location. Optional.
+	SignatureLocation string
+	// PublicKeyPath is the path of the PEM encoded public key used to verify
+	// the signature.
+	PublicKeyPath string
+}
+
+// Verify checks the content against the checksum and the detached signature
+// if they are configured.
+func (v Verification) Verify(fetcher *Fetcher, content []byte) error {
+	return v.verify(fetcher, content)
+}
+
+func (v Verification) verify(fetcher fetcher, content []byte) error {
+	if v.Checksum != "" {
+		if err := VerifyChecksum(content, v.Checksum); err != nil {
+			return fmt.Errorf("checksum verification failed: %w", err)
+		}
+	}
+	if v.SignatureLocation == "" {
+		return nil
+	}
+	if v.PublicKeyPath == "" {
+		return fmt.Errorf("public-key is required to verify the signature")
+	}
+	location, err := Parse(v.SignatureLocation)
+	if err != nil {
+		return fmt.Errorf("invalid signature location: %w", err)
+	}
+	signature, err := fetcher.Fetch(location, "")
+	if err != nil {
+		return fmt.Errorf("unable to fetch signature: %w", err)
+	}
+	publicKey, err := os.ReadFile(v.PublicKeyPath)
+	if err != nil {
+		return fmt.Errorf("unable to read public key: %w", err)
+	}
+	return VerifySignature(content, signature.Content, publicKey)
+}
+
+// VerifyChecksum checks the content against a checksum in the format
+// <algorithm>:<hex>, e.g. sha256:2c26b46b... The supported algorithms are
+// sha256 and sha512.
+func VerifyChecksum(content []byte, checksum string) error {
+	algorithm, expected, ok := strings.Cut(strings.TrimSpace(checksum), ":")
+	if !ok {
+		return fmt.Errorf("checksum %q must be in the format <algorithm>:<hex>", checksum)
+	}
+	var h hash.Hash
+	switch strings.ToLower(algorithm) {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf("checksum algorithm %s is not supported", algorithm)
+	}
+	want, err := hex.DecodeString(expected)
+	if err != nil {
+		return fmt.Errorf("checksum %q is not hex encoded: %w", checksum, err)
+	}
+	h.Write(content)
+	if subtle.ConstantTimeCompare(h.Sum(nil), want) != 1 {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
+
+// VerifySignature checks a detached signature of the content with a PEM
+// encoded public key. RSA (PKCS #1 v1.5), ECDSA (ASN.1) and Ed25519 keys are
+// supported. RSA and ECDSA signatures are over the SHA-256 digest of the
+// content. The signature can either be raw or base64 encoded.
+func VerifySignature(content, signature, publicKeyPEM []byte) error {
+	publicKey, err := parsePublicKey(publicKeyPEM)
+	if err != nil {
+		return err
+	}
+	signature = decodeSignature(signature)
+	digest := sha256.Sum256(content)
+	var valid bool
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(key, digest[:], signature)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, content, signature)
+	default:
+		return fmt.Errorf("public key type %T is not supported", publicKey)
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func parsePublicKey(publicKeyPEM []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(publicKeyPEM)
+	if block == nil {
+		return nil, errors.New("unable to decode public key, expected PEM format")
+	}
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	}
+	return nil, fmt.Errorf("PEM block type %s is not supported", block.Type)
+}
+
+func decodeSignature(signature []byte) []byte {
+	trimmed := bytes.TrimSpace(signature)
+	decoded := make([]byte, base64.StdEncoding.DecodedLen(len(trimmed)))
+	n, err := base64.StdEncoding.Decode(decoded, trimmed)
+	if err != nil {
+		return signature
+	}
+	return decoded[:n]
+}
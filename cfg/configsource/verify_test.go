@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package configsource
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyChecksum(t *testing.T) {
+	content := []byte("foo")
+	assert.NoError(t, VerifyChecksum(content, "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"))
+	assert.NoError(t, VerifyChecksum(content, "SHA512:f7fbba6e0636f890e56fbbf3283e524c6fa3204ae298382d624741d0dc6638326e282c41be5e4254d8820772c5518a2c5a8c0c7f7eda19594a7eb539453e1ed7"))
+	assert.ErrorIs(t, VerifyChecksum([]byte("bar"), "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"), ErrChecksumMismatch)
+	assert.ErrorContains(t, VerifyChecksum(content, "2c26b46b"), "must be in the format <algorithm>:<hex>")
+	assert.ErrorContains(t, VerifyChecksum(content, "md5:acbd18db4cc2f85cedef654fccc4a4d8"), "algorithm md5 is not supported")
+	assert.ErrorContains(t, VerifyChecksum(content, "sha256:xyz"), "is not hex encoded")
+}
+
+func TestVerifySignature(t *testing.T) {
+	content := []byte(`{"agent":{"region":"us-east-1"}}`)
+	digest := sha256.Sum256(content)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	rsaSignature, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecdsaSignature, err := ecdsa.SignASN1(rand.Reader, ecdsaKey, digest[:])
+	require.NoError(t, err)
+	ed25519Public, ed25519Private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		publicKey crypto.PublicKey
+		signature []byte
+	}{
+		"WithRSA":           {publicKey: &rsaKey.PublicKey, signature: rsaSignature},
+		"WithECDSA":         {publicKey: &ecdsaKey.PublicKey, signature: ecdsaSignature},
+		"WithEd25519":       {publicKey: ed25519Public, signature: ed25519.Sign(ed25519Private, content)},
+		"WithBase64Ed25519": {publicKey: ed25519Public, signature: []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(ed25519Private, content)) + "\n")},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			der, err := x509.MarshalPKIXPublicKey(testCase.publicKey)
+			require.NoError(t, err)
+			publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+			assert.NoError(t, VerifySignature(content, testCase.signature, publicKeyPEM))
+			assert.ErrorIs(t, VerifySignature([]byte("tampered"), testCase.signature, publicKeyPEM), ErrInvalidSignature)
+		})
+	}
+
+	assert.ErrorContains(t, VerifySignature(content, rsaSignature, []byte("not a key")), "expected PEM format")
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
 	"github.com/aws/amazon-cloudwatch-agent/cmd/amazon-cloudwatch-agent/internal"
 	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/useragent"
+	"github.com/aws/amazon-cloudwatch-agent/internal/status"
 	"github.com/aws/amazon-cloudwatch-agent/internal/version"
 	cwaLogger "github.com/aws/amazon-cloudwatch-agent/logger"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
@@ -57,6 +58,8 @@ var fDebug = flag.Bool("debug", false,
 	"turn on debug logging")
 var pprofAddr = flag.String("pprof-addr", "",
 	"pprof address to listen on, disabled by default, examples: 'localhost:1234', ':4567' (restricted to localhost)")
+var statusAddr = flag.String("status-addr", "",
+	"status API address to listen on, disabled by default, examples: 'localhost:1234', ':4567' (restricted to localhost)")
 var fQuiet = flag.Bool("quiet", false,
 	"run in quiet mode")
 var fTest = flag.Bool("test", false, "enable test mode: gather metrics, print them out, and exit")
@@ -109,22 +112,10 @@ func reloadLoop(
 
 		ctx, cancel := context.WithCancel(context.Background())
 
-		signals := make(chan os.Signal)
+		signals := make(chan os.Signal, 1)
 		signal.Notify(signals, os.Interrupt, syscall.SIGHUP,
 			syscall.SIGTERM, syscall.SIGINT)
-		go func() {
-			select {
-			case sig := <-signals:
-				if sig == syscall.SIGHUP {
-					log.Println("I! Reloading Telegraf config")
-					<-reload
-					reload <- true
-				}
-				cancel()
-			case <-stop:
-				cancel()
-			}
-		}()
+		go watchReload(signals, reload, stop, cancel)
 
 		go func(ctx context.Context) {
 			profilerTicker := time.NewTicker(60 * time.Second)
@@ -190,6 +181,38 @@ func reloadLoop(
 	}
 }
 
+// watchReload cancels the running agent when it is stopped or its config is
+// reloaded, in which case reload is set for the reload loop to start the
+// agent again.
+func watchReload(signals <-chan os.Signal, reload chan bool, stop <-chan struct{}, cancel context.CancelFunc) {
+	select {
+	case sig := <-signals:
+		if sig == syscall.SIGHUP {
+			log.Println("I! Reloading Telegraf config")
+			<-reload
+			reload <- true
+		}
+		cancel()
+	case <-reloadAgent:
+		log.Println("I! Reloading config applied by config polling")
+		<-reload
+		reload <- true
+		cancel()
+	case <-stop:
+		cancel()
+	}
+}
+
+// configPollEnvVars are the env vars set by the config-translator from the
+// agent.config_polling section of the json config.
+var configPollEnvVars = []string{
+	envconfig.CWAGENT_CONFIG_POLL_LOCATION,
+	envconfig.CWAGENT_CONFIG_POLL_INTERVAL,
+	envconfig.CWAGENT_CONFIG_POLL_MODE,
+	envconfig.CWAGENT_CONFIG_POLL_SIGNATURE,
+	envconfig.CWAGENT_CONFIG_POLL_PUBLIC_KEY,
+}
+
 // loadEnvironmentVariables updates OS ENV vars with key/val from the given JSON file.
 // The "config-translator" program populates that file.
 func loadEnvironmentVariables(path string) error {
@@ -207,6 +230,10 @@ func loadEnvironmentVariables(path string) error {
 		return fmt.Errorf("cannot create env config due to: %s", err.Error())
 	}
 
+	// the config polling variables are only set while the config enables it
+	for _, key := range configPollEnvVars {
+		os.Unsetenv(key)
+	}
 	for key, val := range envVars {
 		os.Setenv(key, val)
 		log.Printf("I! %s is set to \"%s\"\n", key, val)
@@ -308,6 +335,8 @@ func runAgent(ctx context.Context,
 		}
 	}
 
+	startConfigPoller(ctx, envConfigPath)
+
 	if len(c.Inputs) != 0 && len(c.Outputs) != 0 {
 		log.Println("creating new logs agent")
 		logAgent := logs.NewLogAgent(c)
@@ -347,6 +376,12 @@ func runAgent(ctx context.Context,
 	params := getCollectorParams(factories, provider, writer)
 
 	_ = featuregate.GlobalRegistry().Set("exporter.xray.allowDot", true)
+	return runCollector(ctx, params, yamlConfigPath)
+}
+
+// runCollector runs the OTEL collector until the context is done, so that a
+// reload restarts the collector with the new config.
+func runCollector(ctx context.Context, params otelcol.CollectorSettings, yamlConfigPath string) error {
 	cmd := otelcol.NewCommand(params)
 
 	// Noticed that args of parent process get passed here to otel collector which causes failures complaining about
@@ -355,7 +390,7 @@ func runAgent(ctx context.Context,
 	e := []string{"--config=" + yamlConfigPath + " --feature-gates=exporter.xray.allowDot"}
 	cmd.SetArgs(e)
 
-	return cmd.Execute()
+	return cmd.ExecuteContext(ctx)
 }
 
 func getCollectorParams(factories otelcol.Factories, provider otelcol.ConfigProvider, writer io.Writer) otelcol.CollectorSettings {
@@ -469,6 +504,22 @@ func main() {
 		}()
 	}
 
+	if *statusAddr != "" {
+		go func() {
+			addr, err := statusListenAddr(*statusAddr)
+			if err != nil {
+				log.Printf("W! Not starting status API: %v", err)
+				return
+			}
+
+			log.Printf("I! Starting status API HTTP server at: http://%s%s\n", addr, status.Path)
+
+			if err := http.ListenAndServe(addr, status.NewHandler()); err != nil {
+				log.Printf("E! Status API HTTP server stopped: %v", err)
+			}
+		}()
+	}
+
 	if len(args) > 0 {
 		switch args[0] {
 		case "version":
@@ -5,15 +5,19 @@ package main
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-test/deep"
 	"github.com/influxdata/wlog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/otelcol"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
 	"github.com/aws/amazon-cloudwatch-agent/logger"
 )
 
@@ -58,3 +62,22 @@ func Test_getCollectorParams(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadEnvironmentVariablesClearsConfigPolling(t *testing.T) {
+	t.Setenv(envconfig.CWAGENT_CONFIG_POLL_LOCATION, "")
+	t.Setenv(envconfig.CWAGENT_CONFIG_POLL_INTERVAL, "")
+	t.Setenv(envconfig.CWAGENT_LOG_LEVEL, "")
+	envConfig := filepath.Join(t.TempDir(), "env-config.json")
+	require.NoError(t, os.WriteFile(envConfig, []byte(`{"CWAGENT_CONFIG_POLL_LOCATION":"ssm:param","CWAGENT_CONFIG_POLL_INTERVAL":"600"}`), 0644))
+	require.NoError(t, loadEnvironmentVariables(envConfig))
+	assert.Equal(t, "ssm:param", os.Getenv(envconfig.CWAGENT_CONFIG_POLL_LOCATION))
+
+	// a config without config_polling stops the polling
+	require.NoError(t, os.WriteFile(envConfig, []byte(`{"CWAGENT_LOG_LEVEL":"DEBUG"}`), 0644))
+	require.NoError(t, loadEnvironmentVariables(envConfig))
+	_, ok := os.LookupEnv(envconfig.CWAGENT_CONFIG_POLL_LOCATION)
+	assert.False(t, ok)
+	_, ok = os.LookupEnv(envconfig.CWAGENT_CONFIG_POLL_INTERVAL)
+	assert.False(t, ok)
+	assert.Equal(t, "DEBUG", os.Getenv(envconfig.CWAGENT_LOG_LEVEL))
+}
@@ -0,0 +1,259 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/commonconfig"
+	"github.com/aws/amazon-cloudwatch-agent/cfg/configsource"
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/provider"
+	"github.com/aws/amazon-cloudwatch-agent/internal/status"
+	"github.com/aws/amazon-cloudwatch-agent/tool/paths"
+	translatorcontext "github.com/aws/amazon-cloudwatch-agent/translator/context"
+	"github.com/aws/amazon-cloudwatch-agent/translator/util"
+)
+
+// reloadAgent triggers a reload of the agent in the same way as a SIGHUP.
+var reloadAgent = make(chan struct{}, 1)
+
+// The poller is kept across reloads so that the version of the config in use
+// is not lost when the agent reloads after applying it.
+var (
+	configPollerMu  sync.Mutex
+	configPoller    *configsource.Poller
+	configPollerKey string
+)
+
+// startConfigPoller polls the location from the agent.config_polling section
+// of the json config until the context is done.
+func startConfigPoller(ctx context.Context, envConfigPath string) {
+	location := os.Getenv(envconfig.CWAGENT_CONFIG_POLL_LOCATION)
+	if location == "" {
+		return
+	}
+	if envconfig.IsRunningInContainer() {
+		log.Printf("W! Config polling is not supported when running in a container")
+		return
+	}
+	interval := configsource.DefaultPollInterval
+	if value := os.Getenv(envconfig.CWAGENT_CONFIG_POLL_INTERVAL); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			log.Printf("W! Invalid config poll interval %q, using %v: %v", value, interval, err)
+		} else {
+			interval = time.Duration(seconds) * time.Second
+		}
+	}
+	// the mode and the verification of the translation that enabled polling
+	mode := os.Getenv(envconfig.CWAGENT_CONFIG_POLL_MODE)
+	if mode == "" {
+		mode = "auto"
+	}
+	verification := configsource.Verification{
+		SignatureLocation: os.Getenv(envconfig.CWAGENT_CONFIG_POLL_SIGNATURE),
+		PublicKeyPath:     os.Getenv(envconfig.CWAGENT_CONFIG_POLL_PUBLIC_KEY),
+	}
+	poller, err := getConfigPoller(location, interval, mode, verification, envConfigPath)
+	if err != nil {
+		log.Printf("E! Unable to poll config at %s: %v", location, err)
+		return
+	}
+	go poller.Run(ctx)
+}
+
+func getConfigPoller(location string, interval time.Duration, mode string, verification configsource.Verification, envConfigPath string) (*configsource.Poller, error) {
+	configPollerMu.Lock()
+	defer configPollerMu.Unlock()
+	key := fmt.Sprintf("%s|%v|%s|%+v|%s", location, interval, mode, verification, envConfigPath)
+	if configPoller != nil && configPollerKey == key {
+		return configPoller, nil
+	}
+	parsed, err := configsource.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	cc := commonconfig.New()
+	if f, err := os.Open(paths.CommonConfigPath); err == nil {
+		err = cc.Parse(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse common config: %w", err)
+		}
+	}
+	agentMode := util.DetectAgentMode(mode)
+	region, _ := util.DetectRegion(agentMode, cc.CredentialsMap())
+	applier := &configApplier{
+		target:        filepath.Join(paths.JsonDirPath, parsed.FileName()),
+		mode:          mode,
+		tomlConfig:    *fTomlConfig,
+		yamlConfig:    *fOtelConfig,
+		envConfig:     envConfigPath,
+		translatorBin: paths.TranslatorBinaryPath,
+	}
+	poller := configsource.NewPoller(configsource.NewFetcher(region, agentMode, cc.CredentialsMap()), parsed, interval, configsource.DefaultPollJitter)
+	poller.Verification = verification
+	poller.Current = applier.current
+	poller.Validate = applier.validate
+	poller.Apply = applier.apply
+	poller.OnVersion = func(version string) {
+		provider.SetConfigVersion(version)
+		status.SetConfigVersion(version)
+	}
+	configPoller, configPollerKey = poller, key
+	return poller, nil
+}
+
+// configApplier replaces a json config in the config dir. A new config is
+// only applied if the config-translator accepts it along with the other json
+// configs, in which case the translated configs are installed and the agent
+// is reloaded.
+type configApplier struct {
+	target string
+	// mode is the --mode of the config-translator.
+	mode          string
+	tomlConfig    string
+	yamlConfig    string
+	envConfig     string
+	translatorBin string
+
+	// staged is the dir with the output of the last successful validation.
+	staged string
+}
+
+func (a *configApplier) current() []byte {
+	content, _ := os.ReadFile(a.target)
+	return content
+}
+
+// validate translates the json configs with the new content into a staging
+// dir without touching the configs in use.
+func (a *configApplier) validate(content []byte) error {
+	a.cleanup()
+	staging, err := os.MkdirTemp("", "cwagent-config-")
+	if err != nil {
+		return err
+	}
+	inputDir := filepath.Join(staging, "json")
+	if err = stageJsonConfigs(filepath.Dir(a.target), inputDir, filepath.Base(a.target), content); err != nil {
+		os.RemoveAll(staging)
+		return err
+	}
+	args := []string{
+		"--input", paths.JsonConfigPath,
+		"--input-dir", inputDir,
+		"--output", filepath.Join(staging, paths.TOML),
+		"--mode", a.mode,
+		"--config", paths.CommonConfigPath,
+	}
+	output, err := exec.Command(a.translatorBin, args...).CombinedOutput()
+	if err != nil {
+		os.RemoveAll(staging)
+		return fmt.Errorf("config-translator rejected the config: %w: %s", err, lastLines(output, 5))
+	}
+	a.staged = staging
+	return nil
+}
+
+// apply installs the json config and the staged translated configs, then
+// reloads the agent.
+func (a *configApplier) apply(content []byte) error {
+	if a.staged == "" {
+		return errors.New("config has not been validated")
+	}
+	defer a.cleanup()
+	if err := writeFileAtomic(a.target, content); err != nil {
+		return err
+	}
+	installs := map[string]string{
+		filepath.Join(a.staged, paths.TOML): a.tomlConfig,
+		filepath.Join(a.staged, paths.YAML): a.yamlConfig,
+		filepath.Join(a.staged, paths.ENV):  a.envConfig,
+	}
+	for src, dst := range installs {
+		if dst == "" {
+			continue
+		}
+		staged, err := os.ReadFile(src)
+		if errors.Is(err, os.ErrNotExist) {
+			// the translator does not write the YAML if there are no pipelines
+			if err = os.Remove(dst); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err = writeFileAtomic(dst, staged); err != nil {
+			return err
+		}
+	}
+	select {
+	case reloadAgent <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (a *configApplier) cleanup() {
+	if a.staged != "" {
+		os.RemoveAll(a.staged)
+		a.staged = ""
+	}
+}
+
+// stageJsonConfigs copies the json configs the translator reads from the
+// config dir, replacing the one with the target name.
+func stageJsonConfigs(configDir, stagingDir, target string, content []byte) error {
+	if err := os.MkdirAll(stagingDir, 0755); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(configDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || name == target || filepath.Ext(name) == translatorcontext.TmpFileSuffix {
+			continue
+		}
+		existing, err := os.ReadFile(filepath.Join(configDir, name))
+		if err != nil {
+			return err
+		}
+		if err = os.WriteFile(filepath.Join(stagingDir, name), existing, 0644); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(filepath.Join(stagingDir, target), content, 0644)
+}
+
+func writeFileAtomic(path string, content []byte) error {
+	tmp := path + ".new"
+	if err := os.WriteFile(tmp, content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func lastLines(output []byte, n int) string {
+	lines := strings.Split(string(bytes.TrimSpace(output)), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "; ")
+}
@@ -0,0 +1,145 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/otelcol"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/aws/amazon-cloudwatch-agent/service/configprovider"
+	"github.com/aws/amazon-cloudwatch-agent/tool/paths"
+)
+
+func TestStageJsonConfigs(t *testing.T) {
+	configDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "file_other.json"), []byte(`{"logs":{}}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "ssm_param"), []byte(`{"old":true}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(configDir, "ssm_pending.tmp"), []byte(`{}`), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(configDir, "subdir"), 0755))
+
+	stagingDir := filepath.Join(t.TempDir(), "json")
+	require.NoError(t, stageJsonConfigs(configDir, stagingDir, "ssm_param", []byte(`{"new":true}`)))
+	entries, err := os.ReadDir(stagingDir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"file_other.json", "ssm_param"}, names)
+	got, err := os.ReadFile(filepath.Join(stagingDir, "ssm_param"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"new":true}`, string(got))
+}
+
+func TestConfigApplierApply(t *testing.T) {
+	configDir := t.TempDir()
+	etcDir := t.TempDir()
+	staged := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(staged, paths.TOML), []byte("toml"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(staged, paths.ENV), []byte("{}"), 0644))
+	yamlConfig := filepath.Join(etcDir, paths.YAML)
+	require.NoError(t, os.WriteFile(yamlConfig, []byte("old yaml"), 0644))
+
+	a := &configApplier{
+		target:     filepath.Join(configDir, "ssm_param"),
+		tomlConfig: filepath.Join(etcDir, paths.TOML),
+		yamlConfig: yamlConfig,
+		envConfig:  filepath.Join(etcDir, paths.ENV),
+	}
+	assert.Error(t, a.apply([]byte(`{}`)))
+
+	a.staged = staged
+	require.NoError(t, a.apply([]byte(`{"agent":{}}`)))
+	assert.Equal(t, `{"agent":{}}`, string(a.current()))
+	got, err := os.ReadFile(a.tomlConfig)
+	require.NoError(t, err)
+	assert.Equal(t, "toml", string(got))
+	assert.NoFileExists(t, yamlConfig)
+	assert.FileExists(t, a.envConfig)
+	assert.NoDirExists(t, staged)
+	assert.Empty(t, a.staged)
+	select {
+	case <-reloadAgent:
+	default:
+		t.Fatal("expected the agent to be reloaded")
+	}
+}
+
+// TestConfigApplierApplyStopsCollector applies a polled config while the OTEL
+// collector is running, which must stop the collector so that the reload loop
+// starts the agent with the new config.
+func TestConfigApplierApplyStopsCollector(t *testing.T) {
+	yamlConfig := filepath.Join(t.TempDir(), paths.YAML)
+	require.NoError(t, os.WriteFile(yamlConfig, []byte(`receivers:
+  nop:
+exporters:
+  nop:
+service:
+  telemetry:
+    metrics:
+      level: none
+  pipelines:
+    metrics:
+      receivers: [nop]
+      exporters: [nop]
+`), 0644))
+	provider, err := configprovider.Get(yamlConfig)
+	require.NoError(t, err)
+	receivers, err := receiver.MakeFactoryMap(receivertest.NewNopFactory())
+	require.NoError(t, err)
+	exporters, err := exporter.MakeFactoryMap(exportertest.NewNopFactory())
+	require.NoError(t, err)
+	factories := otelcol.Factories{Receivers: receivers, Exporters: exporters}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reload := make(chan bool, 1)
+	reload <- false
+	go watchReload(make(chan os.Signal), reload, make(chan struct{}), cancel)
+	done := make(chan error, 1)
+	go func() {
+		done <- runCollector(ctx, getCollectorParams(factories, provider, io.Discard), yamlConfig)
+	}()
+
+	// let the collector start its pipelines
+	select {
+	case err = <-done:
+		t.Fatalf("the collector stopped before the config was applied: %v", err)
+	case <-time.After(2 * time.Second):
+	}
+
+	staged := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(staged, paths.TOML), []byte("toml"), 0644))
+	a := &configApplier{
+		target:     filepath.Join(t.TempDir(), "ssm_param"),
+		tomlConfig: filepath.Join(t.TempDir(), paths.TOML),
+		staged:     staged,
+	}
+	require.NoError(t, a.apply([]byte(`{"agent":{}}`)))
+
+	select {
+	case err = <-done:
+		assert.NoError(t, err)
+	case <-time.After(30 * time.Second):
+		t.Fatal("expected the collector to stop after the config was applied")
+	}
+	assert.True(t, <-reload)
+}
+
+func TestLastLines(t *testing.T) {
+	assert.Equal(t, "c; d", lastLines([]byte("a\nb\nc\nd\n"), 2))
+	assert.Equal(t, "a", lastLines([]byte("a"), 2))
+}
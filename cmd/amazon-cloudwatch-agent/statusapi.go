@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"net"
+)
+
+// statusListenAddr returns the address for the status API to listen on. The
+// API is restricted to localhost, so an address without a host listens on
+// the loopback interface instead of all interfaces.
+func statusListenAddr(addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	switch host {
+	case "", "localhost":
+		return net.JoinHostPort("localhost", port), nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return addr, nil
+	}
+	return "", fmt.Errorf("address %s is not restricted to localhost", addr)
+}
@@ -0,0 +1,37 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusListenAddr(t *testing.T) {
+	testCases := map[string]struct {
+		addr    string
+		want    string
+		wantErr bool
+	}{
+		"PortOnly":      {addr: ":4567", want: "localhost:4567"},
+		"Localhost":     {addr: "localhost:1234", want: "localhost:1234"},
+		"Loopback":      {addr: "127.0.0.1:1234", want: "127.0.0.1:1234"},
+		"LoopbackV6":    {addr: "[::1]:1234", want: "[::1]:1234"},
+		"AllInterfaces": {addr: "0.0.0.0:1234", wantErr: true},
+		"Remote":        {addr: "example.com:1234", wantErr: true},
+		"NoPort":        {addr: "localhost", wantErr: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := statusListenAddr(testCase.addr)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
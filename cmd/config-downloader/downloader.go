@@ -11,13 +11,8 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/ssm"
-
-	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
 	commonconfig "github.com/aws/amazon-cloudwatch-agent/cfg/commonconfig"
+	"github.com/aws/amazon-cloudwatch-agent/cfg/configsource"
 	"github.com/aws/amazon-cloudwatch-agent/translator/config"
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
 	"github.com/aws/amazon-cloudwatch-agent/translator/util"
@@ -25,69 +20,58 @@ import (
 )
 
 const (
-	locationDefault = "default"
-	locationSSM     = "ssm"
-	locationFile    = "file"
-
-	locationSeparator = ":"
-
 	exitErrorMessage = "Fail to fetch the config!"
-)
 
-func defaultJsonConfig(mode string) (string, error) {
-	return config.DefaultJsonConfig(config.ToValidOs(""), mode), nil
-}
+	cacheDirName       = "config-downloader-cache"
+	cacheVersionSuffix = ".version"
+)
 
-func downloadFromSSM(region, parameterStoreName, mode string, credsConfig map[string]string) (string, error) {
-	fmt.Printf("Region: %v\n", region)
-	fmt.Printf("credsConfig: %v\n", credsConfig)
-	var ses *session.Session
-	credsMap := util.GetCredentials(mode, credsConfig)
-	profile, profileOk := credsMap[commonconfig.CredentialProfile]
-	sharedConfigFile, sharedConfigFileOk := credsMap[commonconfig.CredentialFile]
-	rootconfig := &aws.Config{
-		Region:   aws.String(region),
-		LogLevel: configaws.SDKLogLevel(),
-		Logger:   configaws.SDKLogger{},
-	}
-	if profileOk || sharedConfigFileOk {
-		rootconfig.Credentials = credentials.NewCredentials(&credentials.SharedCredentialsProvider{
-			Filename: sharedConfigFile,
-			Profile:  profile,
-		})
+// fetchWithCache downloads the config and keeps a copy along with its version
+// in the cache dir. If the location reports the cached version is still
+// current, e.g. the S3 ETag did not change, the cached copy is used.
+func fetchWithCache(fetcher *configsource.Fetcher, location configsource.Location, cachePath string) ([]byte, error) {
+	var version string
+	cached, err := os.ReadFile(cachePath)
+	if err == nil {
+		if b, err := os.ReadFile(cachePath + cacheVersionSuffix); err == nil {
+			version = strings.TrimSpace(string(b))
+		}
 	}
-
-	ses, err := session.NewSession(rootconfig)
+	result, err := fetcher.Fetch(location, version)
 	if err != nil {
-		fmt.Printf("Error in creating session: %v\n", err)
-		return "", err
+		return nil, err
 	}
-
-	ssmClient := ssm.New(ses)
-	input := ssm.GetParameterInput{
-		Name:           aws.String(parameterStoreName),
-		WithDecryption: aws.Bool(true),
+	if result.NotModified {
+		fmt.Printf("Config at %s is unchanged (version %s), using the cached copy\n", location, version)
+		return cached, nil
+	}
+	if err = os.MkdirAll(filepath.Dir(cachePath), 0755); err == nil {
+		err = os.WriteFile(cachePath, result.Content, 0644)
+	}
+	if err == nil {
+		err = os.WriteFile(cachePath+cacheVersionSuffix, []byte(result.Version), 0644)
 	}
-	output, err := ssmClient.GetParameter(&input)
 	if err != nil {
-		fmt.Printf("Error in retrieving parameter store content: %v\n", err)
-		return "", err
+		fmt.Printf("Unable to cache the config in %s: %v\n", cachePath, err)
 	}
-
-	return *output.Parameter.Value, nil
+	return result.Content, nil
 }
 
-func readFromFile(filePath string) (string, error) {
-	bytes, err := os.ReadFile(filePath)
-	return string(bytes), err
+func removeFromCache(cachePath string) {
+	for _, path := range []string{cachePath, cachePath + cacheVersionSuffix} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			fmt.Printf("Unable to remove cached config %s: %v\n", path, err)
+		}
+	}
 }
 
-func EscapeFilePath(filePath string) (escapedFilePath string) {
-	escapedFilePath = filepath.ToSlash(filePath)
-	escapedFilePath = strings.Replace(escapedFilePath, "/", "_", -1)
-	escapedFilePath = strings.Replace(escapedFilePath, " ", "_", -1)
-	escapedFilePath = strings.Replace(escapedFilePath, ":", "_", -1)
-	return
+// requiresRegion returns whether the location is fetched with an AWS client.
+func requiresRegion(location configsource.Location) bool {
+	switch location.Type {
+	case configsource.LocationDefault, configsource.LocationFile, configsource.LocationHTTPS:
+		return false
+	}
+	return true
 }
 
 /**
@@ -108,13 +92,19 @@ func main() {
 	}()
 
 	var region, mode, downloadLocation, outputDir, inputConfig, multiConfig string
+	var cacheDir, checksum, signatureLocation, publicKeyPath string
 
 	flag.StringVar(&mode, "mode", "ec2", "Please provide the mode, i.e. ec2, onPremise, onPrem, auto")
 	flag.StringVar(&downloadLocation, "download-source", "",
-		"Download source. Example: \"ssm:my-parameter-store-name\" for an EC2 SSM Parameter Store Name holding your CloudWatch Agent configuration.")
+		"Download source. Example: \"ssm:my-parameter-store-name\" for an EC2 SSM Parameter Store Name holding your CloudWatch Agent configuration. "+
+			"Supported sources are default, ssm:<parameter-store-name>, file:<file-path>, s3://<bucket>/<key>, https://<url> and appconfig:<application>/<environment>/<configuration-profile>.")
 	flag.StringVar(&outputDir, "output-dir", "", "Path of output json config directory.")
 	flag.StringVar(&inputConfig, "config", "", "Please provide the common-config file")
 	flag.StringVar(&multiConfig, "multi-config", "default", "valid values: default, append, remove")
+	flag.StringVar(&cacheDir, "cache-dir", "", "Path of the directory used to cache configs downloaded from s3 and https sources. Defaults to a directory next to the output dir.")
+	flag.StringVar(&checksum, "checksum", "", "Expected checksum of the config in the format <algorithm>:<hex>, e.g. sha256:<hex>. Supported algorithms are sha256 and sha512.")
+	flag.StringVar(&signatureLocation, "signature", "", "Download source of a detached signature of the config, in the same format as download-source.")
+	flag.StringVar(&publicKeyPath, "public-key", "", "Path of the PEM encoded public key used to verify the signature.")
 	flag.Parse()
 
 	cc := commonconfig.New()
@@ -143,9 +133,17 @@ func main() {
 
 	mode = sdkutil.DetectAgentMode(mode)
 
+	location, err := configsource.Parse(downloadLocation)
+	if err != nil {
+		log.Panicf("E! downloadLocation %s is invalid: %v", downloadLocation, err)
+	}
+	if cacheDir == "" {
+		cacheDir = filepath.Join(filepath.Dir(filepath.Clean(outputDir)), cacheDirName)
+	}
+
 	region, _ = util.DetectRegion(mode, cc.CredentialsMap())
 
-	if region == "" && downloadLocation != locationDefault {
+	if region == "" && requiresRegion(location) {
 		fmt.Println("Unable to determine aws-region.")
 		if mode == config.ModeEC2 {
 			errorMessage = "E! Please check if you can access the metadata service. For example, on linux, run 'wget -q -O - http://169.254.169.254/latest/meta-data/instance-id && echo' "
@@ -179,31 +177,26 @@ func main() {
 			return nil
 		})
 
-	locationArray := strings.SplitN(downloadLocation, locationSeparator, 2)
-	if locationArray == nil || len(locationArray) < 2 && downloadLocation != locationDefault {
-		log.Panicf("E! downloadLocation %s is malformated.", downloadLocation)
-	}
-
-	var config, outputFilePath string
-	var err error
-	switch locationArray[0] {
-	case locationDefault:
-		outputFilePath = locationDefault
-		if multiConfig != "remove" {
-			config, err = defaultJsonConfig(mode)
-		}
-	case locationSSM:
-		outputFilePath = locationSSM + "_" + EscapeFilePath(locationArray[1])
-		if multiConfig != "remove" {
-			config, err = downloadFromSSM(region, locationArray[1], mode, cc.CredentialsMap())
+	var config []byte
+	outputFilePath := location.FileName()
+	cachePath := filepath.Join(cacheDir, outputFilePath)
+	if multiConfig != "remove" {
+		fetcher := configsource.NewFetcher(region, mode, cc.CredentialsMap())
+		switch location.Type {
+		case configsource.LocationS3, configsource.LocationHTTPS:
+			config, err = fetchWithCache(fetcher, location, cachePath)
+		default:
+			var result *configsource.Result
+			if result, err = fetcher.Fetch(location, ""); err == nil {
+				config = result.Content
+			}
 		}
-	case locationFile:
-		outputFilePath = locationFile + "_" + EscapeFilePath(filepath.Base(locationArray[1]))
-		if multiConfig != "remove" {
-			config, err = readFromFile(locationArray[1])
+		if err == nil {
+			verification := configsource.Verification{Checksum: checksum, SignatureLocation: signatureLocation, PublicKeyPath: publicKeyPath}
+			err = verification.Verify(fetcher, config)
 		}
-	default:
-		log.Panicf("E! Location type %s is not supported.", locationArray[0])
+	} else {
+		removeFromCache(cachePath)
 	}
 
 	if err != nil {
@@ -212,7 +205,7 @@ func main() {
 
 	if multiConfig != "remove" {
 		outputFilePath = filepath.Join(outputDir, outputFilePath+context.TmpFileSuffix)
-		err = os.WriteFile(outputFilePath, []byte(config), 0644)
+		err = os.WriteFile(outputFilePath, config, 0644)
 		if err != nil {
 			log.Panicf("E! Failed to write the json file %v: %v", outputFilePath, err)
 		} else {
@@ -4,8 +4,10 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/user"
@@ -25,8 +27,19 @@ const (
 	version            = "1.0"
 	envConfigFileName  = "env-config.json"
 	yamlConfigFileName = "amazon-cloudwatch-agent.yaml"
+
+	explainFormatText = "text"
+	explainFormatJson = "json"
+
+	// exitCodeStrictValidation is used when the strict validation finds
+	// unknown or misplaced keys in the JSON config.
+	exitCodeStrictValidation = 2
 )
 
+// explainFormat is the output format for the dry-run explain mode. If empty,
+// the translated configuration files are written instead.
+var explainFormat string
+
 func initFlags() {
 	var inputOs = flag.String("os", "", "Please provide the os preference, valid value: windows/linux.")
 	var inputJsonFile = flag.String("input", "", "Please provide the path of input agent json config file")
@@ -35,14 +48,21 @@ func initFlags() {
 	var inputMode = flag.String("mode", "ec2", "Please provide the mode, i.e. ec2, onPremise, onPrem, auto")
 	var inputConfig = flag.String("config", "", "Please provide the common-config file")
 	var multiConfig = flag.String("multi-config", "remove", "valid values: default, append, remove")
+	var strict = flag.Bool("strict", false, "Report every unknown or misplaced key in the input json config files with its line and column")
+	flag.StringVar(&explainFormat, "explain", "", "Dry-run that prints the translated pipelines instead of writing the configuration files, valid values: text, json")
 	flag.Parse()
 
+	if explainFormat != "" && explainFormat != explainFormatText && explainFormat != explainFormatJson {
+		log.Fatalf("E! Invalid explain format %s, valid values: %s, %s", explainFormat, explainFormatText, explainFormatJson)
+	}
+
 	ctx := context.CurrentContext()
 	ctx.SetOs(*inputOs)
 	ctx.SetInputJsonFilePath(*inputJsonFile)
 	ctx.SetInputJsonDirPath(*inputJsonDir)
 	ctx.SetMultiConfig(*multiConfig)
 	ctx.SetOutputTomlFilePath(*inputTomlFile)
+	ctx.SetStrictValidation(*strict)
 
 	if *inputConfig != "" {
 		f, err := os.Open(*inputConfig)
@@ -69,12 +89,18 @@ func initFlags() {
 
 /**
  *	config-translator --input ${JSON} --input-dir ${JSON_DIR} --output ${TOML} --mode ${param_mode} --config ${COMMON_CONFIG}
- *  --multi-config [default|append|remove]
+ *  --multi-config [default|append|remove] --explain [text|json] --strict
  *
  *		multi-config:
  *			default:	only process .tmp files
  *			append:		process both existing files and .tmp files
  *			remove:		only process existing files
+ *
+ *		explain:
+ *			text:		print a human-readable pipeline graph instead of writing files
+ *			json:		print a JSON pipeline graph instead of writing files
+ *
+ *		strict:		fail with exit code 2 if the input json config files contain unknown or misplaced keys
  */
 func main() {
 	initFlags()
@@ -96,6 +122,14 @@ func main() {
 	ctx := context.CurrentContext()
 
 	mergedJsonConfigMap, err := cmdutil.GenerateMergedJsonConfigMap(ctx)
+	var strictErr *cmdutil.StrictValidationError
+	if errors.As(err, &strictErr) {
+		for _, keyErr := range strictErr.Errors {
+			log.Printf("E! %v", keyErr)
+		}
+		log.Printf(exitErrorMessage, version)
+		os.Exit(exitCodeStrictValidation)
+	}
 	if err != nil {
 		log.Panicf("E! Failed to generate merged json config: %v", err)
 	}
@@ -123,6 +157,10 @@ func main() {
 	if err != nil && !errors.Is(err, pipeline.ErrNoPipelines) {
 		log.Panicf("E! Failed to generate YAML configuration validation content: %v", err)
 	}
+	if explainFormat != "" {
+		explain(mergedJsonConfigMap)
+		return
+	}
 	if err = cmdutil.ConfigToTomlFile(tomlConfig, tomlConfigPath); err != nil {
 		log.Panicf("E! Failed to create the configuration TOML validation file: %v", err)
 	}
@@ -134,3 +172,21 @@ func main() {
 	envConfigPath := filepath.Join(tomlConfigDir, envConfigFileName)
 	cmdutil.TranslateJsonMapToEnvConfigFile(mergedJsonConfigMap, envConfigPath)
 }
+
+// explain prints the pipelines translated from the JSON config in the
+// requested format.
+func explain(jsonConfigValue interface{}) {
+	explanation, err := cmdutil.ExplainJsonMapToYamlConfig(jsonConfigValue)
+	if err != nil {
+		log.Panicf("E! Failed to explain YAML configuration: %v", err)
+	}
+	if explainFormat == explainFormatJson {
+		out, err := json.MarshalIndent(explanation, "", "  ")
+		if err != nil {
+			log.Panicf("E! Failed to marshal explanation: %v", err)
+		}
+		fmt.Println(string(out))
+		return
+	}
+	fmt.Print(explanation.String())
+}
@@ -58,6 +58,19 @@ func TestAgentConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAgent.json", false, expectedErrorMap)
 }
 
+// TestStrictOnlyKeysConfig validates keys that are only declared for the strict
+// validation, which must not reject values that were accepted before.
+func TestStrictOnlyKeysConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validStrictOnlyKeys.json", true, map[string]int{})
+}
+
+func TestAgentConfigPollingConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validAgentConfigPolling.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAgentConfigPollingWithChecksum.json", false, expectedErrorMap)
+}
+
 func TestTracesConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validTrace.json", true, map[string]int{})
 	expectedErrorMap := map[string]int{}
@@ -78,6 +91,64 @@ func TestLogFilesConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithDuplicateEntry.json", false, expectedErrorMap2)
 }
 
+func TestLogFilesWatchModeConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithInotify.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithInvalidWatchMode.json", false, expectedErrorMap)
+}
+
+func TestLogFilesContainerLogConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithContainerLogs.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithInvalidContainerLogFormat.json", false, expectedErrorMap)
+}
+
+func TestLogFilesBackfillConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithBackfill.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_gte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithInvalidBackfillRateLimit.json", false, expectedErrorMap)
+}
+
+func TestLogFilesTimestampFormatsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithTimestampFormats.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["required"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithTimestampFormatsMissingFormat.json", false, expectedErrorMap)
+}
+
+func TestLogFilesMultiLineConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithMultiLine.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithInvalidMultiLinePreset.json", false, expectedErrorMap)
+}
+
+func TestLogFilesEnrichmentConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithEnrichment.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithInvalidEnrichmentField.json", false, expectedErrorMap)
+}
+
+func TestLogFilesLocalLogsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithLocalLogs.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	expectedErrorMap["number_gte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithInvalidLocalLogs.json", false, expectedErrorMap)
+}
+
+func TestLogFilesKinesisLogsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithKinesisLogs.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	expectedErrorMap["required"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithInvalidKinesisLogs.json", false, expectedErrorMap)
+}
+
 func TestLogWindowsEventConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogWindowsEvents.json", true, map[string]int{})
 	expectedErrorMap := map[string]int{}
@@ -94,6 +165,20 @@ func TestLogWindowsEventConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogWindowsEventsWithInvalidEventFormatType.json", false, expectedErrorMap3)
 }
 
+func TestLogJournaldConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogJournald.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogJournaldWithInvalidPriority.json", false, expectedErrorMap)
+}
+
+func TestLogSyslogConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogSyslog.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogSyslogWithInvalidProtocol.json", false, expectedErrorMap)
+}
+
 func TestMetricsConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLinuxMetrics.json", true, map[string]int{})
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validWindowsMetrics.json", true, map[string]int{})
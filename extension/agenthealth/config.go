@@ -10,8 +10,11 @@ import (
 )
 
 type Config struct {
-	IsUsageDataEnabled bool              `mapstructure:"is_usage_data_enabled"`
-	Stats              agent.StatsConfig `mapstructure:"stats"`
+	IsUsageDataEnabled bool `mapstructure:"is_usage_data_enabled"`
+	// IsHealthMetricsEnabled records the client stats for the agent health
+	// metrics even if the usage data is disabled.
+	IsHealthMetricsEnabled bool              `mapstructure:"is_health_metrics_enabled,omitempty"`
+	Stats                  agent.StatsConfig `mapstructure:"stats"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -9,6 +9,8 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats"
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/agent"
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/client"
 	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/useragent"
 )
 
@@ -28,6 +30,10 @@ func (ah *agentHealth) Handlers() ([]awsmiddleware.RequestHandler, []awsmiddlewa
 		req, res := stats.NewHandlers(ah.logger, ah.cfg.Stats)
 		requestHandlers = append(requestHandlers, req...)
 		responseHandlers = append(responseHandlers, res...)
+	} else if ah.cfg.IsHealthMetricsEnabled {
+		clientStats := client.NewHandler(agent.NewOperationsFilter(ah.cfg.Stats.Operations...))
+		requestHandlers = append(requestHandlers, clientStats)
+		responseHandlers = append(responseHandlers, clientStats)
 	}
 	return requestHandlers, responseHandlers
 }
@@ -28,5 +28,11 @@ func TestExtension(t *testing.T) {
 	// user agent
 	assert.Len(t, requestHandlers, 1)
 	assert.Len(t, responseHandlers, 0)
+	cfg.IsHealthMetricsEnabled = true
+	requestHandlers, responseHandlers = extension.Handlers()
+	// user agent, client stats
+	assert.Len(t, requestHandlers, 2)
+	// client stats
+	assert.Len(t, responseHandlers, 1)
 	assert.NoError(t, extension.Shutdown(ctx))
 }
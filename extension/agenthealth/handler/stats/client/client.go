@@ -109,6 +109,7 @@ func (csh *clientStatsHandler) HandleResponse(ctx context.Context, r *http.Respo
 	latency := time.Since(recorder.start)
 	stats.LatencyMillis = aws.Int64(latency.Milliseconds())
 	csh.statsByOperation.Store(operation, stats)
+	operations.record(operation, r.StatusCode, latency, recorder.payloadBytes)
 }
 
 func (csh *clientStatsHandler) Stats(operation string) agent.Stats {
@@ -21,6 +21,7 @@ import (
 func TestHandle(t *testing.T) {
 	operation := "test"
 	handler := NewHandler(agent.NewOperationsFilter("test"))
+	TakeOperationMetrics()
 	handler.(*clientStatsHandler).getOperationName = func(context.Context) string {
 		return operation
 	}
@@ -64,4 +65,16 @@ func TestHandle(t *testing.T) {
 	got = handler.Stats(operation)
 	assert.NotNil(t, got.PayloadBytes)
 	assert.Equal(t, 29, *got.PayloadBytes)
+
+	operationMetrics := TakeOperationMetrics()
+	require.Contains(t, operationMetrics, operation)
+	assert.Equal(t, 3, operationMetrics[operation].RequestCount)
+	assert.Equal(t, 0, operationMetrics[operation].ErrorCount)
+	assert.EqualValues(t, 66, operationMetrics[operation].PayloadBytes)
+	assert.GreaterOrEqual(t, operationMetrics[operation].LatencyMaxMillis, int64(1))
+	assert.Empty(t, TakeOperationMetrics())
+
+	handler.HandleRequest(ctx, req)
+	handler.HandleResponse(ctx, &http.Response{StatusCode: http.StatusTooManyRequests})
+	assert.Equal(t, 1, TakeOperationMetrics()[operation].ErrorCount)
 }
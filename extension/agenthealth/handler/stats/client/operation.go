@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package client
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// OperationMetrics are the aggregated stats of the requests made for an
+// operation.
+type OperationMetrics struct {
+	RequestCount     int
+	ErrorCount       int
+	LatencySumMillis int64
+	LatencyMaxMillis int64
+	PayloadBytes     int64
+}
+
+type operationRecorder struct {
+	mu          sync.Mutex
+	byOperation map[string]*OperationMetrics
+}
+
+var operations = &operationRecorder{byOperation: make(map[string]*OperationMetrics)}
+
+func (r *operationRecorder) record(operation string, statusCode int, latency time.Duration, payloadBytes int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	metrics, ok := r.byOperation[operation]
+	if !ok {
+		metrics = &OperationMetrics{}
+		r.byOperation[operation] = metrics
+	}
+	metrics.RequestCount++
+	if statusCode >= http.StatusBadRequest {
+		metrics.ErrorCount++
+	}
+	latencyMillis := latency.Milliseconds()
+	metrics.LatencySumMillis += latencyMillis
+	if latencyMillis > metrics.LatencyMaxMillis {
+		metrics.LatencyMaxMillis = latencyMillis
+	}
+	metrics.PayloadBytes += payloadBytes
+}
+
+func (r *operationRecorder) take() map[string]OperationMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make(map[string]OperationMetrics, len(r.byOperation))
+	for operation, metrics := range r.byOperation {
+		result[operation] = *metrics
+	}
+	r.byOperation = make(map[string]*OperationMetrics)
+	return result
+}
+
+// TakeOperationMetrics returns the metrics for the requests across all the
+// client stats handlers since the last call.
+func TakeOperationMetrics() map[string]OperationMetrics {
+	return operations.take()
+}
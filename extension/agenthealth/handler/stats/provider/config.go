@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/agent"
+)
+
+const (
+	configVersionGetInterval = 5 * time.Minute
+)
+
+var (
+	configVersionSingleton *configVersionStats
+	configVersionOnce      sync.Once
+)
+
+// configVersionStats reports the version of the config that is in use. Unlike
+// the usage flags, the version changes when a new config is polled and applied.
+type configVersionStats struct {
+	*intervalStats
+}
+
+func (p *configVersionStats) setVersion(version string) {
+	p.stats.Store(agent.Stats{ConfigVersion: aws.String(version)})
+}
+
+func newConfigVersionStats(interval time.Duration) *configVersionStats {
+	return &configVersionStats{intervalStats: newIntervalStats(interval)}
+}
+
+func getConfigVersionStats() *configVersionStats {
+	configVersionOnce.Do(func() {
+		configVersionSingleton = newConfigVersionStats(configVersionGetInterval)
+	})
+	return configVersionSingleton
+}
+
+func GetConfigVersionStats() agent.StatsProvider {
+	return getConfigVersionStats()
+}
+
+// SetConfigVersion sets the version of the config that is in use.
+func SetConfigVersion(version string) {
+	getConfigVersionStats().setVersion(version)
+}
@@ -0,0 +1,22 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigVersionStats(t *testing.T) {
+	cs := newConfigVersionStats(time.Microsecond)
+	assert.Nil(t, cs.getStats().ConfigVersion)
+	cs.setVersion("1")
+	got := cs.getStats()
+	assert.NotNil(t, got.ConfigVersion)
+	assert.Equal(t, "1", *got.ConfigVersion)
+	cs.setVersion("2")
+	assert.Equal(t, "2", *cs.getStats().ConfigVersion)
+}
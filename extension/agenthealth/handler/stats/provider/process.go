@@ -99,3 +99,10 @@ func GetProcessStats() agent.StatsProvider {
 	})
 	return processSingleton
 }
+
+// GetLatestProcessStats returns the last collected process stats. Unlike the
+// provider, reads are not restricted to once per interval.
+func GetLatestProcessStats() agent.Stats {
+	GetProcessStats()
+	return processSingleton.getStats()
+}
@@ -110,6 +110,7 @@ require (
 	github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awscloudwatchlogsexporter v0.98.0
 	github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awsemfexporter v0.98.0
 	github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awsxrayexporter v0.98.0
+	github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusexporter v0.98.0
 	github.com/open-telemetry/opentelemetry-collector-contrib/extension/awsproxy v0.98.0
 	github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry v0.98.0
 	github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza v0.98.0
@@ -166,9 +167,13 @@ require (
 )
 
 require (
+	go.opentelemetry.io/collector/config/configcompression v1.5.0
 	go.opentelemetry.io/collector/confmap/converter/expandconverter v0.98.0
 	go.opentelemetry.io/collector/confmap/provider/fileprovider v0.98.0
+	go.opentelemetry.io/collector/exporter/otlpexporter v0.98.0
+	go.opentelemetry.io/collector/exporter/otlphttpexporter v0.98.0
 	go.opentelemetry.io/collector/featuregate v1.5.0
+	golang.org/x/time v0.5.0
 )
 
 require (
@@ -384,7 +389,6 @@ require (
 	go.opencensus.io v0.24.0 // indirect
 	go.opentelemetry.io/collector v0.98.0 // indirect
 	go.opentelemetry.io/collector/config/configauth v0.98.0 // indirect
-	go.opentelemetry.io/collector/config/configcompression v1.5.0 // indirect
 	go.opentelemetry.io/collector/config/configgrpc v0.98.0 // indirect
 	go.opentelemetry.io/collector/config/confighttp v0.98.0 // indirect
 	go.opentelemetry.io/collector/config/confignet v0.98.0 // indirect
@@ -420,7 +424,6 @@ require (
 	golang.org/x/mod v0.16.0 // indirect
 	golang.org/x/oauth2 v0.18.0 // indirect
 	golang.org/x/term v0.19.0 // indirect
-	golang.org/x/time v0.5.0 // indirect
 	golang.org/x/tools v0.19.0 // indirect
 	gonum.org/v1/gonum v0.15.0 // indirect
 	google.golang.org/api v0.168.0 // indirect
@@ -9,6 +9,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
 )
 
 type ObjStore struct {
@@ -16,6 +17,8 @@ type ObjStore struct {
 
 	refreshed bool
 	objs      map[types.UID]interface{}
+	// uids indexes the objs by their namespace/name key.
+	uids map[string]types.UID
 
 	transformFunc func(interface{}) (interface{}, error)
 }
@@ -24,6 +27,7 @@ func NewObjStore(transformFunc func(interface{}) (interface{}, error)) *ObjStore
 	return &ObjStore{
 		transformFunc: transformFunc,
 		objs:          map[types.UID]interface{}{},
+		uids:          map[string]types.UID{},
 	}
 }
 
@@ -57,6 +61,7 @@ func (s *ObjStore) Add(obj interface{}) error {
 	defer s.Unlock()
 
 	s.objs[o.GetUID()] = toCacheObj
+	s.uids[cache.MetaObjectToName(o).String()] = o.GetUID()
 	s.refreshed = true
 
 	return nil
@@ -79,6 +84,11 @@ func (s *ObjStore) Delete(obj interface{}) error {
 	defer s.Unlock()
 
 	delete(s.objs, o.GetUID())
+	// the object may have been replaced by a new one with the same name
+	key := cache.MetaObjectToName(o).String()
+	if s.uids[key] == o.GetUID() {
+		delete(s.uids, key)
+	}
 
 	s.refreshed = true
 
@@ -114,9 +124,18 @@ func (s *ObjStore) Get(obj interface{}) (item interface{}, exists bool, err erro
 	return nil, false, nil
 }
 
-// GetByKey implements the GetByKey method of the store interface.
+// GetByKey implements the GetByKey method of the store interface. The key is
+// the namespace/name of the object, or the name for cluster scoped objects.
 func (s *ObjStore) GetByKey(key string) (item interface{}, exists bool, err error) {
-	return nil, false, nil
+	s.RLock()
+	defer s.RUnlock()
+
+	uid, ok := s.uids[key]
+	if !ok {
+		return nil, false, nil
+	}
+	item, exists = s.objs[uid]
+	return item, exists, nil
 }
 
 // Replace will delete the contents of the store, using instead the
@@ -124,6 +143,7 @@ func (s *ObjStore) GetByKey(key string) (item interface{}, exists bool, err erro
 func (s *ObjStore) Replace(list []interface{}, _ string) error {
 	s.Lock()
 	s.objs = map[types.UID]interface{}{}
+	s.uids = map[string]types.UID{}
 	s.Unlock()
 
 	for _, o := range list {
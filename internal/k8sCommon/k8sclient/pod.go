@@ -22,6 +22,7 @@ import (
 
 type PodClient interface {
 	NamespaceToRunningPodNum() map[string]int
+	PodMetadata(namespace, name string) (PodMetadata, bool)
 
 	Init()
 	Shutdown()
@@ -52,6 +53,24 @@ func (c *podClient) NamespaceToRunningPodNum() map[string]int {
 	return c.namespaceToRunningPodNumMap
 }
 
+// PodMetadata returns the metadata of the pod with the name in the namespace.
+func (c *podClient) PodMetadata(namespace, name string) (PodMetadata, bool) {
+	if !c.inited {
+		c.Init()
+	}
+	obj, ok, _ := c.store.GetByKey(cache.NewObjectName(namespace, name).String())
+	if !ok {
+		return PodMetadata{}, false
+	}
+	pod := obj.(*podInfo)
+	return PodMetadata{
+		Namespace: pod.namespace,
+		Name:      pod.name,
+		UID:       pod.uid,
+		Labels:    pod.labels,
+	}, true
+}
+
 func (c *podClient) refresh() {
 	c.Lock()
 	defer c.Unlock()
@@ -114,6 +133,9 @@ func transformFuncPod(obj interface{}) (interface{}, error) {
 	}
 	info := new(podInfo)
 	info.namespace = pod.Namespace
+	info.name = pod.Name
+	info.uid = string(pod.UID)
+	info.labels = pod.Labels
 	info.phase = pod.Status.Phase
 	return info, nil
 }
@@ -9,5 +9,16 @@ import (
 
 type podInfo struct {
 	namespace string
+	name      string
+	uid       string
+	labels    map[string]string
 	phase     v1.PodPhase
 }
+
+// PodMetadata is the metadata of a pod that is used to enrich its logs.
+type PodMetadata struct {
+	Namespace string
+	Name      string
+	UID       string
+	Labels    map[string]string
+}
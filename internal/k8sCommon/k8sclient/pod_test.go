@@ -183,3 +183,36 @@ func TestPodClient_NamespaceToRunningPodNum(t *testing.T) {
 	log.Printf("NamespaceToRunningPodNum (len=%v): %v", len(resultMap), awsutil.Prettify(resultMap))
 	assert.DeepEqual(t, resultMap, expectedMap)
 }
+
+func TestPodClient_PodMetadata(t *testing.T) {
+	client, stopChan := setUpPodClient()
+	defer close(stopChan)
+
+	pod := *podArray[4].(*v1.Pod)
+	pod.Labels = map[string]string{"app": "guestbook"}
+	client.store.Replace([]interface{}{podArray[0], &pod}, "")
+
+	metadata, ok := client.PodMetadata("default", "guestbook-qbdv8")
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, metadata, PodMetadata{
+		Namespace: "default",
+		Name:      "guestbook-qbdv8",
+		UID:       "11d078c2-6fed-49c3-83a8-b94915a6451f",
+		Labels:    map[string]string{"app": "guestbook"},
+	})
+	_, ok = client.PodMetadata("kube-system", "guestbook-qbdv8")
+	assert.Assert(t, !ok)
+
+	// the pod is recreated with the same name before the old one is deleted
+	recreated := pod
+	recreated.UID = "22e189d3-7afe-4ad0-94c9-c95a26b7562a"
+	client.store.Add(&recreated)
+	client.store.Delete(&pod)
+	metadata, ok = client.PodMetadata("default", "guestbook-qbdv8")
+	assert.Assert(t, ok)
+	assert.Equal(t, metadata.UID, "22e189d3-7afe-4ad0-94c9-c95a26b7562a")
+
+	client.store.Delete(&recreated)
+	_, ok = client.PodMetadata("default", "guestbook-qbdv8")
+	assert.Assert(t, !ok)
+}
@@ -25,5 +25,6 @@ const (
 	LogEntryField = "value"
 
 	WindowsEventLogPrefix = "Amazon_CloudWatch_WindowsEventLog_"
+	JournaldPrefix        = "Amazon_CloudWatch_Journald_"
 	LogType               = "log_type"
 )
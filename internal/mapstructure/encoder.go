@@ -43,7 +43,7 @@ type Encoder struct {
 // EncoderConfig is the configuration used to create a new encoder.
 type EncoderConfig struct {
 	// EncodeHook, if set, is a way to provide custom encoding. It
-	// will be called before structs and primitive types.
+	// will be called before structs, maps and primitive types.
 	EncodeHook mapstructure.DecodeHookFunc
 	// NilEmptyMap, if set, is a way to nil out empty maps.
 	NilEmptyMap bool
@@ -82,7 +82,7 @@ func (e *Encoder) encode(value reflect.Value) (any, error) {
 }
 
 // encodeHook calls the EncodeHook in the EncoderConfig with the value passed in.
-// This is called before processing structs and for primitive data types.
+// This is called before processing structs and maps and for primitive data types.
 func (e *Encoder) encodeHook(value reflect.Value) (any, error) {
 	if e.config != nil && e.config.EncodeHook != nil {
 		out, err := mapstructure.DecodeHookExec(e.config.EncodeHook, value, value)
@@ -178,6 +178,16 @@ func (e *Encoder) encodeMap(value reflect.Value) (any, error) {
 	if value.IsNil() {
 		return nil, nil
 	}
+	out, err := e.encodeHook(value)
+	if err != nil {
+		return nil, err
+	}
+	value = reflect.ValueOf(out)
+	// if the output of encodeHook is no longer a map,
+	// call encode against it.
+	if value.Kind() != reflect.Map {
+		return e.encode(value)
+	}
 	result := make(map[string]any)
 	iterator := value.MapRange()
 	for iterator.Next() {
@@ -31,7 +31,8 @@ func Marshal(rawVal any) (map[string]any, error) {
 func encoderConfig(rawVal any) *EncoderConfig {
 	return &EncoderConfig{
 		EncodeHook: mapstructure.ComposeDecodeHookFunc(
-			NilHookFunc[configopaque.String](),
+			NilZeroValueHookFunc[configopaque.String](),
+			OpaqueHeadersHookFunc(),
 			NilZeroValueHookFunc[configtls.ServerConfig](),
 			TextMarshalerHookFunc(),
 			MarshalerHookFunc(rawVal),
@@ -42,6 +43,27 @@ func encoderConfig(rawVal any) *EncoderConfig {
 	}
 }
 
+// OpaqueHeadersHookFunc returns a DecodeHookFuncValue that writes out the raw values of the configopaque.String
+// headers of the confighttp and configgrpc client configs instead of the redacted text, so that the translated
+// OTLP exporters keep their request headers. Every other configopaque.String, like the inline TLS keys, stays
+// redacted.
+func OpaqueHeadersHookFunc() mapstructure.DecodeHookFuncValue {
+	return func(from reflect.Value, _ reflect.Value) (any, error) {
+		if !from.IsValid() {
+			return nil, nil
+		}
+		headers, ok := from.Interface().(map[string]configopaque.String)
+		if !ok {
+			return from.Interface(), nil
+		}
+		out := make(map[string]string, len(headers))
+		for key, value := range headers {
+			out[key] = string(value)
+		}
+		return out, nil
+	}
+}
+
 // MarshalerHookFunc returns a DecodeHookFuncValue that checks structs that aren't
 // the original to see if they implement the Marshaler interface.
 func MarshalerHookFunc(orig any) mapstructure.DecodeHookFuncValue {
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/confmap"
 )
 
@@ -52,6 +53,23 @@ func TestMarshal(t *testing.T) {
 	assert.Equal(t, map[string]any{"string_": "this is a string"}, conf.Get("map"))
 }
 
+func TestMarshalOpaqueHeaders(t *testing.T) {
+	cfg := &struct {
+		Headers map[string]configopaque.String `mapstructure:"headers"`
+		Pem     configopaque.String            `mapstructure:"pem"`
+		Empty   configopaque.String            `mapstructure:"empty"`
+	}{
+		Headers: map[string]configopaque.String{"key": "value"},
+		Pem:     "secret",
+	}
+	got, err := Marshal(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"headers": map[string]any{"key": "value"},
+		"pem":     "[REDACTED]",
+	}, got)
+}
+
 func TestMarshalDuplicateID(t *testing.T) {
 	cfg := &TestIDConfig{
 		Boolean: true,
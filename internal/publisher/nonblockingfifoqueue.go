@@ -47,3 +47,10 @@ func (u *NonBlockingFifoQueue) Enqueue(value interface{}) {
 	}
 	u.queue.PushBack(value)
 }
+
+func (u *NonBlockingFifoQueue) Len() int {
+	u.Lock()
+	defer u.Unlock()
+
+	return u.queue.Len()
+}
@@ -0,0 +1,30 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const Path = "/status"
+
+// NewHandler returns a handler that serves the report as JSON on Path. It
+// uses its own mux so that handlers registered on the default mux, e.g. pprof,
+// are not exposed.
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(Path, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(Get())
+	})
+	return mux
+}
@@ -0,0 +1,169 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package status collects the state of the agent's log sources, log pushers
+// and metrics exporters so it can be served by the local status API.
+package status
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LogSource is the state of a log source, e.g. a tailed file.
+type LogSource struct {
+	Path        string `json:"path"`
+	Group       string `json:"log_group"`
+	Stream      string `json:"log_stream"`
+	Destination string `json:"destination,omitempty"`
+	// ReadOffset is the offset of the last line read from the file.
+	ReadOffset int64 `json:"read_offset"`
+	// SavedOffset is the offset in the state file. Everything before it has
+	// been published.
+	SavedOffset int64 `json:"saved_offset"`
+}
+
+// LogPusher is the state of a pusher sending events to a log stream.
+type LogPusher struct {
+	Group  string `json:"log_group"`
+	Stream string `json:"log_stream"`
+	// QueueDepth is the number of events waiting to be added to a batch.
+	QueueDepth int `json:"queue_depth"`
+	// BatchSize is the number of events in the batch that is not sent yet.
+	BatchSize     int        `json:"batch_size"`
+	LastSendTime  *time.Time `json:"last_send_time,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastErrorTime *time.Time `json:"last_error_time,omitempty"`
+}
+
+// MetricsExporter is the state of an exporter sending metrics to CloudWatch.
+type MetricsExporter struct {
+	Name string `json:"name"`
+	// BatchSize is the number of datums in the batch that is not sent yet.
+	BatchSize int `json:"batch_size"`
+	// MetricQueueSize is the number of metrics waiting to be batched.
+	MetricQueueSize     int `json:"metric_queue_size"`
+	MetricQueueCapacity int `json:"metric_queue_capacity"`
+	// BatchQueueSize is the number of full batches waiting to be published.
+	BatchQueueSize     int `json:"batch_queue_size"`
+	BatchQueueCapacity int `json:"batch_queue_capacity"`
+	// PublishQueueSize is the number of requests waiting to be sent.
+	PublishQueueSize int `json:"publish_queue_size"`
+}
+
+// Report is the state of the agent.
+type Report struct {
+	ConfigVersion    string            `json:"config_version,omitempty"`
+	LogSources       []LogSource       `json:"log_sources"`
+	LogPushers       []LogPusher       `json:"log_pushers"`
+	MetricsExporters []MetricsExporter `json:"metrics_exporters"`
+	// Dropped are the counts of dropped metrics or events since the agent
+	// started, by reason.
+	Dropped map[string]int64 `json:"dropped"`
+}
+
+// registry holds the functions that report the state of each component.
+type registry[T any] struct {
+	mu        sync.RWMutex
+	nextID    uint64
+	reporters map[uint64]func() T
+}
+
+func (r *registry[T]) register(fn func() T) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.reporters == nil {
+		r.reporters = make(map[uint64]func() T)
+	}
+	id := r.nextID
+	r.nextID++
+	r.reporters[id] = fn
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			delete(r.reporters, id)
+		})
+	}
+}
+
+func (r *registry[T]) collect() []T {
+	r.mu.RLock()
+	ids := make([]uint64, 0, len(r.reporters))
+	for id := range r.reporters {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	reporters := make([]func() T, len(ids))
+	for i, id := range ids {
+		reporters[i] = r.reporters[id]
+	}
+	r.mu.RUnlock()
+	result := make([]T, len(reporters))
+	for i, reporter := range reporters {
+		result[i] = reporter()
+	}
+	return result
+}
+
+var (
+	logSources       registry[LogSource]
+	logPushers       registry[LogPusher]
+	metricsExporters registry[MetricsExporter]
+
+	dropped       sync.Map // map[string]*atomic.Int64
+	configVersion atomic.Value
+)
+
+// RegisterLogSource adds a log source to the report. The returned function
+// removes it.
+func RegisterLogSource(fn func() LogSource) func() {
+	return logSources.register(fn)
+}
+
+// RegisterLogPusher adds a log pusher to the report. The returned function
+// removes it.
+func RegisterLogPusher(fn func() LogPusher) func() {
+	return logPushers.register(fn)
+}
+
+// RegisterMetricsExporter adds a metrics exporter to the report. The returned
+// function removes it.
+func RegisterMetricsExporter(fn func() MetricsExporter) func() {
+	return metricsExporters.register(fn)
+}
+
+// AddDropped increments the dropped count for the reason.
+func AddDropped(reason string, count int64) {
+	counter, ok := dropped.Load(reason)
+	if !ok {
+		counter, _ = dropped.LoadOrStore(reason, new(atomic.Int64))
+	}
+	counter.(*atomic.Int64).Add(count)
+}
+
+// SetConfigVersion sets the version of the config in use.
+func SetConfigVersion(version string) {
+	configVersion.Store(version)
+}
+
+// Get returns the current state of the agent.
+func Get() Report {
+	report := Report{
+		LogSources:       logSources.collect(),
+		LogPushers:       logPushers.collect(),
+		MetricsExporters: metricsExporters.collect(),
+		Dropped:          make(map[string]int64),
+	}
+	if version, ok := configVersion.Load().(string); ok {
+		report.ConfigVersion = version
+	}
+	dropped.Range(func(key, value any) bool {
+		report.Dropped[key.(string)] = value.(*atomic.Int64).Load()
+		return true
+	})
+	return report
+}
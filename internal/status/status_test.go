@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	offset := int64(10)
+	unregisterFirst := RegisterLogSource(func() LogSource {
+		return LogSource{Path: "/tmp/a.log", Group: "group", Stream: "stream", ReadOffset: offset}
+	})
+	unregisterSecond := RegisterLogSource(func() LogSource {
+		return LogSource{Path: "/tmp/b.log"}
+	})
+	defer unregisterSecond()
+
+	report := Get()
+	require.Len(t, report.LogSources, 2)
+	assert.Equal(t, "/tmp/a.log", report.LogSources[0].Path)
+	assert.EqualValues(t, 10, report.LogSources[0].ReadOffset)
+	assert.Equal(t, "/tmp/b.log", report.LogSources[1].Path)
+
+	offset = 20
+	assert.EqualValues(t, 20, Get().LogSources[0].ReadOffset)
+
+	unregisterFirst()
+	unregisterFirst()
+	report = Get()
+	require.Len(t, report.LogSources, 1)
+	assert.Equal(t, "/tmp/b.log", report.LogSources[0].Path)
+}
+
+func TestAddDropped(t *testing.T) {
+	AddDropped("test_drop", 1)
+	AddDropped("test_drop", 2)
+	assert.EqualValues(t, 3, Get().Dropped["test_drop"])
+}
+
+func TestHandler(t *testing.T) {
+	SetConfigVersion("v1")
+	unregister := RegisterLogPusher(func() LogPusher {
+		return LogPusher{Group: "group", Stream: "stream", QueueDepth: 5, LastError: "throttled"}
+	})
+	defer unregister()
+	handler := NewHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var report Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, "v1", report.ConfigVersion)
+	require.Len(t, report.LogPushers, 1)
+	assert.Equal(t, 5, report.LogPushers[0].QueueDepth)
+	assert.Equal(t, "throttled", report.LogPushers[0].LastError)
+	assert.NotNil(t, report.MetricsExporters)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Path, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
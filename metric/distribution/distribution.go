@@ -12,7 +12,7 @@ import (
 
 var (
 	ErrUnsupportedWeight = errors.New("weight must be larger than 0")
-	ErrUnsupportedValue  = errors.New("value cannot be negative, NaN, Inf, or greater than 2^360")
+	ErrUnsupportedValue  = errors.New("value cannot be NaN, Inf, less than -2^360, or greater than 2^360")
 	MinValue             = -math.Pow(2, 360)
 	MaxValue             = math.Pow(2, 360)
 )
@@ -45,6 +45,9 @@ type Distribution interface {
 	ConvertToOtel(dp pmetric.HistogramDataPoint)
 
 	ConvertFromOtel(dp pmetric.HistogramDataPoint, unit string)
+
+	// unitScale converts the values of the data point to the unit.
+	ConvertFromOtelExponential(dp pmetric.ExponentialHistogramDataPoint, unit string, unitScale float64)
 }
 
 var NewDistribution func() Distribution
@@ -55,3 +58,31 @@ var NewDistribution func() Distribution
 func IsSupportedValue(value, min, max float64) bool {
 	return !math.IsNaN(value) && value >= min && value <= max
 }
+
+// ExponentialBucketValues calls fn with the value in the middle of each
+// non-empty bucket of the exponential histogram data point, multiplied by
+// unitScale, and its count. The middle of the bucket with index i is
+// base^(i+0.5) where base = 2^(2^-scale). Buckets whose value is not
+// supported are skipped.
+func ExponentialBucketValues(dp pmetric.ExponentialHistogramDataPoint, unitScale float64, fn func(value float64, count uint64)) {
+	if dp.ZeroCount() > 0 {
+		fn(0, dp.ZeroCount())
+	}
+	exponent := math.Exp2(float64(-dp.Scale()))
+	forEach := func(buckets pmetric.ExponentialHistogramDataPointBuckets, sign float64) {
+		for i := 0; i < buckets.BucketCounts().Len(); i++ {
+			count := buckets.BucketCounts().At(i)
+			if count == 0 {
+				continue
+			}
+			index := float64(buckets.Offset()) + float64(i)
+			value := sign * math.Exp2((index+0.5)*exponent) * unitScale
+			if !IsSupportedValue(value, MinValue, MaxValue) {
+				continue
+			}
+			fn(value, count)
+		}
+	}
+	forEach(dp.Positive(), 1)
+	forEach(dp.Negative(), -1)
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 )
 
 func TestIsAcceptedValue(t *testing.T) {
@@ -28,3 +29,33 @@ func TestIsAcceptedValue(t *testing.T) {
 		assert.Equal(t, testCase.want, IsSupportedValue(testCase.input, MinValue, MaxValue))
 	}
 }
+
+func TestExponentialBucketValues(t *testing.T) {
+	dp := pmetric.NewExponentialHistogramDataPoint()
+	dp.SetScale(0)
+	dp.SetZeroCount(1)
+	dp.Positive().SetOffset(1)
+	dp.Positive().BucketCounts().FromRaw([]uint64{2, 0, 3})
+	dp.Negative().SetOffset(-1)
+	dp.Negative().BucketCounts().FromRaw([]uint64{4})
+
+	got := map[float64]uint64{}
+	ExponentialBucketValues(dp, 1, func(value float64, count uint64) {
+		got[value] += count
+	})
+	assert.Equal(t, map[float64]uint64{
+		0:                  1,
+		math.Pow(2, 1.5):   2,
+		math.Pow(2, 3.5):   3,
+		-math.Pow(2, -0.5): 4,
+	}, got)
+
+	dp.SetScale(-9)
+	dp.SetZeroCount(0)
+	dp.Negative().BucketCounts().FromRaw([]uint64{})
+	got = map[float64]uint64{}
+	ExponentialBucketValues(dp, 1, func(value float64, count uint64) {
+		got[value] += count
+	})
+	assert.Empty(t, got)
+}
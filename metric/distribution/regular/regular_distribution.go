@@ -24,7 +24,7 @@ type RegularDistribution struct {
 
 func NewRegularDistribution() distribution.Distribution {
 	return &RegularDistribution{
-		maximum:     0, // negative number is not supported for now, so zero is the min value
+		maximum:     -math.MaxFloat64,
 		minimum:     math.MaxFloat64,
 		sampleCount: 0,
 		sum:         0,
@@ -72,7 +72,7 @@ func (regularDist *RegularDistribution) AddEntryWithUnit(value float64, weight f
 	if weight <= 0 {
 		return fmt.Errorf("unsupported weight %v: %w", weight, distribution.ErrUnsupportedWeight)
 	}
-	if !distribution.IsSupportedValue(value, 0, distribution.MaxValue) {
+	if !distribution.IsSupportedValue(value, distribution.MinValue, distribution.MaxValue) {
 		return fmt.Errorf("unsupported value %v: %w", value, distribution.ErrUnsupportedValue)
 	}
 	//sample count
@@ -173,6 +173,25 @@ func (rd *RegularDistribution) ConvertFromOtel(dp pmetric.HistogramDataPoint, un
 	}
 }
 
+// ConvertFromOtelExponential uses the middle value of each bucket of the
+// exponential histogram as the value.
+func (rd *RegularDistribution) ConvertFromOtelExponential(dp pmetric.ExponentialHistogramDataPoint, unit string, unitScale float64) {
+	rd.sampleCount = float64(dp.Count())
+	rd.sum = dp.Sum() * unitScale
+	rd.unit = unit
+	distribution.ExponentialBucketValues(dp, unitScale, func(value float64, count uint64) {
+		rd.buckets[value] += float64(count)
+		rd.minimum = math.Min(rd.minimum, value)
+		rd.maximum = math.Max(rd.maximum, value)
+	})
+	if dp.HasMin() {
+		rd.minimum = dp.Min() * unitScale
+	}
+	if dp.HasMax() {
+		rd.maximum = dp.Max() * unitScale
+	}
+}
+
 func (regularDist *RegularDistribution) GetCount(value float64) float64 {
 	return regularDist.buckets[value]
 }
@@ -80,7 +80,6 @@ func TestRegularDistribution(t *testing.T) {
 	assert.Equal(t, dist, anotherDist) //the direction of AddDistribution should not matter.
 
 	assert.ErrorIs(t, anotherDist.AddEntry(1, 0), distribution.ErrUnsupportedWeight)
-	assert.ErrorIs(t, anotherDist.AddEntry(-1, 1), distribution.ErrUnsupportedValue)
 	assert.ErrorIs(t, anotherDist.AddEntry(math.NaN(), 1), distribution.ErrUnsupportedValue)
 	assert.ErrorIs(t, anotherDist.AddEntry(math.Inf(1), 1), distribution.ErrUnsupportedValue)
 	assert.ErrorIs(t, anotherDist.AddEntry(math.Inf(-1), 1), distribution.ErrUnsupportedValue)
@@ -88,6 +87,31 @@ func TestRegularDistribution(t *testing.T) {
 	assert.ErrorIs(t, anotherDist.AddEntry(distribution.MinValue*1.001, 1), distribution.ErrUnsupportedValue)
 }
 
+func TestRegularDistributionNegativeValues(t *testing.T) {
+	dist := NewRegularDistribution()
+	assert.NoError(t, dist.AddEntry(-10, 1))
+	assert.NoError(t, dist.AddEntry(-20, 1))
+	assert.NoError(t, dist.AddEntry(0, 1))
+	assert.NoError(t, dist.AddEntry(20, 2))
+
+	assert.Equal(t, 10.0, dist.Sum())
+	assert.Equal(t, 5.0, dist.SampleCount())
+	assert.Equal(t, -20.0, dist.Minimum())
+	assert.Equal(t, 20.0, dist.Maximum())
+	assert.Equal(t, 4, dist.Size())
+	assert.Equal(t, 1.0, dist.(*RegularDistribution).GetCount(-10))
+
+	negativeOnly := NewRegularDistribution()
+	assert.NoError(t, negativeOnly.AddEntry(-5, 1))
+	assert.Equal(t, -5.0, negativeOnly.Maximum())
+	assert.Equal(t, -5.0, negativeOnly.Minimum())
+
+	dist.AddDistribution(negativeOnly)
+	assert.Equal(t, 5.0, dist.Sum())
+	assert.Equal(t, 5, dist.Size())
+	assert.Equal(t, -20.0, dist.Minimum())
+}
+
 func cloneRegularDistribution(dist *RegularDistribution) *RegularDistribution {
 	clonedDist := &RegularDistribution{
 		maximum:     dist.maximum,
@@ -16,23 +16,30 @@ import (
 var bucketForZero int16 = math.MinInt16
 var bucketFactor = math.Log(1 + 0.1)
 
+// negativeBucketOffset is added to the negative bucket numbers when they are
+// stored in the otel explicit bounds, so they can be told apart from the
+// positive bucket numbers, which always fit in an int16.
+const negativeBucketOffset = 1 << 17
+
 type SEH1Distribution struct {
-	maximum     float64
-	minimum     float64
-	sampleCount float64
-	sum         float64
-	buckets     map[int16]float64 // from bucket number (i.e. value) to the counter (i.e. weight)
-	unit        string
+	maximum         float64
+	minimum         float64
+	sampleCount     float64
+	sum             float64
+	buckets         map[int16]float64 // from bucket number (i.e. value) to the counter (i.e. weight)
+	negativeBuckets map[int16]float64 // from bucket number of the absolute value to the counter (i.e. weight)
+	unit            string
 }
 
 func NewSEH1Distribution() distribution.Distribution {
 	return &SEH1Distribution{
-		maximum:     0, // negative number is not supported for now, so zero is the min value
-		minimum:     math.MaxFloat64,
-		sampleCount: 0,
-		sum:         0,
-		buckets:     map[int16]float64{},
-		unit:        "",
+		maximum:         -math.MaxFloat64,
+		minimum:         math.MaxFloat64,
+		sampleCount:     0,
+		sum:             0,
+		buckets:         map[int16]float64{},
+		negativeBuckets: map[int16]float64{},
+		unit:            "",
 	}
 }
 
@@ -56,14 +63,12 @@ func (seh1Distribution *SEH1Distribution) ValuesAndCounts() (values []float64, c
 	values = []float64{}
 	counts = []float64{}
 	for bucketNumber, counter := range seh1Distribution.buckets {
-		var value float64
-		if bucketNumber == bucketForZero {
-			value = 0
-		} else {
-			// Add 0.5 to calculate exponent for the middle of the bin
-			value = math.Exp((float64(bucketNumber) + 0.5) * bucketFactor)
-		}
-		values = append(values, value)
+		values = append(values, bucketValue(bucketNumber))
+		counts = append(counts, counter)
+	}
+	// The negative buckets mirror the positive ones.
+	for bucketNumber, counter := range seh1Distribution.negativeBuckets {
+		values = append(values, -bucketValue(bucketNumber))
 		counts = append(counts, counter)
 	}
 	return
@@ -74,7 +79,7 @@ func (seh1Distribution *SEH1Distribution) Unit() string {
 }
 
 func (seh1Distribution *SEH1Distribution) Size() int {
-	return len(seh1Distribution.buckets)
+	return len(seh1Distribution.buckets) + len(seh1Distribution.negativeBuckets)
 }
 
 // weight is 1/samplingRate
@@ -82,7 +87,7 @@ func (seh1Distribution *SEH1Distribution) AddEntryWithUnit(value float64, weight
 	if weight <= 0 {
 		return fmt.Errorf("unsupported weight %v: %w", weight, distribution.ErrUnsupportedWeight)
 	}
-	if !distribution.IsSupportedValue(value, 0, distribution.MaxValue) {
+	if !distribution.IsSupportedValue(value, distribution.MinValue, distribution.MaxValue) {
 		return fmt.Errorf("unsupported value %v: %w", value, distribution.ErrUnsupportedValue)
 	}
 	//sample count
@@ -99,8 +104,11 @@ func (seh1Distribution *SEH1Distribution) AddEntryWithUnit(value float64, weight
 	}
 
 	//seh
-	bucketNumber := bucketNumber(value)
-	seh1Distribution.buckets[bucketNumber] += weight
+	if value < 0 {
+		seh1Distribution.negativeBuckets[bucketNumber(-value)] += weight
+	} else {
+		seh1Distribution.buckets[bucketNumber(value)] += weight
+	}
 
 	//unit
 	if seh1Distribution.unit == "" {
@@ -128,6 +136,9 @@ func (seh1Distribution *SEH1Distribution) AddDistributionWithWeight(distribution
 			for bucketNumber, bucketCounts := range fromSEH1Distribution.buckets {
 				seh1Distribution.buckets[bucketNumber] += bucketCounts * weight
 			}
+			for bucketNumber, bucketCounts := range fromSEH1Distribution.negativeBuckets {
+				seh1Distribution.negativeBuckets[bucketNumber] += bucketCounts * weight
+			}
 		} else {
 			log.Printf("E! The from distribution type is not compatible with the to distribution type: from distribution type %T, to distribution type %T", seh1Distribution, distribution)
 			return
@@ -159,18 +170,23 @@ func (seh1Distribution *SEH1Distribution) AddDistributionWithWeight(distribution
 
 // ConvertToOtel could convert an SEH1Distribution to pmetric.ExponentialHistogram.
 // But there is no need because it will just get converted bak to a SEH1Distribution.
+// Negative bucket numbers are shifted by negativeBucketOffset.
 func (sd *SEH1Distribution) ConvertToOtel(dp pmetric.HistogramDataPoint) {
 	dp.SetMax(sd.maximum)
 	dp.SetMin(sd.minimum)
 	dp.SetCount(uint64(sd.sampleCount))
 	dp.SetSum(sd.sum)
-	dp.ExplicitBounds().EnsureCapacity(len(sd.buckets))
-	dp.BucketCounts().EnsureCapacity(len(sd.buckets))
+	dp.ExplicitBounds().EnsureCapacity(sd.Size())
+	dp.BucketCounts().EnsureCapacity(sd.Size())
 	for k, v := range sd.buckets {
 		dp.ExplicitBounds().Append(float64(k))
 		// Beware of potential loss of precision due to type conversion.
 		dp.BucketCounts().Append(uint64(v))
 	}
+	for k, v := range sd.negativeBuckets {
+		dp.ExplicitBounds().Append(float64(k) + negativeBucketOffset)
+		dp.BucketCounts().Append(uint64(v))
+	}
 }
 
 func (sd *SEH1Distribution) ConvertFromOtel(dp pmetric.HistogramDataPoint, unit string) {
@@ -182,7 +198,34 @@ func (sd *SEH1Distribution) ConvertFromOtel(dp pmetric.HistogramDataPoint, unit
 	for i := 0; i < dp.ExplicitBounds().Len(); i++ {
 		k := dp.ExplicitBounds().At(i)
 		v := dp.BucketCounts().At(i)
-		sd.buckets[int16(k)] = float64(v)
+		if k > math.MaxInt16 {
+			sd.negativeBuckets[int16(k-negativeBucketOffset)] = float64(v)
+		} else {
+			sd.buckets[int16(k)] = float64(v)
+		}
+	}
+}
+
+// ConvertFromOtelExponential maps each bucket of the exponential histogram
+// onto the SEH1 bucket containing its middle value.
+func (sd *SEH1Distribution) ConvertFromOtelExponential(dp pmetric.ExponentialHistogramDataPoint, unit string, unitScale float64) {
+	sd.sampleCount = float64(dp.Count())
+	sd.sum = dp.Sum() * unitScale
+	sd.unit = unit
+	distribution.ExponentialBucketValues(dp, unitScale, func(value float64, count uint64) {
+		if value < 0 {
+			sd.negativeBuckets[bucketNumber(-value)] += float64(count)
+		} else {
+			sd.buckets[bucketNumber(value)] += float64(count)
+		}
+		sd.minimum = math.Min(sd.minimum, value)
+		sd.maximum = math.Max(sd.maximum, value)
+	})
+	if dp.HasMin() {
+		sd.minimum = dp.Min() * unitScale
+	}
+	if dp.HasMax() {
+		sd.maximum = dp.Max() * unitScale
 	}
 }
 
@@ -190,11 +233,13 @@ func (seh1Distribution *SEH1Distribution) CanAdd(value float64, sizeLimit int) b
 	if seh1Distribution.Size() < sizeLimit {
 		return true
 	}
-	bucketNumber := bucketNumber(value)
-	if _, ok := seh1Distribution.buckets[bucketNumber]; ok {
-		return true
+	buckets := seh1Distribution.buckets
+	if value < 0 {
+		buckets = seh1Distribution.negativeBuckets
+		value = -value
 	}
-	return false
+	_, ok := buckets[bucketNumber(value)]
+	return ok
 }
 
 func bucketNumber(value float64) int16 {
@@ -205,6 +250,15 @@ func bucketNumber(value float64) int16 {
 	return bucketNumber
 }
 
+// bucketValue returns the value of the middle of the bucket.
+func bucketValue(bucketNumber int16) float64 {
+	if bucketNumber == bucketForZero {
+		return 0
+	}
+	// Add 0.5 to calculate exponent for the middle of the bin
+	return math.Exp((float64(bucketNumber) + 0.5) * bucketFactor)
+}
+
 // This method is faster than math.Floor
 func floor(fvalue float64) int64 {
 	ivalue := int64(fvalue)
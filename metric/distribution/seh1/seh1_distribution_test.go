@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
 )
@@ -82,7 +83,6 @@ func TestSEH1Distribution(t *testing.T) {
 	assert.Equal(t, dist, anotherDist) //the direction of AddDistribution should not matter.
 
 	assert.ErrorIs(t, anotherDist.AddEntry(1, 0), distribution.ErrUnsupportedWeight)
-	assert.ErrorIs(t, anotherDist.AddEntry(-1, 1), distribution.ErrUnsupportedValue)
 	assert.ErrorIs(t, anotherDist.AddEntry(math.NaN(), 1), distribution.ErrUnsupportedValue)
 	assert.ErrorIs(t, anotherDist.AddEntry(math.Inf(1), 1), distribution.ErrUnsupportedValue)
 	assert.ErrorIs(t, anotherDist.AddEntry(math.Inf(-1), 1), distribution.ErrUnsupportedValue)
@@ -90,18 +90,113 @@ func TestSEH1Distribution(t *testing.T) {
 	assert.ErrorIs(t, anotherDist.AddEntry(distribution.MinValue*1.001, 1), distribution.ErrUnsupportedValue)
 }
 
+func TestSEH1DistributionNegative(t *testing.T) {
+	dist := NewSEH1Distribution()
+	assert.NoError(t, dist.AddEntry(-20, 1))
+	assert.NoError(t, dist.AddEntry(-30, 1))
+	assert.NoError(t, dist.AddEntry(0, 1))
+	assert.NoError(t, dist.AddEntry(20, 2))
+
+	assert.Equal(t, -10.0, dist.Sum())
+	assert.Equal(t, 5.0, dist.SampleCount())
+	assert.Equal(t, -30.0, dist.Minimum())
+	assert.Equal(t, 20.0, dist.Maximum())
+	assert.Equal(t, 4, dist.Size())
+	values, counts := dist.ValuesAndCounts()
+	assert.Equal(t, len(values), len(counts))
+	valuesCountsMap := map[string]float64{}
+	for i := 0; i < len(values); i++ {
+		valuesCountsMap[truncate(values[i])] = counts[i]
+	}
+	expectedValuesCountsMap := map[string]float64{"-20.13119624": 1, "-29.47408442": 1, "0": 1, "20.13119624": 2}
+	assert.Equal(t, expectedValuesCountsMap, valuesCountsMap)
+
+	negativeOnly := NewSEH1Distribution()
+	assert.NoError(t, negativeOnly.AddEntry(-5, 1))
+	assert.Equal(t, -5.0, negativeOnly.Maximum())
+	assert.Equal(t, -5.0, negativeOnly.Minimum())
+
+	dist.AddDistribution(negativeOnly)
+	assert.Equal(t, -15.0, dist.Sum())
+	assert.Equal(t, 5, dist.Size())
+
+	seh1Dist := dist.(*SEH1Distribution)
+	assert.True(t, seh1Dist.CanAdd(-21, 5))
+	assert.True(t, seh1Dist.CanAdd(21, 5))
+	assert.False(t, seh1Dist.CanAdd(-100, 5))
+}
+
+func TestSEH1DistributionOtelRoundTrip(t *testing.T) {
+	dist := NewSEH1Distribution()
+	assert.NoError(t, dist.AddEntryWithUnit(-1000, 1, "Count"))
+	assert.NoError(t, dist.AddEntry(-0.001, 2))
+	assert.NoError(t, dist.AddEntry(0, 3))
+	assert.NoError(t, dist.AddEntry(0.001, 4))
+	assert.NoError(t, dist.AddEntry(1000, 5))
+
+	dp := pmetric.NewHistogramDataPoint()
+	dist.ConvertToOtel(dp)
+	assert.Equal(t, dist.Size(), dp.ExplicitBounds().Len())
+	assert.Equal(t, dist.Size(), dp.BucketCounts().Len())
+
+	got := NewSEH1Distribution()
+	got.ConvertFromOtel(dp, "Count")
+	assert.Equal(t, dist, got)
+}
+
+func TestSEH1DistributionConvertFromOtelExponential(t *testing.T) {
+	dp := pmetric.NewExponentialHistogramDataPoint()
+	dp.SetScale(3)
+	dp.SetCount(10)
+	dp.SetSum(123.4)
+	dp.SetMin(-3)
+	dp.SetMax(40)
+	dp.SetZeroCount(1)
+	dp.Positive().SetOffset(20)
+	dp.Positive().BucketCounts().FromRaw([]uint64{2, 0, 4})
+	dp.Negative().SetOffset(10)
+	dp.Negative().BucketCounts().FromRaw([]uint64{3})
+
+	dist := NewSEH1Distribution()
+	dist.ConvertFromOtelExponential(dp, "Count", 1)
+	assert.Equal(t, 10.0, dist.SampleCount())
+	assert.Equal(t, 123.4, dist.Sum())
+	assert.Equal(t, -3.0, dist.Minimum())
+	assert.Equal(t, 40.0, dist.Maximum())
+	assert.Equal(t, "Count", dist.Unit())
+	values, counts := dist.ValuesAndCounts()
+	assert.Len(t, values, 4)
+	var total float64
+	for i := range values {
+		total += counts[i]
+	}
+	assert.Equal(t, 10.0, total)
+
+	// Without min and max, they are estimated from the buckets.
+	dp.RemoveMin()
+	dp.RemoveMax()
+	dist = NewSEH1Distribution()
+	dist.ConvertFromOtelExponential(dp, "", 1)
+	assert.InDelta(t, -math.Exp2(10.5/8), dist.Minimum(), 1e-9)
+	assert.InDelta(t, math.Exp2(22.5/8), dist.Maximum(), 1e-9)
+}
+
 func cloneSEH1Distribution(dist *SEH1Distribution) *SEH1Distribution {
 	clonedDist := &SEH1Distribution{
-		maximum:     dist.maximum,
-		minimum:     dist.minimum,
-		sampleCount: dist.sampleCount,
-		sum:         dist.sum,
-		buckets:     map[int16]float64{},
-		unit:        dist.unit,
+		maximum:         dist.maximum,
+		minimum:         dist.minimum,
+		sampleCount:     dist.sampleCount,
+		sum:             dist.sum,
+		buckets:         map[int16]float64{},
+		negativeBuckets: map[int16]float64{},
+		unit:            dist.unit,
 	}
 	for k, v := range dist.buckets {
 		clonedDist.buckets[k] = v
 	}
+	for k, v := range dist.negativeBuckets {
+		clonedDist.negativeBuckets[k] = v
+	}
 	return clonedDist
 }
 
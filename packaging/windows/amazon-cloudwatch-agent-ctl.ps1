@@ -14,6 +14,15 @@ Param (
     [string]$Mode = 'ec2',
     [Parameter(Mandatory = $false)]
     [string]$LogLevel = '',
+    [Parameter(Mandatory = $false)]
+    [Alias('k')]
+    [string]$ExpectedChecksum = '',
+    [Parameter(Mandatory = $false)]
+    [Alias('g')]
+    [string]$DetachedSignature = '',
+    [Parameter(Mandatory = $false)]
+    [Alias('p')]
+    [string]$PublicKey = '',
     [parameter(ValueFromRemainingArguments=$true)]
     $unsupportedVars
 )
@@ -27,7 +36,10 @@ $UsageString = @"
         usage:  amazon-cloudwatch-agent-ctl.ps1 -a
                 stop|start|status|fetch-config|append-config|remove-config|set-log-level
                 [-m ec2|onPremise|onPrem|auto]
-                [-c default|all|ssm:<parameter-store-name>|file:<file-path>]
+                [-c default|all|ssm:<parameter-store-name>|file:<file-path>|s3://<bucket>/<key>|https://<url>|appconfig:<application>/<environment>/<profile>]
+                [-k <algorithm>:<hex>]
+                [-g <signature-location>]
+                [-p <public-key-path>]
                 [-s]
                 [-l INFO|DEBUG|WARN|ERROR|OFF]
 
@@ -57,8 +69,20 @@ $UsageString = @"
             default:                                default configuration for quick trial.
             ssm:<parameter-store-name>:             ssm parameter store name.
             file:<file-path>:                       file path on the host.
+            s3://<bucket>/<key>:                    s3 object, re-downloaded only when its ETag changes.
+            https://<url>:                          https url.
+            appconfig:<application>/<environment>/<profile>:
+                                                    AWS AppConfig configuration profile.
             all:                                    all existing configs. Only apply to remove-config action.
 
+        -k: expected checksum of the config downloaded by -c, e.g. sha256:<hex>. Supported algorithms are sha256 and sha512.
+            this parameter is used for 'fetch-config', 'append-config' action only.
+
+        -g: location of a detached signature of the config downloaded by -c, in the same format as -c.
+            this parameter is used for 'fetch-config', 'append-config' action only and requires -p.
+
+        -p: path of the PEM encoded public key used to verify the signature given by -g.
+
         -s: optionally restart after configuring the agent configuration
             this parameter is used for 'fetch-config', 'append-config', 'remove-config' action only.
 
@@ -301,7 +325,17 @@ Function CWAConfig() {
     if ($ConfigLocation -eq $AllConfig) {
         Remove-Item -Path "${JSON_DIR}\*" -Force -ErrorAction SilentlyContinue
     } else {
-        & $CWAProgramFiles\config-downloader.exe --output-dir "${JSON_DIR}" --download-source "${ConfigLocation}" --mode "${param_mode}" --config "${COMMON_CONIG}" --multi-config "${multi_config}"
+        $downloaderArgs = @('--output-dir', "${JSON_DIR}", '--download-source', "${ConfigLocation}", '--mode', "${param_mode}", '--config', "${COMMON_CONIG}", '--multi-config', "${multi_config}")
+        if ($ExpectedChecksum) {
+            $downloaderArgs += @('--checksum', "${ExpectedChecksum}")
+        }
+        if ($DetachedSignature) {
+            $downloaderArgs += @('--signature', "${DetachedSignature}")
+        }
+        if ($PublicKey) {
+            $downloaderArgs += @('--public-key', "${PublicKey}")
+        }
+        & $CWAProgramFiles\config-downloader.exe @downloaderArgs
         CheckCMDResult
     }
 
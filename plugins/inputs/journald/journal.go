@@ -0,0 +1,448 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+// +build linux
+
+package journald
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+const (
+	cursorField            = "__CURSOR"
+	realtimeTimestampField = "__REALTIME_TIMESTAMP"
+	messageField           = "MESSAGE"
+
+	unknownFieldValue = "unknown"
+	srcBufferSize     = 100
+)
+
+var (
+	journalctlPath    = "journalctl"
+	restartDelay      = 5 * time.Second
+	saveStateInterval = 100 * time.Millisecond
+
+	// fieldPlaceholder matches journal fields like {_SYSTEMD_UNIT} in the log
+	// group and log stream names.
+	fieldPlaceholder = regexp.MustCompile(`\{([A-Z0-9_]+)\}`)
+)
+
+type offset struct {
+	seq    uint64
+	cursor string
+}
+
+// journalReader follows the journal with journalctl and routes the entries
+// to a journalSrc per log group and log stream.
+type journalReader struct {
+	cfg            JournalConfig
+	stateFilePath  string
+	journalctlPath string
+	restartDelay   time.Duration
+
+	// cursor is the position to continue reading the journal from.
+	cursor string
+	seq    uint64
+
+	mu      sync.Mutex
+	srcs    map[string]*journalSrc
+	pending []logs.LogSrc
+	active  sync.WaitGroup
+
+	offsetCh chan offset
+	done     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func newJournalReader(cfg JournalConfig, stateFilePath string) *journalReader {
+	return &journalReader{
+		cfg:            cfg,
+		stateFilePath:  stateFilePath,
+		journalctlPath: journalctlPath,
+		restartDelay:   restartDelay,
+		srcs:           make(map[string]*journalSrc),
+		offsetCh:       make(chan offset, 100),
+		done:           make(chan struct{}),
+		stopped:        make(chan struct{}),
+	}
+}
+
+func (r *journalReader) Init() {
+	r.loadState()
+	go r.runSaveState()
+	go r.run()
+}
+
+func (r *journalReader) Stop() {
+	r.stopOnce.Do(func() { close(r.done) })
+}
+
+func (r *journalReader) description() string {
+	return fmt.Sprintf("journald%v", r.cfg.Units)
+}
+
+// newSrcs returns the log sources created since the last call.
+func (r *journalReader) newSrcs() []logs.LogSrc {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	srcs := r.pending
+	r.pending = nil
+	return srcs
+}
+
+// args builds the journalctl arguments. Without a saved cursor, only new
+// entries are read.
+func (r *journalReader) args() []string {
+	args := []string{"--output=json", "--follow", "--no-pager"}
+	for _, unit := range r.cfg.Units {
+		args = append(args, "--unit="+unit)
+	}
+	if r.cfg.Priority != "" {
+		args = append(args, "--priority="+r.cfg.Priority)
+	}
+	if r.cursor != "" {
+		args = append(args, "--after-cursor="+r.cursor, "--no-tail")
+	} else {
+		args = append(args, "--lines=0")
+	}
+	return append(args, r.cfg.Matches...)
+}
+
+func (r *journalReader) run() {
+	defer func() {
+		// wait for the log agent to stop the sources before the final save
+		r.active.Wait()
+		close(r.stopped)
+	}()
+	for {
+		err := r.follow()
+		select {
+		case <-r.done:
+			return
+		default:
+		}
+		log.Printf("W! [journald] journalctl stopped for %s, restarting in %v: %v", r.description(), r.restartDelay, err)
+		select {
+		case <-time.After(r.restartDelay):
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// follow runs journalctl until it exits or the reader is stopped.
+func (r *journalReader) follow() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-r.done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.journalctlPath, r.args()...)
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err = cmd.Start(); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(stdout)
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			r.handle(line)
+		}
+		if readErr != nil {
+			break
+		}
+	}
+	if err = cmd.Wait(); err != nil && stderr.Len() > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return err
+}
+
+func (r *journalReader) handle(line []byte) {
+	var entry map[string]interface{}
+	if err := json.Unmarshal(line, &entry); err != nil {
+		log.Printf("W! [journald] Failed to parse journal entry for %s: %v", r.description(), err)
+		return
+	}
+	cursor, _ := entry[cursorField].(string)
+	if cursor == "" {
+		return
+	}
+	r.cursor = cursor
+	r.seq++
+
+	msg, err := r.format(entry)
+	if err != nil {
+		log.Printf("W! [journald] Failed to format journal entry for %s: %v", r.description(), err)
+		return
+	}
+	group := resolveFields(r.cfg.LogGroupName, entry)
+	stream := resolveFields(r.cfg.LogStreamName, entry)
+	src := r.getSrc(group, stream)
+	e := &LogEvent{
+		msg:    msg,
+		t:      entryTime(entry),
+		offset: offset{seq: r.seq, cursor: cursor},
+		reader: r,
+	}
+	select {
+	case src.events <- e:
+	case <-src.done:
+	case <-r.done:
+	}
+}
+
+// format returns the message of the entry, or the entire entry without the
+// journal's internal fields in the JSON format.
+func (r *journalReader) format(entry map[string]interface{}) (string, error) {
+	if r.cfg.EventFormat != EventFormatJSON {
+		return fieldValue(entry[messageField]), nil
+	}
+	fields := make(map[string]string, len(entry))
+	for key, value := range entry {
+		if strings.HasPrefix(key, "__") {
+			continue
+		}
+		fields[key] = fieldValue(value)
+	}
+	out, err := json.Marshal(fields)
+	return string(out), err
+}
+
+func (r *journalReader) getSrc(group, stream string) *journalSrc {
+	key := group + "\n" + stream
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if src, ok := r.srcs[key]; ok {
+		return src
+	}
+	src := &journalSrc{
+		group:  group,
+		stream: stream,
+		reader: r,
+		events: make(chan *LogEvent, srcBufferSize),
+		done:   make(chan struct{}),
+	}
+	r.active.Add(1)
+	r.srcs[key] = src
+	r.pending = append(r.pending, src)
+	return src
+}
+
+func (r *journalReader) Done(o offset) {
+	select {
+	case r.offsetCh <- o:
+	case <-r.stopped:
+	}
+}
+
+func (r *journalReader) runSaveState() {
+	t := time.NewTicker(saveStateInterval)
+	defer t.Stop()
+
+	var last, saved offset
+	for {
+		select {
+		case o := <-r.offsetCh:
+			if o.seq > last.seq {
+				last = o
+			}
+		case <-t.C:
+			if last.seq == saved.seq {
+				continue
+			}
+			if err := r.saveState(last.cursor); err != nil {
+				log.Printf("E! [journald] Error happened when saving journal cursor of %s to %s: %v", r.description(), r.stateFilePath, err)
+				continue
+			}
+			saved = last
+		case <-r.stopped:
+			for len(r.offsetCh) > 0 {
+				if o := <-r.offsetCh; o.seq > last.seq {
+					last = o
+				}
+			}
+			if last.seq == saved.seq {
+				return
+			}
+			if err := r.saveState(last.cursor); err != nil {
+				log.Printf("E! [journald] Error happened during final saving of journal cursor of %s to %s, duplicate log maybe sent at next start: %v", r.description(), r.stateFilePath, err)
+			}
+			return
+		}
+	}
+}
+
+func (r *journalReader) saveState(cursor string) error {
+	if r.stateFilePath == "" || cursor == "" {
+		return nil
+	}
+	content := []byte(cursor + "\n" + r.description())
+	return os.WriteFile(r.stateFilePath, content, 0644)
+}
+
+func (r *journalReader) loadState() {
+	if r.stateFilePath == "" {
+		return
+	}
+	content, err := os.ReadFile(r.stateFilePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("W! [journald] Failed to read journal cursor of %s from %s: %v", r.description(), r.stateFilePath, err)
+		}
+		return
+	}
+	r.cursor = strings.TrimSpace(strings.SplitN(string(content), "\n", 2)[0])
+}
+
+// resolveFields replaces the journal field placeholders in the template with
+// the values of the entry.
+func resolveFields(template string, entry map[string]interface{}) string {
+	return fieldPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		value, ok := entry[placeholder[1:len(placeholder)-1]]
+		if !ok {
+			return unknownFieldValue
+		}
+		return fieldValue(value)
+	})
+}
+
+// fieldValue converts a journal field to a string. journalctl outputs fields
+// that are not valid UTF-8 as arrays of bytes.
+func fieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		b := make([]byte, 0, len(v))
+		for _, c := range v {
+			if n, ok := c.(float64); ok {
+				b = append(b, byte(n))
+			}
+		}
+		return string(b)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func entryTime(entry map[string]interface{}) time.Time {
+	if usec, err := strconv.ParseInt(fieldValue(entry[realtimeTimestampField]), 10, 64); err == nil {
+		return time.UnixMicro(usec)
+	}
+	return time.Now()
+}
+
+// journalSrc is the log source of the journal entries mapped to a single log
+// group and log stream.
+type journalSrc struct {
+	group  string
+	stream string
+	reader *journalReader
+
+	events    chan *LogEvent
+	outputFn  func(logs.LogEvent)
+	startOnce sync.Once
+	done      chan struct{}
+	stopOnce  sync.Once
+}
+
+var _ logs.LogSrc = (*journalSrc)(nil)
+
+func (s *journalSrc) SetOutput(fn func(logs.LogEvent)) {
+	if fn == nil {
+		return
+	}
+	s.outputFn = fn
+	s.startOnce.Do(func() { go s.run() })
+}
+
+func (s *journalSrc) run() {
+	for {
+		select {
+		case e := <-s.events:
+			s.outputFn(e)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *journalSrc) Group() string {
+	return s.group
+}
+
+func (s *journalSrc) Stream() string {
+	return s.stream
+}
+
+func (s *journalSrc) Destination() string {
+	return s.reader.cfg.Destination
+}
+
+func (s *journalSrc) Description() string {
+	return s.reader.description()
+}
+
+func (s *journalSrc) Retention() int {
+	return s.reader.cfg.Retention
+}
+
+func (s *journalSrc) Class() string {
+	return s.reader.cfg.LogGroupClass
+}
+
+func (s *journalSrc) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+		s.reader.active.Done()
+	})
+}
+
+type LogEvent struct {
+	msg    string
+	t      time.Time
+	offset offset
+	reader *journalReader
+}
+
+func (le LogEvent) Message() string {
+	return le.msg
+}
+
+func (le LogEvent) Time() time.Time {
+	return le.t
+}
+
+func (le LogEvent) Done() {
+	le.reader.Done(le.offset)
+}
@@ -0,0 +1,142 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+// +build linux
+
+package journald
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logscommon"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+const (
+	EventFormatText = "text"
+	EventFormatJSON = "json"
+)
+
+type JournalConfig struct {
+	Units         []string `toml:"units"`
+	Priority      string   `toml:"priority"`
+	Matches       []string `toml:"matches"`
+	EventFormat   string   `toml:"event_format"`
+	LogGroupName  string   `toml:"log_group_name"`
+	LogStreamName string   `toml:"log_stream_name"`
+	LogGroupClass string   `toml:"log_group_class"`
+	Destination   string   `toml:"destination"`
+	Retention     int      `toml:"retention_in_days"`
+}
+
+type Plugin struct {
+	FileStateFolder string          `toml:"file_state_folder"`
+	Journals        []JournalConfig `toml:"journal_config"`
+	Destination     string          `toml:"destination"`
+	Log             telegraf.Logger `toml:"-"`
+
+	startOnce sync.Once
+	readers   []*journalReader
+}
+
+func (p *Plugin) Description() string {
+	return "A plugin to collect entries from the systemd journal"
+}
+
+func (p *Plugin) SampleConfig() string {
+	return `
+	file_state_folder = "/path/to/state/folder"
+
+	[[inputs.journald.journal_config]]
+	units = ["sshd.service"]
+	priority = "info"
+	matches = ["_TRANSPORT=syslog"]
+	event_format = "text"
+	log_group_name = "journal/{_SYSTEMD_UNIT}"
+	log_stream_name = "STREAM_NAME"
+	destination = "cloudwatchlogs"
+	`
+}
+
+func (p *Plugin) Gather(acc telegraf.Accumulator) error {
+	return nil
+}
+
+// FindLogSrc returns a log source for each log group and log stream that the
+// journal entries have been mapped to since the last call.
+func (p *Plugin) FindLogSrc() []logs.LogSrc {
+	var srcs []logs.LogSrc
+	for _, r := range p.readers {
+		srcs = append(srcs, r.newSrcs()...)
+	}
+	return srcs
+}
+
+// Start is called by both the log agent and telegraf, so the journal readers
+// are only started once.
+func (p *Plugin) Start(acc telegraf.Accumulator) error {
+	var err error
+	p.startOnce.Do(func() {
+		for i := range p.Journals {
+			cfg := p.Journals[i]
+			if cfg.Destination == "" {
+				cfg.Destination = p.Destination
+			}
+			var stateFilePath string
+			stateFilePath, err = getStateFilePath(p, &cfg)
+			if err != nil {
+				return
+			}
+			r := newJournalReader(cfg, stateFilePath)
+			r.Init()
+			p.readers = append(p.readers, r)
+		}
+	})
+	return err
+}
+
+// Stop stops reading the journal. The log sources are stopped by the log
+// agent after the output plugin is stopped so that the final cursor is
+// saved accurately.
+func (p *Plugin) Stop() {
+	for _, r := range p.readers {
+		r.Stop()
+	}
+}
+
+// getStateFilePath returns a unique file pathname for a given JournalConfig.
+func getStateFilePath(plugin *Plugin, cfg *JournalConfig) (string, error) {
+	if plugin.FileStateFolder == "" {
+		return "", errors.New("empty FileStateFolder")
+	}
+	err := os.MkdirAll(plugin.FileStateFolder, 0755)
+	if err != nil {
+		return "", err
+	}
+	stateFileName := logscommon.JournaldPrefix +
+		escapeFileName(cfg.LogGroupName+"_"+cfg.LogStreamName+"_"+strings.Join(cfg.Units, "_"))
+	return filepath.Join(plugin.FileStateFolder, stateFileName), nil
+}
+
+// escapeFileName returns a valid filename string.
+func escapeFileName(filePath string) string {
+	escapedFilePath := filepath.ToSlash(filePath)
+	escapedFilePath = strings.Replace(escapedFilePath, "/", "_", -1)
+	escapedFilePath = strings.Replace(escapedFilePath, " ", "_", -1)
+	escapedFilePath = strings.Replace(escapedFilePath, ":", "_", -1)
+	escapedFilePath = strings.Replace(escapedFilePath, "{", "", -1)
+	escapedFilePath = strings.Replace(escapedFilePath, "}", "", -1)
+	return escapedFilePath
+}
+
+func init() {
+	inputs.Add("journald", func() telegraf.Input { return &Plugin{} })
+}
@@ -0,0 +1,200 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+// +build linux
+
+package journald
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+const (
+	sshdEntry   = `{"__CURSOR":"s=1;i=1","__REALTIME_TIMESTAMP":"1700000000000000","_SYSTEMD_UNIT":"sshd.service","PRIORITY":"6","MESSAGE":"Accepted publickey"}`
+	cronEntry   = `{"__CURSOR":"s=1;i=2","__REALTIME_TIMESTAMP":"1700000001000000","_SYSTEMD_UNIT":"cron.service","PRIORITY":"6","MESSAGE":"Job started"}`
+	binaryEntry = `{"__CURSOR":"s=1;i=3","__REALTIME_TIMESTAMP":"1700000002000000","_SYSTEMD_UNIT":"sshd.service","PRIORITY":"3","MESSAGE":[104,105]}`
+)
+
+// fakeJournalctl replaces journalctl with a script that records its
+// arguments and prints the entries.
+func fakeJournalctl(t *testing.T, entries ...string) string {
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	entriesFile := filepath.Join(dir, "entries")
+	require.NoError(t, os.WriteFile(entriesFile, []byte(strings.Join(entries, "\n")+"\n"), 0644))
+	script := filepath.Join(dir, "journalctl")
+	content := fmt.Sprintf("#!/bin/sh\necho \"$@\" >> %s\ncat %s\nsleep 10\n", argsFile, entriesFile)
+	require.NoError(t, os.WriteFile(script, []byte(content), 0755))
+
+	original := journalctlPath
+	journalctlPath = script
+	t.Cleanup(func() { journalctlPath = original })
+	return argsFile
+}
+
+type collector struct {
+	mu     sync.Mutex
+	events map[string][]logs.LogEvent
+}
+
+func (c *collector) collect(src logs.LogSrc) {
+	src.SetOutput(func(e logs.LogEvent) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		key := src.Group() + "/" + src.Stream()
+		c.events[key] = append(c.events[key], e)
+		e.Done()
+	})
+}
+
+func (c *collector) messages(key string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var messages []string
+	for _, e := range c.events[key] {
+		messages = append(messages, e.Message())
+	}
+	return messages
+}
+
+func startPlugin(t *testing.T, plugin *Plugin) (*collector, func()) {
+	require.NoError(t, plugin.Start(nil))
+	// the log agent and telegraf both call Start
+	require.NoError(t, plugin.Start(nil))
+	c := &collector{events: map[string][]logs.LogEvent{}}
+	var srcs []logs.LogSrc
+	stop := func() {
+		plugin.Stop()
+		for _, src := range srcs {
+			src.Stop()
+		}
+	}
+	require.Eventually(t, func() bool {
+		for _, src := range plugin.FindLogSrc() {
+			srcs = append(srcs, src)
+			c.collect(src)
+		}
+		return len(srcs) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	return c, stop
+}
+
+func TestJournald(t *testing.T) {
+	argsFile := fakeJournalctl(t, sshdEntry, cronEntry, binaryEntry)
+	stateFolder := t.TempDir()
+	plugin := &Plugin{
+		FileStateFolder: stateFolder,
+		Destination:     "cloudwatchlogs",
+		Journals: []JournalConfig{{
+			Units:         []string{"sshd.service", "cron.service"},
+			Priority:      "info",
+			Matches:       []string{"_TRANSPORT=syslog"},
+			LogGroupName:  "journal/{_SYSTEMD_UNIT}",
+			LogStreamName: "{_HOSTNAME}",
+			Retention:     7,
+		}},
+	}
+	c, stop := startPlugin(t, plugin)
+
+	require.Eventually(t, func() bool {
+		return len(c.messages("journal/sshd.service/unknown")) == 2 && len(c.messages("journal/cron.service/unknown")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"Accepted publickey", "hi"}, c.messages("journal/sshd.service/unknown"))
+	assert.Equal(t, []string{"Job started"}, c.messages("journal/cron.service/unknown"))
+	assert.Equal(t, time.UnixMicro(1700000001000000), c.events["journal/cron.service/unknown"][0].Time())
+
+	args, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	assert.Equal(t, "--output=json --follow --no-pager --unit=sshd.service --unit=cron.service --priority=info --lines=0 _TRANSPORT=syslog\n", string(args))
+
+	stop()
+	stateFilePath, err := getStateFilePath(plugin, &plugin.Journals[0])
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		content, _ := os.ReadFile(stateFilePath)
+		return strings.HasPrefix(string(content), "s=1;i=3\n")
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestJournaldResumeFromCursor(t *testing.T) {
+	argsFile := fakeJournalctl(t, sshdEntry, cronEntry)
+	stateFolder := t.TempDir()
+	plugin := &Plugin{
+		FileStateFolder: stateFolder,
+		Journals: []JournalConfig{{
+			LogGroupName:  "journal",
+			LogStreamName: "{_SYSTEMD_UNIT}",
+			EventFormat:   EventFormatJSON,
+			Destination:   "cloudwatchlogs",
+		}},
+	}
+	stateFilePath, err := getStateFilePath(plugin, &plugin.Journals[0])
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(stateFilePath, []byte("s=1;i=0\njournald[]"), 0644))
+
+	c, stop := startPlugin(t, plugin)
+	defer stop()
+
+	require.Eventually(t, func() bool {
+		return len(c.messages("journal/sshd.service")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	var fields map[string]string
+	require.NoError(t, json.Unmarshal([]byte(c.messages("journal/sshd.service")[0]), &fields))
+	assert.Equal(t, map[string]string{
+		"_SYSTEMD_UNIT": "sshd.service",
+		"PRIORITY":      "6",
+		"MESSAGE":       "Accepted publickey",
+	}, fields)
+
+	args, err := os.ReadFile(argsFile)
+	require.NoError(t, err)
+	assert.Equal(t, "--output=json --follow --no-pager --after-cursor=s=1;i=0 --no-tail\n", string(args))
+}
+
+func TestJournaldRestart(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "journalctl")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho \"$@\" >> "+filepath.Join(dir, "args")+"\necho '"+sshdEntry+"'\n"), 0755))
+	r := newJournalReader(JournalConfig{LogGroupName: "journal"}, "")
+	r.journalctlPath = script
+	r.restartDelay = 10 * time.Millisecond
+	r.Init()
+	defer r.Stop()
+
+	// continues after the last cursor read when journalctl exits
+	assert.Eventually(t, func() bool {
+		args, _ := os.ReadFile(filepath.Join(dir, "args"))
+		return strings.Contains(string(args), "--after-cursor=s=1;i=1")
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestResolveFields(t *testing.T) {
+	entry := map[string]interface{}{
+		"_SYSTEMD_UNIT": "sshd.service",
+		"_PID":          "42",
+	}
+	testCases := map[string]string{
+		"journal":                    "journal",
+		"journal/{_SYSTEMD_UNIT}":    "journal/sshd.service",
+		"{_SYSTEMD_UNIT}-{_PID}":     "sshd.service-42",
+		"{_HOSTNAME}":                "unknown",
+		"{instance_id}/{_PID}":       "{instance_id}/42",
+		"{_SYSTEMD_UNIT}{_COMM}{_X}": "sshd.serviceunknownunknown",
+	}
+	for template, want := range testCases {
+		assert.Equal(t, want, resolveFields(template, entry), template)
+	}
+}
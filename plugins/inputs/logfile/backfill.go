@@ -0,0 +1,359 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/tail"
+)
+
+const (
+	// maxBackfillAge is the age of the oldest log events accepted by PutLogEvents.
+	maxBackfillAge = 14 * 24 * time.Hour
+
+	defaultBackfillRateLimit = 1000
+
+	// backfillStateDir is the directory in the file state folder with the
+	// rotated files each file config still has to backfill.
+	backfillStateDir = "backfill"
+)
+
+var backfillProgressInterval = 30 * time.Second
+
+// backfillWindow bounds the time of the log events backfilled from the
+// content of the files when they are first found.
+type backfillWindow struct {
+	since, until time.Time
+	// eventsPerSecond is the most log events backfilled per second and file.
+	eventsPerSecond int
+}
+
+// newBackfillWindow parses the since and until bounds, which are either a time
+// in RFC3339 format or a duration before now like "72h". The window does not
+// start before the oldest log events accepted by CloudWatch Logs or the
+// retention of the log group.
+func newBackfillWindow(since, until string, eventsPerSecond, retentionInDays int, now time.Time) (*backfillWindow, error) {
+	w := &backfillWindow{eventsPerSecond: eventsPerSecond}
+	var err error
+	if w.since, err = parseBackfillTime(since, now); err != nil {
+		return nil, fmt.Errorf("backfill_since %s is not a time or a duration: %v", since, err)
+	}
+	if w.until, err = parseBackfillTime(until, now); err != nil {
+		return nil, fmt.Errorf("backfill_until %s is not a time or a duration: %v", until, err)
+	}
+	if !w.until.IsZero() && w.until.Before(w.since) {
+		return nil, fmt.Errorf("backfill_until %s is before backfill_since %s", until, since)
+	}
+	oldest := now.Add(-maxBackfillAge)
+	if retentionInDays > 0 {
+		if retention := now.AddDate(0, 0, -retentionInDays); retention.After(oldest) {
+			oldest = retention
+		}
+	}
+	if w.since.Before(oldest) {
+		log.Printf("W! [logfile] backfill_since %s is before %s, older log events are not accepted by CloudWatch Logs and are skipped", since, oldest.Format(time.RFC3339))
+		w.since = oldest
+	}
+	if w.eventsPerSecond <= 0 {
+		w.eventsPerSecond = defaultBackfillRateLimit
+	}
+	return w, nil
+}
+
+func parseBackfillTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// contains reports whether the time is in the window. The log events without a
+// timestamp are always backfilled.
+func (w *backfillWindow) contains(t time.Time) bool {
+	if t.IsZero() {
+		return true
+	}
+	return !t.Before(w.since) && (w.until.IsZero() || !t.After(w.until))
+}
+
+// backfill tracks the backfill of the content of a file, which is throttled so
+// that it does not starve the tailing of the other files.
+type backfill struct {
+	window   *backfillWindow
+	filename string
+	// end is the size of the file when the backfill started, the content past
+	// it is tailed as usual.
+	end       int64
+	limiter   *rate.Limiter
+	sent      int
+	skipped   int
+	started   time.Time
+	lastLog   time.Time
+	completed bool
+}
+
+func newBackfill(window *backfillWindow, filename string) *backfill {
+	fi, err := os.Stat(filename)
+	if err != nil || fi.Size() == 0 {
+		return nil
+	}
+	now := time.Now()
+	log.Printf("I! [logfile] Backfilling %d bytes of file %s", fi.Size(), filename)
+	return &backfill{
+		window:   window,
+		filename: filename,
+		end:      fi.Size(),
+		limiter:  rate.NewLimiter(rate.Limit(window.eventsPerSecond), window.eventsPerSecond),
+		started:  now,
+		lastLog:  now,
+	}
+}
+
+// admit reports whether the log event is sent, and waits until it can be sent
+// without going over the rate limit of the backfill.
+func (b *backfill) admit(e *LogEvent, done <-chan struct{}) bool {
+	if b.completed || e.offset.offset > b.end {
+		b.complete()
+		return true
+	}
+	defer func() {
+		if e.offset.offset >= b.end {
+			b.complete()
+		} else if time.Since(b.lastLog) >= backfillProgressInterval {
+			b.lastLog = time.Now()
+			log.Printf("I! [logfile] Backfilling file %s, read %d of %d bytes, sent %d log events, skipped %d log events outside of the time window", b.filename, e.offset.offset, b.end, b.sent, b.skipped)
+		}
+	}()
+	if !b.window.contains(e.t) {
+		b.skipped++
+		return false
+	}
+	if d := b.limiter.Reserve().Delay(); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-done:
+		}
+	}
+	b.sent++
+	return true
+}
+
+// complete logs the completion of the backfill once.
+func (b *backfill) complete() {
+	if b.completed {
+		return
+	}
+	b.completed = true
+	log.Printf("I! [logfile] Backfilled file %s in %v, sent %d log events, skipped %d log events outside of the time window", b.filename, time.Since(b.started).Round(time.Second), b.sent, b.skipped)
+}
+
+// backfillQueue is the rotated files of a file config that are backfilled one
+// after the other, oldest first, and then the target files found on the first
+// scan, whose backfill waits for the rotated files.
+type backfillQueue struct {
+	files   []string
+	current string
+	targets []*backfill
+	// statePath is the file the pending rotated files are saved to, so that
+	// the backfill resumes after a restart.
+	statePath string
+}
+
+// queued reports whether the target file waits for the rotated files.
+func (q *backfillQueue) queued(filename string) bool {
+	for _, b := range q.targets {
+		if b.filename == filename {
+			return true
+		}
+	}
+	return false
+}
+
+// saveState saves the rotated files that are not backfilled yet, including
+// the current one, and removes the state file once all are.
+func (q *backfillQueue) saveState() {
+	if q.statePath == "" {
+		return
+	}
+	pending := q.files
+	if q.current != "" {
+		pending = append([]string{q.current}, q.files...)
+	}
+	if len(pending) == 0 {
+		if err := os.Remove(q.statePath); err != nil && !os.IsNotExist(err) {
+			log.Printf("W! [logfile] Failed to remove the backfill state file %s: %v", q.statePath, err)
+		}
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(q.statePath), 0755); err != nil {
+		log.Printf("W! [logfile] Failed to create the backfill state directory %s: %v", filepath.Dir(q.statePath), err)
+		return
+	}
+	if err := os.WriteFile(q.statePath, []byte(strings.Join(pending, "\n")), stateFileMode); err != nil {
+		log.Printf("W! [logfile] Failed to save the backfill state file %s: %v", q.statePath, err)
+	}
+}
+
+// restoreState returns the rotated files that were not backfilled before the
+// restart and still exist.
+func (q *backfillQueue) restoreState() ([]string, bool) {
+	if q.statePath == "" {
+		return nil, false
+	}
+	content, err := os.ReadFile(q.statePath)
+	if err != nil {
+		return nil, false
+	}
+	var files []string
+	for _, filename := range strings.Split(string(content), "\n") {
+		if _, err = os.Stat(filename); filename != "" && err == nil {
+			files = append(files, filename)
+		}
+	}
+	return files, true
+}
+
+// getBackfillStatePath returns the path of the backfill state file of the file
+// config, named after a hash of the file path, log group and log stream.
+func (t *LogFile) getBackfillStatePath(fileconfig *FileConfig) string {
+	if t.FileStateFolder == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(fileconfig.FilePath + "\n" + fileconfig.LogGroupName + "\n" + fileconfig.LogStreamName))
+	return filepath.Join(t.FileStateFolder, backfillStateDir, hex.EncodeToString(sum[:8]))
+}
+
+// newBackfillQueue returns the files that match the file config, other than the
+// target files, and the backfill of the target files. The rotated files still
+// pending before a restart are restored. Otherwise no rotated files are
+// backfilled when the state of one of the target files was restored, since the
+// files were backfilled when the agent first tailed them.
+func (t *LogFile) newBackfillQueue(fileconfig *FileConfig, targetFiles []string, targets []*backfill, restored bool) *backfillQueue {
+	q := &backfillQueue{targets: targets, statePath: t.getBackfillStatePath(fileconfig)}
+	if files, ok := q.restoreState(); ok {
+		q.files = files
+		t.Log.Infof("Resuming the backfill of %d rotated files of file config %v", len(q.files), fileconfig.FilePath)
+		return q
+	}
+	if restored {
+		return q
+	}
+	matchedFiles, err := t.getMatchedFiles(fileconfig)
+	if err != nil {
+		t.Log.Errorf("Failed to find the files to backfill for file config %v, with error: %v", fileconfig.FilePath, err)
+		return q
+	}
+	isTarget := make(map[string]struct{}, len(targetFiles))
+	for _, filename := range targetFiles {
+		isTarget[filename] = struct{}{}
+	}
+	for _, f := range matchedFiles {
+		// the files modified before the window have no log events in it
+		if _, ok := isTarget[f.name]; ok || f.modTime.Before(fileconfig.backfill.since) {
+			continue
+		}
+		q.files = append(q.files, f.name)
+	}
+	if len(q.files) > 0 {
+		t.Log.Infof("Backfilling %d rotated files of file config %v", len(q.files), fileconfig.FilePath)
+		q.saveState()
+	}
+	return q
+}
+
+// nextBackfillSrcs starts reading the next rotated file of the file config once
+// the previous one was read, and tails the target files once all were read.
+func (t *LogFile) nextBackfillSrcs(fileconfig *FileConfig) []logs.LogSrc {
+	q := t.backfills[fileconfig]
+	if q == nil {
+		return nil
+	}
+	dests := t.configs[fileconfig]
+	if q.current != "" {
+		if _, ok := dests[q.current]; ok {
+			return nil
+		}
+		q.current = ""
+		q.saveState()
+	}
+	for len(q.files) > 0 {
+		if t.openFileLimitReached(fileconfig) {
+			return nil
+		}
+		filename := q.files[0]
+		q.files = q.files[1:]
+		if _, ok := dests[filename]; ok {
+			continue
+		}
+		src, err := t.tailFile(fileconfig, filename, t.restoredSeekInfo(filename), false)
+		if err != nil {
+			t.Log.Errorf("Failed to backfill file %v with error: %v", filename, err)
+			continue
+		}
+		src.backfill = newBackfill(fileconfig.backfill, filename)
+		dests[filename] = src
+		q.current = filename
+		q.saveState()
+		return []logs.LogSrc{src}
+	}
+	var srcs []logs.LogSrc
+	for len(q.targets) > 0 {
+		if t.openFileLimitReached(fileconfig) {
+			break
+		}
+		b := q.targets[0]
+		q.targets = q.targets[1:]
+		if _, ok := dests[b.filename]; ok {
+			continue
+		}
+		src, err := t.tailFile(fileconfig, b.filename, nil, true)
+		if err != nil {
+			t.Log.Errorf("Failed to tail file %v with error: %v", b.filename, err)
+			continue
+		}
+		src.backfill = b
+		dests[b.filename] = src
+		srcs = append(srcs, src)
+	}
+	return srcs
+}
+
+// openFileLimitReached checks the open file limit, and has the file config
+// scanned again once files are closed.
+func (t *LogFile) openFileLimitReached(fileconfig *FileConfig) bool {
+	if t.MaxOpenFiles <= 0 || tail.OpenFileCount.Load() < int64(t.MaxOpenFiles) {
+		return false
+	}
+	if t.discovery != nil {
+		t.discovery.markDirty(fileconfig)
+	}
+	return true
+}
+
+// restoredSeekInfo returns where to resume reading the file from its state
+// file, nil to read it from the start.
+func (t *LogFile) restoredSeekInfo(filename string) *tail.SeekInfo {
+	offset, err := t.restoreState(filename)
+	if err != nil {
+		return nil
+	}
+	return &tail.SeekInfo{Whence: io.SeekStart, Offset: offset}
+}
@@ -0,0 +1,172 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+func TestNewBackfillWindow(t *testing.T) {
+	now := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+
+	w, err := newBackfillWindow("72h", "2024-03-10T06:00:00Z", 0, 0, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-72*time.Hour), w.since)
+	assert.Equal(t, now.Add(-6*time.Hour), w.until)
+	assert.Equal(t, defaultBackfillRateLimit, w.eventsPerSecond)
+	assert.True(t, w.contains(now.Add(-24*time.Hour)))
+	assert.True(t, w.contains(time.Time{}))
+	assert.False(t, w.contains(now.Add(-73*time.Hour)))
+	assert.False(t, w.contains(now.Add(-time.Hour)))
+
+	// the window does not start before the oldest accepted log events
+	w, err = newBackfillWindow("2024-01-01T00:00:00Z", "", 10, 0, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-maxBackfillAge), w.since)
+	assert.True(t, w.until.IsZero())
+	assert.Equal(t, 10, w.eventsPerSecond)
+	w, err = newBackfillWindow("240h", "", 10, 3, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, -3), w.since)
+
+	_, err = newBackfillWindow("yesterday", "", 0, 0, now)
+	assert.ErrorContains(t, err, "backfill_since yesterday is not a time or a duration")
+	_, err = newBackfillWindow("1h", "2h", 0, 0, now)
+	assert.EqualError(t, err, "backfill_until 2h is before backfill_since 1h")
+
+	config := &FileConfig{FilePath: "/var/log/app.log", BackfillUntil: "1h"}
+	assert.EqualError(t, config.init(), "backfill_until 1h is set without backfill_since")
+}
+
+func TestBackfill(t *testing.T) {
+	multilineWaitPeriod = 10 * time.Millisecond
+	dir := t.TempDir()
+	now := time.Now().UTC().Truncate(time.Second)
+	line := func(ago time.Duration, msg string) string {
+		return now.Add(-ago).Format(time.RFC3339) + " " + msg + "\n"
+	}
+	files := []struct {
+		name    string
+		modTime time.Duration
+		content string
+	}{
+		{"app.log.3", 5 * time.Hour, line(6*time.Hour, "too old")},
+		{"app.log.2", 90 * time.Minute, line(3*time.Hour, "before since") + line(100*time.Minute, "rotated 2")},
+		{"app.log.1", 50 * time.Minute, line(60*time.Minute, "rotated 1")},
+		{"app.log", 0, line(40*time.Minute, "live") + line(10*time.Minute, "after until")},
+	}
+	for _, f := range files {
+		filename := filepath.Join(dir, f.name)
+		require.NoError(t, os.WriteFile(filename, []byte(f.content), 0644))
+		modTime := now.Add(-f.modTime)
+		require.NoError(t, os.Chtimes(filename, modTime, modTime))
+	}
+
+	tt := NewLogFile()
+	tt.Log = TestLogger{t}
+	tt.FileConfig = []FileConfig{{
+		FilePath:          filepath.Join(dir, "app.log*"),
+		TimestampRegex:    `^(\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z)`,
+		TimestampLayout:   []string{time.RFC3339},
+		Timezone:          "UTC",
+		BackfillSince:     "2h",
+		BackfillUntil:     "30m",
+		BackfillRateLimit: 100,
+	}}
+	require.NoError(t, tt.FileConfig[0].init())
+	tt.started = true
+	defer tt.Stop()
+
+	events := make(chan logs.LogEvent, 10)
+	var srcs []logs.LogSrc
+	defer func() {
+		for _, src := range srcs {
+			src.Stop()
+		}
+	}()
+	var msgs []string
+	appended := false
+	for deadline := time.Now().Add(10 * time.Second); len(msgs) < 4 && time.Now().Before(deadline); {
+		for _, src := range tt.FindLogSrc() {
+			srcs = append(srcs, src)
+			src.SetOutput(func(e logs.LogEvent) {
+				if e != nil {
+					events <- e
+				}
+			})
+		}
+		select {
+		case e := <-events:
+			_, msg, _ := strings.Cut(e.Message(), " ")
+			msgs = append(msgs, msg)
+		case <-time.After(50 * time.Millisecond):
+		}
+		// the lines written after the backfill started are tailed as usual
+		if !appended && len(msgs) > 0 {
+			appended = true
+			f, err := os.OpenFile(filepath.Join(dir, "app.log"), os.O_APPEND|os.O_WRONLY, 0644)
+			require.NoError(t, err)
+			_, err = f.WriteString(line(5*time.Minute, "appended") + line(0, "next"))
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+		}
+	}
+	assert.ElementsMatch(t, []string{"rotated 2", "rotated 1", "live", "appended"}, msgs)
+	// the rotated files are read oldest first, then the target file
+	joined := strings.Join(msgs, ",")
+	assert.Less(t, strings.Index(joined, "rotated 2"), strings.Index(joined, "rotated 1"))
+	assert.Less(t, strings.Index(joined, "rotated 1"), strings.Index(joined, "live"))
+}
+
+func TestBackfillQueueState(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for i, name := range []string{"app.log.2", "app.log.1", "app.log"} {
+		filename := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(filename, []byte("line\n"), 0644))
+		modTime := now.Add(time.Duration(i-3) * time.Minute)
+		require.NoError(t, os.Chtimes(filename, modTime, modTime))
+	}
+	tt := NewLogFile()
+	tt.Log = TestLogger{t}
+	tt.FileStateFolder = t.TempDir()
+	tt.FileConfig = []FileConfig{{
+		FilePath:      filepath.Join(dir, "app.log*"),
+		BackfillSince: "2h",
+	}}
+	fileconfig := &tt.FileConfig[0]
+	require.NoError(t, fileconfig.init())
+	targetFiles := []string{filepath.Join(dir, "app.log")}
+
+	q := tt.newBackfillQueue(fileconfig, targetFiles, nil, false)
+	assert.Equal(t, []string{filepath.Join(dir, "app.log.2"), filepath.Join(dir, "app.log.1")}, q.files)
+
+	// the pending rotated files are restored even though the target file has a
+	// state file after a restart
+	q.current, q.files = q.files[0], q.files[1:]
+	q.saveState()
+	restored := tt.newBackfillQueue(fileconfig, targetFiles, nil, true)
+	assert.Equal(t, []string{filepath.Join(dir, "app.log.2"), filepath.Join(dir, "app.log.1")}, restored.files)
+
+	q.current = ""
+	q.saveState()
+	restored = tt.newBackfillQueue(fileconfig, targetFiles, nil, true)
+	assert.Equal(t, []string{filepath.Join(dir, "app.log.1")}, restored.files)
+
+	// nothing is backfilled once all rotated files were
+	q.files = nil
+	q.saveState()
+	assert.NoFileExists(t, q.statePath)
+	restored = tt.newBackfillQueue(fileconfig, targetFiles, nil, true)
+	assert.Empty(t, restored.files)
+}
@@ -0,0 +1,208 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/k8sCommon/k8sclient"
+)
+
+const (
+	ContainerLogFormatAuto   = "auto"
+	ContainerLogFormatCRI    = "cri"
+	ContainerLogFormatDocker = "docker"
+
+	defaultContainerLogGroupName  = "/k8s/{namespace}"
+	defaultContainerLogStreamName = "{pod_name}/{container_name}"
+
+	criPartialTag = "P"
+)
+
+var (
+	// containerLogFileName matches the symlinks in /var/log/containers, which are
+	// named <pod>_<namespace>_<container>-<container id>.log.
+	containerLogFileName = regexp.MustCompile(`(?:^|/)([^/_]+)_([^/_]+)_([^/]+)-([0-9a-f]{64})\.log$`)
+	// podLogFileName matches the files in /var/log/pods, which are named
+	// <namespace>_<pod>_<pod uid>/<container>/<restart count>.log.
+	podLogFileName = regexp.MustCompile(`(?:^|/)([^/_]+)_([^/_]+)_([^/_]+)/([^/]+)/\d+\.log$`)
+
+	// getPodMetadata looks up the pod in the pod store of the Kubernetes client.
+	getPodMetadata = func(namespace, name string) (k8sclient.PodMetadata, bool) {
+		client := k8sclient.Get()
+		if client.Pod == nil {
+			return k8sclient.PodMetadata{}, false
+		}
+		return client.Pod.PodMetadata(namespace, name)
+	}
+)
+
+// containerInfo is the Kubernetes metadata of a container log file.
+type containerInfo struct {
+	Namespace     string            `json:"namespace_name"`
+	PodName       string            `json:"pod_name"`
+	PodID         string            `json:"pod_id,omitempty"`
+	ContainerName string            `json:"container_name"`
+	ContainerID   string            `json:"container_id,omitempty"`
+	Labels        map[string]string `json:"labels,omitempty"`
+}
+
+// newContainerInfo returns the metadata of the container from the name of the
+// log file and the labels of its pod, or nil when the file is not the log of a
+// Kubernetes container.
+func newContainerInfo(filename string) *containerInfo {
+	filename = strings.ReplaceAll(filename, "\\", "/")
+	var info *containerInfo
+	if m := containerLogFileName.FindStringSubmatch(filename); m != nil {
+		info = &containerInfo{PodName: m[1], Namespace: m[2], ContainerName: m[3], ContainerID: m[4]}
+	} else if m = podLogFileName.FindStringSubmatch(filename); m != nil {
+		info = &containerInfo{Namespace: m[1], PodName: m[2], PodID: m[3], ContainerName: m[4]}
+	} else {
+		return nil
+	}
+	if pod, ok := getPodMetadata(info.Namespace, info.PodName); ok {
+		info.PodID = pod.UID
+		info.Labels = pod.Labels
+	}
+	return info
+}
+
+// containerLine is a line of a container log without the CRI or docker
+// json-file wrapper.
+type containerLine struct {
+	log    string
+	stream string
+	time   time.Time
+}
+
+// containerLog parses the lines written by the container runtime and joins
+// the lines that were split into partial ones.
+type containerLog struct {
+	format  string
+	maxSize int
+	info    *containerInfo
+	// partial is the beginning of the line being written to each stream.
+	partial map[string]string
+}
+
+func newContainerLog(format, filename string, maxSize int) *containerLog {
+	return &containerLog{
+		format:  format,
+		maxSize: maxSize,
+		info:    newContainerInfo(filename),
+		partial: make(map[string]string),
+	}
+}
+
+// parse returns the log of the line, and false when the line is only the
+// beginning of a log that continues on the next lines of the stream. Lines
+// that are not in the format are returned as is.
+func (c *containerLog) parse(text string) (containerLine, bool) {
+	var line containerLine
+	var partial bool
+	var ok bool
+	format := c.format
+	if format == ContainerLogFormatAuto {
+		format = ContainerLogFormatCRI
+		if strings.HasPrefix(text, "{") {
+			format = ContainerLogFormatDocker
+		}
+	}
+	if format == ContainerLogFormatDocker {
+		line, partial, ok = parseDockerLine(text)
+	} else {
+		line, partial, ok = parseCRILine(text)
+	}
+	if !ok {
+		return containerLine{log: text}, true
+	}
+	if prefix, found := c.partial[line.stream]; found {
+		line.log = prefix + line.log
+		delete(c.partial, line.stream)
+	}
+	if partial && len(line.log) < c.maxSize {
+		c.partial[line.stream] = line.log
+		return line, false
+	}
+	return line, true
+}
+
+// parseCRILine parses the "<time> <stream> <tag> <log>" lines of the CRI
+// logging format, where the P tag marks a partial line.
+func parseCRILine(text string) (containerLine, bool, bool) {
+	fields := strings.SplitN(text, " ", 4)
+	if len(fields) < 3 {
+		return containerLine{}, false, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, fields[0])
+	if err != nil {
+		return containerLine{}, false, false
+	}
+	line := containerLine{stream: fields[1], time: t}
+	if len(fields) == 4 {
+		line.log = fields[3]
+	}
+	tag, _, _ := strings.Cut(fields[2], ":")
+	return line, tag == criPartialTag, true
+}
+
+// parseDockerLine parses the lines of the docker json-file logging driver,
+// where a log that does not end with a newline is a partial line.
+func parseDockerLine(text string) (containerLine, bool, bool) {
+	var record struct {
+		Log    string    `json:"log"`
+		Stream string    `json:"stream"`
+		Time   time.Time `json:"time"`
+	}
+	if err := json.Unmarshal([]byte(text), &record); err != nil {
+		return containerLine{}, false, false
+	}
+	line := containerLine{
+		log:    strings.TrimSuffix(strings.TrimSuffix(record.Log, "\n"), "\r"),
+		stream: record.Stream,
+		time:   record.Time,
+	}
+	return line, !strings.HasSuffix(record.Log, "\n"), true
+}
+
+// wrap returns the JSON envelope with the log, its stream and the Kubernetes
+// metadata of the container.
+func (c *containerLog) wrap(msg string, line containerLine) string {
+	envelope := struct {
+		Log        string         `json:"log"`
+		Stream     string         `json:"stream,omitempty"`
+		Kubernetes *containerInfo `json:"kubernetes,omitempty"`
+	}{
+		Log:        msg,
+		Stream:     line.stream,
+		Kubernetes: c.info,
+	}
+	out, err := json.Marshal(envelope)
+	if err != nil {
+		return msg
+	}
+	return string(out)
+}
+
+// value returns the value of the Kubernetes placeholder in the log group and
+// log stream names, and false when the placeholder is not one.
+func (info *containerInfo) value(key, arg string) (string, bool) {
+	if info == nil {
+		info = &containerInfo{}
+	}
+	switch {
+	case key == "namespace" && arg == "":
+		return info.Namespace, true
+	case key == "pod_name" && arg == "":
+		return info.PodName, true
+	case key == "container_name" && arg == "":
+		return info.ContainerName, true
+	case key == "label" && arg != "":
+		return info.Labels[arg], true
+	}
+	return "", false
+}
@@ -0,0 +1,235 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/k8sCommon/k8sclient"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+const testContainerID = "5d2b4e1f0c3a9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e"
+
+func stubPodMetadata(t *testing.T) {
+	orig := getPodMetadata
+	t.Cleanup(func() { getPodMetadata = orig })
+	getPodMetadata = func(namespace, name string) (k8sclient.PodMetadata, bool) {
+		if namespace != "shop" || name != "checkout-7d9f" {
+			return k8sclient.PodMetadata{}, false
+		}
+		return k8sclient.PodMetadata{
+			Namespace: namespace,
+			Name:      name,
+			UID:       "0b7c1d3e",
+			Labels:    map[string]string{"app": "checkout"},
+		}, true
+	}
+}
+
+func TestNewContainerInfo(t *testing.T) {
+	stubPodMetadata(t)
+
+	testCases := map[string]*containerInfo{
+		"/var/log/containers/checkout-7d9f_shop_server-" + testContainerID + ".log": {
+			Namespace:     "shop",
+			PodName:       "checkout-7d9f",
+			PodID:         "0b7c1d3e",
+			ContainerName: "server",
+			ContainerID:   testContainerID,
+			Labels:        map[string]string{"app": "checkout"},
+		},
+		"/var/log/pods/kube-system_coredns-5d78_a1b2c3/coredns/0.log": {
+			Namespace:     "kube-system",
+			PodName:       "coredns-5d78",
+			PodID:         "a1b2c3",
+			ContainerName: "coredns",
+		},
+		"/var/log/messages": nil,
+	}
+	for filename, want := range testCases {
+		assert.Equal(t, want, newContainerInfo(filename), filename)
+	}
+}
+
+func TestContainerLogParse(t *testing.T) {
+	testCases := map[string]struct {
+		format string
+		lines  []string
+		want   []containerLine
+	}{
+		"cri": {
+			format: ContainerLogFormatCRI,
+			lines: []string{
+				"2024-03-10T12:00:00.123456789Z stdout F hello world",
+				"2024-03-10T12:00:01Z stderr P first ",
+				"2024-03-10T12:00:01Z stdout F other",
+				"2024-03-10T12:00:02Z stderr F second",
+				"2024-03-10T12:00:03Z stdout F",
+			},
+			want: []containerLine{
+				{log: "hello world", stream: "stdout", time: time.Date(2024, time.March, 10, 12, 0, 0, 123456789, time.UTC)},
+				{log: "other", stream: "stdout", time: time.Date(2024, time.March, 10, 12, 0, 1, 0, time.UTC)},
+				{log: "first second", stream: "stderr", time: time.Date(2024, time.March, 10, 12, 0, 2, 0, time.UTC)},
+				{log: "", stream: "stdout", time: time.Date(2024, time.March, 10, 12, 0, 3, 0, time.UTC)},
+			},
+		},
+		"docker": {
+			format: ContainerLogFormatDocker,
+			lines: []string{
+				`{"log":"hello world\n","stream":"stdout","time":"2024-03-10T12:00:00Z"}`,
+				`{"log":"first ","stream":"stderr","time":"2024-03-10T12:00:01Z"}`,
+				`{"log":"second\n","stream":"stderr","time":"2024-03-10T12:00:02Z"}`,
+			},
+			want: []containerLine{
+				{log: "hello world", stream: "stdout", time: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)},
+				{log: "first second", stream: "stderr", time: time.Date(2024, time.March, 10, 12, 0, 2, 0, time.UTC)},
+			},
+		},
+		"auto": {
+			format: ContainerLogFormatAuto,
+			lines: []string{
+				`{"log":"from docker\n","stream":"stdout","time":"2024-03-10T12:00:00Z"}`,
+				"2024-03-10T12:00:01Z stdout F from cri",
+				"not a container log",
+			},
+			want: []containerLine{
+				{log: "from docker", stream: "stdout", time: time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)},
+				{log: "from cri", stream: "stdout", time: time.Date(2024, time.March, 10, 12, 0, 1, 0, time.UTC)},
+				{log: "not a container log"},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			c := newContainerLog(testCase.format, "/var/log/app.log", defaultMaxEventSize)
+			var got []containerLine
+			for _, text := range testCase.lines {
+				if line, complete := c.parse(text); complete {
+					got = append(got, line)
+				}
+			}
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestContainerLogPartialMaxSize(t *testing.T) {
+	c := newContainerLog(ContainerLogFormatCRI, "/var/log/app.log", 10)
+	_, complete := c.parse("2024-03-10T12:00:00Z stdout P 12345")
+	assert.False(t, complete)
+	line, complete := c.parse("2024-03-10T12:00:00Z stdout P 67890")
+	assert.True(t, complete)
+	assert.Equal(t, "1234567890", line.log)
+	line, complete = c.parse("2024-03-10T12:00:00Z stdout F end")
+	assert.True(t, complete)
+	assert.Equal(t, "end", line.log)
+}
+
+func TestContainerLogWrap(t *testing.T) {
+	stubPodMetadata(t)
+
+	c := newContainerLog(ContainerLogFormatCRI, "/var/log/containers/checkout-7d9f_shop_server-"+testContainerID+".log", defaultMaxEventSize)
+	assert.JSONEq(t, `{
+		"log": "hello",
+		"stream": "stdout",
+		"kubernetes": {
+			"namespace_name": "shop",
+			"pod_name": "checkout-7d9f",
+			"pod_id": "0b7c1d3e",
+			"container_name": "server",
+			"container_id": "`+testContainerID+`",
+			"labels": {"app": "checkout"}
+		}
+	}`, c.wrap("hello", containerLine{log: "hello", stream: "stdout"}))
+
+	c = newContainerLog(ContainerLogFormatCRI, "/var/log/app.log", defaultMaxEventSize)
+	assert.JSONEq(t, `{"log": "hello"}`, c.wrap("hello", containerLine{log: "hello"}))
+}
+
+func TestContainerLogNames(t *testing.T) {
+	stubPodMetadata(t)
+
+	config := &FileConfig{FilePath: "/var/log/containers/*.log", ContainerLogFormat: ContainerLogFormatAuto}
+	require.NoError(t, config.init())
+	assert.True(t, config.PublishMultiLogs)
+	assert.Equal(t, defaultContainerLogGroupName, config.LogGroupName)
+	assert.Equal(t, defaultContainerLogStreamName, config.LogStreamName)
+	assert.True(t, config.hasFilePlaceholder(config.LogStreamName))
+
+	info := newContainerInfo("/var/log/containers/checkout-7d9f_shop_server-" + testContainerID + ".log")
+	now := time.Now()
+	assert.Equal(t, "/k8s/shop", config.resolveName(config.LogGroupName, "", now, info))
+	assert.Equal(t, "checkout-7d9f/server", config.resolveName(config.LogStreamName, "", now, info))
+	assert.Equal(t, "checkout/unknown", config.resolveName("{label:app}/{label:tier}", "", now, info))
+	assert.Equal(t, "/k8s/unknown", config.resolveName(config.LogGroupName, "", now, nil))
+
+	config = &FileConfig{FilePath: "/var/log/containers/*.log", ContainerLogFormat: "podman"}
+	assert.EqualError(t, config.init(), "container_log_format podman is not supported")
+}
+
+func TestContainerLogs(t *testing.T) {
+	stubPodMetadata(t)
+	multilineWaitPeriod = 10 * time.Millisecond
+
+	dir := filepath.Join(t.TempDir(), "containers")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	filename := filepath.Join(dir, "checkout-7d9f_shop_server-"+testContainerID+".log")
+	content := []string{
+		"2024-03-10T12:00:00Z stdout F started",
+		"2024-03-10T12:00:01Z stderr P part one,",
+		"2024-03-10T12:00:02Z stderr F  part two",
+	}
+	require.NoError(t, os.WriteFile(filename, []byte(strings.Join(content, "\n")+"\n"), 0644))
+
+	tt := NewLogFile()
+	tt.Log = TestLogger{t}
+	tt.FileConfig = []FileConfig{{
+		FilePath:           filepath.Join(dir, "*.log"),
+		FromBeginning:      true,
+		ContainerLogFormat: ContainerLogFormatCRI,
+	}}
+	require.NoError(t, tt.FileConfig[0].init())
+	tt.started = true
+	defer tt.Stop()
+
+	lsrcs := tt.FindLogSrc()
+	require.Len(t, lsrcs, 1)
+	lsrc := lsrcs[0]
+	assert.Equal(t, "/k8s/shop", lsrc.Group())
+	assert.Equal(t, "checkout-7d9f/server", lsrc.Stream())
+
+	events := make(chan logs.LogEvent, 2)
+	lsrc.SetOutput(func(e logs.LogEvent) {
+		if e != nil {
+			events <- e
+		}
+	})
+	defer lsrc.Stop()
+
+	want := []struct {
+		msg string
+		t   time.Time
+	}{
+		{`{"log":"started","stream":"stdout"`, time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)},
+		{`{"log":"part one, part two","stream":"stderr"`, time.Date(2024, time.March, 10, 12, 0, 2, 0, time.UTC)},
+	}
+	for _, w := range want {
+		select {
+		case e := <-events:
+			assert.True(t, strings.HasPrefix(e.Message(), w.msg), e.Message())
+			assert.Contains(t, e.Message(), `"labels":{"app":"checkout"}`)
+			assert.True(t, w.t.Equal(e.Time()), e.Time())
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for %s", w.msg)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/fsnotify.v1"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/globpath"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/tail/watch"
+)
+
+const (
+	WatchModePoll    = "poll"
+	WatchModeInotify = "inotify"
+
+	defaultMaxWatches        = 4096
+	discoveryEventBufferSize = 4096
+)
+
+// rescanInterval is how often the file_path globs are evaluated and the
+// watched directories are synced even without a change being notified, in
+// case an event was dropped.
+var rescanInterval = time.Minute
+
+// fileDiscovery watches the directories that the file_path globs can match
+// files in, so that the globs are only evaluated again after a change in one
+// of them instead of every time FindLogSrc is called. Only files being added,
+// removed or renamed are notified, so a file that becomes the most recently
+// modified match is picked up by the periodic rescan. A file config falls
+// back to polling when its directories cannot be watched.
+type fileDiscovery struct {
+	mu         sync.Mutex
+	events     chan fsnotify.Event
+	configs    map[*FileConfig]*watchedConfig
+	maxWatches int
+}
+
+type watchedConfig struct {
+	filePath string
+	root     string
+	depth    int
+	dirs     map[string]struct{}
+	polling  bool
+	dirty    bool
+	resync   bool
+	lastSync time.Time
+}
+
+func newFileDiscovery(maxWatches int) *fileDiscovery {
+	return &fileDiscovery{
+		events:     make(chan fsnotify.Event, discoveryEventBufferSize),
+		configs:    make(map[*FileConfig]*watchedConfig),
+		maxWatches: maxWatches,
+	}
+}
+
+// add starts watching the directories of the file config.
+func (d *fileDiscovery) add(config *FileConfig) error {
+	g, err := globpath.Compile(config.FilePath)
+	if err != nil {
+		return err
+	}
+	root, depth := g.WatchRoot()
+	c := &watchedConfig{
+		filePath: config.FilePath,
+		root:     root,
+		depth:    depth,
+		dirs:     make(map[string]struct{}),
+		dirty:    true,
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.configs[config] = c
+	d.sync(c)
+	return nil
+}
+
+// needsScan reports whether the files of the file config may have changed
+// since the last call.
+func (d *fileDiscovery) needsScan(config *FileConfig) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	c, ok := d.configs[config]
+	if !ok || c.polling {
+		return true
+	}
+	dirty := c.dirty
+	c.dirty = false
+	return dirty
+}
+
+// markDirty makes the file config to be scanned on the next call to
+// needsScan, e.g. after one of its tailers stopped.
+func (d *fileDiscovery) markDirty(config *FileConfig) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if c, ok := d.configs[config]; ok {
+		c.dirty = true
+	}
+}
+
+// update applies the events received since the last call.
+func (d *fileDiscovery) update() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// events were dropped when the buffer is full
+	overflow := len(d.events) == cap(d.events)
+	for done := false; !done; {
+		select {
+		case e := <-d.events:
+			d.handle(e)
+		default:
+			done = true
+		}
+	}
+	for _, c := range d.configs {
+		if overflow || time.Since(c.lastSync) >= rescanInterval {
+			c.dirty = true
+			c.resync = true
+		}
+		if c.resync {
+			d.sync(c)
+		}
+	}
+}
+
+func (d *fileDiscovery) handle(e fsnotify.Event) {
+	name := filepath.Clean(e.Name)
+	dir := filepath.Dir(name)
+	for _, c := range d.configs {
+		if _, ok := c.dirs[dir]; !ok {
+			continue
+		}
+		c.dirty = true
+		// a directory that is created or removed changes the watched directories
+		if e.Op&fsnotify.Create != 0 && isDir(name) {
+			c.resync = true
+		} else if _, ok := c.dirs[name]; ok && e.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+			c.resync = true
+		}
+	}
+}
+
+// sync watches the directories that currently exist for the file config and
+// stops watching the ones that were removed.
+func (d *fileDiscovery) sync(c *watchedConfig) {
+	c.resync = false
+	c.lastSync = time.Now()
+	want, err := c.watchDirs(d.maxWatches)
+	if err == nil {
+		for dir := range want {
+			if _, ok := c.dirs[dir]; ok {
+				continue
+			}
+			if err = watch.WatchDir(dir, d.events); err != nil {
+				break
+			}
+			c.dirs[dir] = struct{}{}
+		}
+	}
+	if err != nil {
+		if !c.polling {
+			log.Printf("W! [logfile] Failed to watch the directories of %s, polling for new files instead: %v", c.filePath, err)
+		}
+		c.polling = true
+		want = nil
+	} else if c.polling {
+		log.Printf("I! [logfile] Watching the directories of %s for new files", c.filePath)
+		c.polling = false
+		c.dirty = true
+	}
+	for dir := range c.dirs {
+		if _, ok := want[dir]; ok {
+			continue
+		}
+		if err := watch.RemoveWatchDir(dir, d.events); err != nil {
+			log.Printf("D! [logfile] Failed to remove watch of %s: %v", dir, err)
+		}
+		delete(c.dirs, dir)
+	}
+}
+
+// close stops watching the directories of all file configs.
+func (d *fileDiscovery) close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, c := range d.configs {
+		for dir := range c.dirs {
+			watch.RemoveWatchDir(dir, d.events)
+			delete(c.dirs, dir)
+		}
+	}
+	d.configs = make(map[*FileConfig]*watchedConfig)
+}
+
+// watchDirs returns the root and its subdirectories up to the depth that the
+// file path can match files in. When the root does not exist yet, its closest
+// existing parent is watched for it to be created.
+func (c *watchedConfig) watchDirs(maxWatches int) (map[string]struct{}, error) {
+	dirs := make(map[string]struct{})
+	root, depth := c.root, c.depth
+	for !isDir(root) {
+		parent := filepath.Dir(root)
+		if parent == root {
+			return dirs, nil
+		}
+		root, depth = parent, 0
+	}
+	dirs[root] = struct{}{}
+	if depth == 0 {
+		return dirs, nil
+	}
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() || path == root {
+			return nil
+		}
+		if depth > 0 {
+			rel, err := filepath.Rel(root, path)
+			if err != nil || strings.Count(rel, string(os.PathSeparator)) >= depth {
+				return filepath.SkipDir
+			}
+		}
+		if maxWatches > 0 && len(dirs) >= maxWatches {
+			return watch.ErrTooManyWatches
+		}
+		dirs[path] = struct{}{}
+		return nil
+	})
+	if err != nil && !errors.Is(err, filepath.SkipDir) {
+		return nil, err
+	}
+	return dirs, nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
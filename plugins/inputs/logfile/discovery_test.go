@@ -0,0 +1,185 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+// +build linux
+
+package logfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/tail/watch"
+)
+
+func newInotifyLogFile(t testing.TB, filePath string, maxWatches int) *LogFile {
+	tt := NewLogFile()
+	tt.Log = TestLogger{}
+	tt.FileStateFolder = filepath.Join(t.TempDir(), "state")
+	tt.WatchMode = WatchModeInotify
+	tt.MaxWatches = maxWatches
+	tt.FileConfig = []FileConfig{{FilePath: filePath, FromBeginning: true, PublishMultiLogs: true}}
+	require.NoError(t, tt.Start(nil))
+	t.Cleanup(func() {
+		tt.Stop()
+		for _, dsts := range tt.configs {
+			for _, src := range dsts {
+				src.Stop()
+			}
+		}
+		watch.SetMaxWatches(0)
+	})
+	return tt
+}
+
+func writeFile(t testing.TB, path string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte("line\n"), 0644))
+}
+
+func srcFiles(srcs []logs.LogSrc) []string {
+	var streams []string
+	for _, src := range srcs {
+		streams = append(streams, src.Description())
+	}
+	return streams
+}
+
+func TestInotifyDiscovery(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a", "app.log"))
+	tt := newInotifyLogFile(t, filepath.Join(dir, "**.log"), 0)
+
+	c := tt.discovery.configs[&tt.FileConfig[0]]
+	require.NotNil(t, c)
+	assert.False(t, c.polling)
+	assert.Len(t, c.dirs, 2)
+
+	srcs := tt.FindLogSrc()
+	assert.Len(t, srcs, 1)
+	// nothing changed, so the glob is not evaluated again
+	assert.False(t, tt.discovery.needsScan(&tt.FileConfig[0]))
+	assert.Empty(t, tt.FindLogSrc())
+
+	// a file in a new directory
+	writeFile(t, filepath.Join(dir, "b", "c", "web.log"))
+	var found []logs.LogSrc
+	require.Eventually(t, func() bool {
+		found = append(found, tt.FindLogSrc()...)
+		return len(found) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{filepath.Join(dir, "b", "c", "web.log")}, srcFiles(found))
+	assert.Len(t, c.dirs, 4)
+
+	// the removed directories are no longer watched
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "b")))
+	require.Eventually(t, func() bool {
+		tt.FindLogSrc()
+		return len(c.dirs) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestInotifyDiscoveryMissingRoot(t *testing.T) {
+	dir := t.TempDir()
+	tt := newInotifyLogFile(t, filepath.Join(dir, "app", "*.log"), 0)
+	assert.Empty(t, tt.FindLogSrc())
+
+	writeFile(t, filepath.Join(dir, "app", "app.log"))
+	var found []logs.LogSrc
+	require.Eventually(t, func() bool {
+		found = append(found, tt.FindLogSrc()...)
+		return len(found) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{filepath.Join(dir, "app", "app.log")}, srcFiles(found))
+}
+
+func TestInotifyDiscoveryFallsBackToPolling(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 3; i++ {
+		writeFile(t, filepath.Join(dir, fmt.Sprint(i), "app.log"))
+	}
+	tt := newInotifyLogFile(t, filepath.Join(dir, "**.log"), 2)
+
+	c := tt.discovery.configs[&tt.FileConfig[0]]
+	require.NotNil(t, c)
+	assert.True(t, c.polling)
+	assert.Empty(t, c.dirs)
+	assert.Len(t, tt.FindLogSrc(), 3)
+	assert.True(t, tt.discovery.needsScan(&tt.FileConfig[0]))
+
+	writeFile(t, filepath.Join(dir, "0", "web.log"))
+	assert.Equal(t, []string{filepath.Join(dir, "0", "web.log")}, srcFiles(tt.FindLogSrc()))
+}
+
+func TestInotifyTailing(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	writeFile(t, path)
+	tt := newInotifyLogFile(t, path, 0)
+
+	srcs := tt.FindLogSrc()
+	require.Len(t, srcs, 1)
+	evts := make(chan logs.LogEvent, 10)
+	srcs[0].SetOutput(func(e logs.LogEvent) {
+		if e != nil {
+			evts <- e
+		}
+	})
+	// wait for the tailer to reach the end of the file
+	ts := srcs[0].(*tailerSrc)
+	require.Eventually(t, func() bool {
+		return ts.readOffset.Load() == int64(len("line\n"))
+	}, 5*time.Second, 10*time.Millisecond)
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	defer f.Close()
+	// the last line is only published after a wait period, so append another
+	_, err = f.WriteString("appended\nnext\n")
+	require.NoError(t, err)
+	for _, want := range []string{"line", "appended"} {
+		select {
+		case e := <-evts:
+			assert.Equal(t, want, e.Message())
+		case <-time.After(3 * time.Second):
+			t.Fatalf("%s was not received", want)
+		}
+	}
+}
+
+// BenchmarkFindLogSrc compares evaluating the globs every time with only
+// evaluating them after a change, on a tree of 100 directories with 100
+// files each where nothing changes between the calls.
+func BenchmarkFindLogSrc(b *testing.B) {
+	dir := b.TempDir()
+	for i := 0; i < 100; i++ {
+		for j := 0; j < 100; j++ {
+			writeFile(b, filepath.Join(dir, fmt.Sprint(i), fmt.Sprintf("%d.txt", j)))
+		}
+	}
+	writeFile(b, filepath.Join(dir, "0", "app.log"))
+	filePath := filepath.Join(dir, "**", "app.log")
+
+	for _, mode := range []string{WatchModePoll, WatchModeInotify} {
+		b.Run(mode, func(b *testing.B) {
+			tt := newInotifyLogFile(b, filePath, 0)
+			if mode == WatchModePoll {
+				tt.discovery.close()
+				tt.discovery = nil
+			}
+			require.Len(b, tt.FindLogSrc(), 1)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				tt.FindLogSrc()
+			}
+		})
+	}
+}
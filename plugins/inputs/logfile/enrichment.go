@@ -0,0 +1,219 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/internal/retryer"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/ec2tagger"
+)
+
+const (
+	EnrichmentFormatJSON     = "json"
+	EnrichmentFormatKeyValue = "key_value"
+
+	enrichmentMessageKey = "message"
+	enrichmentEC2TagsKey = "ec2_tags"
+
+	enrichmentInstanceID       = "instance_id"
+	enrichmentHostname         = "hostname"
+	enrichmentAvailabilityZone = "availability_zone"
+	enrichmentRegion           = "region"
+	enrichmentAccountID        = "account_id"
+	enrichmentInstanceType     = "instance_type"
+	enrichmentImageID          = "image_id"
+	enrichmentFilePath         = "file_path"
+	enrichmentOffset           = "offset"
+
+	hostMetadataTimeout = 10 * time.Second
+)
+
+var (
+	// enrichmentFields are the supported enrichment fields.
+	enrichmentFields = map[string]bool{
+		enrichmentInstanceID:       true,
+		enrichmentHostname:         true,
+		enrichmentAvailabilityZone: true,
+		enrichmentRegion:           true,
+		enrichmentAccountID:        true,
+		enrichmentInstanceType:     true,
+		enrichmentImageID:          true,
+		enrichmentFilePath:         true,
+		enrichmentOffset:           true,
+	}
+	defaultEnrichmentFields = []string{enrichmentInstanceID, enrichmentHostname, enrichmentAvailabilityZone, enrichmentFilePath, enrichmentOffset}
+
+	// getHostMetadata looks up the metadata of the host, the same instance
+	// metadata as the ec2tagger processor.
+	getHostMetadata = imdsHostMetadata
+
+	hostMetadataOnce sync.Once
+	hostMetadataMap  map[string]string
+)
+
+// enrichment adds the metadata of the host and the file to the log events of a
+// file, so that they can be queried without parsing the log stream names.
+type enrichment struct {
+	format string
+	// fields are the fields other than the offset in order, with their values.
+	fields  []enrichmentField
+	ec2Tags []enrichmentField
+	offset  bool
+}
+
+type enrichmentField struct {
+	key, value string
+}
+
+func (config *FileConfig) initEnrichment() error {
+	if config.EnrichmentFormat == "" {
+		if len(config.EnrichmentFields) > 0 || len(config.EnrichmentEC2Tags) > 0 {
+			return errors.New("enrichment_format is required with enrichment_fields and enrichment_ec2_tags")
+		}
+		return nil
+	}
+	if config.EnrichmentFormat != EnrichmentFormatJSON && config.EnrichmentFormat != EnrichmentFormatKeyValue {
+		return fmt.Errorf("enrichment_format %s is not supported", config.EnrichmentFormat)
+	}
+	if len(config.EnrichmentFields) == 0 {
+		config.EnrichmentFields = defaultEnrichmentFields
+	}
+	for _, field := range config.EnrichmentFields {
+		if !enrichmentFields[field] {
+			return fmt.Errorf("enrichment_fields %s is not supported", field)
+		}
+	}
+	return nil
+}
+
+// newEnrichment returns the enrichment of the log events of the file, nil when
+// the file config has none.
+func newEnrichment(config *FileConfig, filename string) *enrichment {
+	if config.EnrichmentFormat == "" {
+		return nil
+	}
+	e := &enrichment{format: config.EnrichmentFormat}
+	host := hostMetadata()
+	for _, key := range config.EnrichmentFields {
+		switch key {
+		case enrichmentOffset:
+			e.offset = true
+		case enrichmentFilePath:
+			e.fields = append(e.fields, enrichmentField{key, filename})
+		default:
+			if value := host[key]; value != "" {
+				e.fields = append(e.fields, enrichmentField{key, value})
+			}
+		}
+	}
+	for _, key := range config.EnrichmentEC2Tags {
+		if value := ec2Tag(key); value != "" {
+			e.ec2Tags = append(e.ec2Tags, enrichmentField{key, value})
+		}
+	}
+	return e
+}
+
+// wrap returns the message enriched with the fields, in a JSON envelope or with
+// key=value pairs appended to it.
+func (e *enrichment) wrap(msg string, offset int64) string {
+	if e.format == EnrichmentFormatKeyValue {
+		var sb strings.Builder
+		sb.WriteString(msg)
+		for _, f := range e.fields {
+			writeKeyValue(&sb, f.key, f.value)
+		}
+		if e.offset {
+			writeKeyValue(&sb, enrichmentOffset, strconv.FormatInt(offset, 10))
+		}
+		for _, f := range e.ec2Tags {
+			writeKeyValue(&sb, "ec2_tag."+f.key, f.value)
+		}
+		return sb.String()
+	}
+
+	envelope := make(map[string]interface{}, len(e.fields)+3)
+	// the JSON objects, like the container logs, are nested as is
+	if trimmed := strings.TrimSpace(msg); strings.HasPrefix(trimmed, "{") && json.Valid([]byte(trimmed)) {
+		envelope[enrichmentMessageKey] = json.RawMessage(trimmed)
+	} else {
+		envelope[enrichmentMessageKey] = msg
+	}
+	for _, f := range e.fields {
+		envelope[f.key] = f.value
+	}
+	if e.offset {
+		envelope[enrichmentOffset] = offset
+	}
+	if len(e.ec2Tags) > 0 {
+		tags := make(map[string]string, len(e.ec2Tags))
+		for _, f := range e.ec2Tags {
+			tags[f.key] = f.value
+		}
+		envelope[enrichmentEC2TagsKey] = tags
+	}
+	out, err := json.Marshal(envelope)
+	if err != nil {
+		return msg
+	}
+	return string(out)
+}
+
+// writeKeyValue appends the key=value pair, with the value quoted when it has
+// spaces, quotes or equal signs.
+func writeKeyValue(sb *strings.Builder, key, value string) {
+	sb.WriteString(" ")
+	sb.WriteString(key)
+	sb.WriteString("=")
+	if value == "" || strings.ContainsAny(value, " \t\"=") {
+		value = strconv.Quote(value)
+	}
+	sb.WriteString(value)
+}
+
+// hostMetadata returns the metadata of the host, which is only looked up once.
+func hostMetadata() map[string]string {
+	hostMetadataOnce.Do(func() {
+		hostMetadataMap = getHostMetadata()
+	})
+	return hostMetadataMap
+}
+
+// imdsHostMetadata returns the instance metadata, and only the hostname of the
+// host when it is not an EC2 instance.
+func imdsHostMetadata() map[string]string {
+	metadata := make(map[string]string)
+	if hostname, err := os.Hostname(); err == nil {
+		metadata[enrichmentHostname] = hostname
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), hostMetadataTimeout)
+	defer cancel()
+	provider := ec2tagger.NewMetadataProvider((&configaws.CredentialConfig{}).Credentials(), retryer.GetDefaultRetryNumber())
+	doc, err := provider.Get(ctx)
+	if err != nil {
+		log.Printf("W! [logfile] Failed to get the instance metadata for the enrichment of the log events: %v", err)
+		return metadata
+	}
+	metadata[enrichmentInstanceID] = doc.InstanceID
+	metadata[enrichmentAvailabilityZone] = doc.AvailabilityZone
+	metadata[enrichmentRegion] = doc.Region
+	metadata[enrichmentAccountID] = doc.AccountID
+	metadata[enrichmentInstanceType] = doc.InstanceType
+	metadata[enrichmentImageID] = doc.ImageID
+	if hostname, err := provider.Hostname(ctx); err == nil && hostname != "" {
+		metadata[enrichmentHostname] = hostname
+	}
+	return metadata
+}
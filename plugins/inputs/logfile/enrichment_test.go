@@ -0,0 +1,151 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+func stubHostMetadata(t *testing.T) {
+	hostMetadataOnce = sync.Once{}
+	getHostMetadata = func() map[string]string {
+		return map[string]string{
+			enrichmentInstanceID:       "i-0123456789abcdef0",
+			enrichmentHostname:         "ip-10-0-0-1.ec2.internal",
+			enrichmentAvailabilityZone: "us-east-1a",
+			enrichmentRegion:           "us-east-1",
+			enrichmentInstanceType:     "m5.large",
+		}
+	}
+	getEC2Tag = func(key string) (string, error) {
+		if key == "Team" {
+			return "payments team", nil
+		}
+		return "", errors.New("not found")
+	}
+	t.Cleanup(func() {
+		hostMetadataOnce = sync.Once{}
+		getHostMetadata = imdsHostMetadata
+		getEC2Tag = imdsTag
+		ec2Tags = make(map[string]ec2TagLookup)
+	})
+}
+
+func TestEnrichmentInit(t *testing.T) {
+	config := &FileConfig{FilePath: "/var/log/app.log", EnrichmentFormat: EnrichmentFormatJSON}
+	require.NoError(t, config.init())
+	assert.Equal(t, defaultEnrichmentFields, config.EnrichmentFields)
+
+	config = &FileConfig{FilePath: "/var/log/app.log", EnrichmentFormat: "xml"}
+	assert.EqualError(t, config.init(), "enrichment_format xml is not supported")
+	config = &FileConfig{FilePath: "/var/log/app.log", EnrichmentFormat: EnrichmentFormatKeyValue, EnrichmentFields: []string{"kernel"}}
+	assert.EqualError(t, config.init(), "enrichment_fields kernel is not supported")
+	config = &FileConfig{FilePath: "/var/log/app.log", EnrichmentEC2Tags: []string{"Team"}}
+	assert.EqualError(t, config.init(), "enrichment_format is required with enrichment_fields and enrichment_ec2_tags")
+}
+
+func TestEnrichmentWrap(t *testing.T) {
+	stubHostMetadata(t)
+
+	config := &FileConfig{
+		FilePath:          "/var/log/app.log",
+		EnrichmentFormat:  EnrichmentFormatJSON,
+		EnrichmentEC2Tags: []string{"Team", "Missing"},
+	}
+	require.NoError(t, config.init())
+	e := newEnrichment(config, "/var/log/app.log")
+	assert.JSONEq(t, `{
+		"message": "GET /health 200",
+		"instance_id": "i-0123456789abcdef0",
+		"hostname": "ip-10-0-0-1.ec2.internal",
+		"availability_zone": "us-east-1a",
+		"file_path": "/var/log/app.log",
+		"offset": 1024,
+		"ec2_tags": {"Team": "payments team"}
+	}`, e.wrap("GET /health 200", 1024))
+	// the JSON messages are nested as objects
+	assert.JSONEq(t, `{
+		"message": {"level": "info", "msg": "started"},
+		"instance_id": "i-0123456789abcdef0",
+		"hostname": "ip-10-0-0-1.ec2.internal",
+		"availability_zone": "us-east-1a",
+		"file_path": "/var/log/app.log",
+		"offset": 10,
+		"ec2_tags": {"Team": "payments team"}
+	}`, e.wrap(`{"level": "info", "msg": "started"}`, 10))
+	assert.JSONEq(t, `{
+		"message": "{not json",
+		"instance_id": "i-0123456789abcdef0",
+		"hostname": "ip-10-0-0-1.ec2.internal",
+		"availability_zone": "us-east-1a",
+		"file_path": "/var/log/app.log",
+		"offset": 10,
+		"ec2_tags": {"Team": "payments team"}
+	}`, e.wrap(`{not json`, 10))
+
+	config = &FileConfig{
+		FilePath:          "/var/log/app.log",
+		EnrichmentFormat:  EnrichmentFormatKeyValue,
+		EnrichmentFields:  []string{enrichmentInstanceID, enrichmentRegion, enrichmentAccountID, enrichmentOffset},
+		EnrichmentEC2Tags: []string{"Team"},
+	}
+	require.NoError(t, config.init())
+	e = newEnrichment(config, "/var/log/app.log")
+	assert.Equal(t, `GET /health 200 instance_id=i-0123456789abcdef0 region=us-east-1 offset=1024 ec2_tag.Team="payments team"`, e.wrap("GET /health 200", 1024))
+
+	config = &FileConfig{FilePath: "/var/log/app.log"}
+	require.NoError(t, config.init())
+	assert.Nil(t, newEnrichment(config, "/var/log/app.log"))
+}
+
+func TestEnrichedLogs(t *testing.T) {
+	stubHostMetadata(t)
+	multilineWaitPeriod = 10 * time.Millisecond
+
+	filename := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(filename, []byte("first\nsecond\n"), 0644))
+
+	tt := NewLogFile()
+	tt.Log = TestLogger{t}
+	tt.FileConfig = []FileConfig{{
+		FilePath:         filename,
+		FromBeginning:    true,
+		EnrichmentFormat: EnrichmentFormatKeyValue,
+	}}
+	require.NoError(t, tt.FileConfig[0].init())
+	tt.started = true
+	defer tt.Stop()
+
+	lsrcs := tt.FindLogSrc()
+	require.Len(t, lsrcs, 1)
+	events := make(chan logs.LogEvent, 2)
+	lsrcs[0].SetOutput(func(e logs.LogEvent) {
+		if e != nil {
+			events <- e
+		}
+	})
+	defer lsrcs[0].Stop()
+
+	for _, want := range []string{
+		"first instance_id=i-0123456789abcdef0 hostname=ip-10-0-0-1.ec2.internal availability_zone=us-east-1a file_path=" + filename + " offset=6",
+		"second instance_id=i-0123456789abcdef0 hostname=ip-10-0-0-1.ec2.internal availability_zone=us-east-1a file_path=" + filename + " offset=13",
+	} {
+		select {
+		case e := <-events:
+			assert.Equal(t, want, e.Message())
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+}
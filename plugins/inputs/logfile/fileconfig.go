@@ -16,6 +16,7 @@ import (
 	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/ianaindex"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/profiler"
 )
@@ -31,9 +32,20 @@ type FileConfig struct {
 	FilePath string `toml:"file_path"`
 	//The blacklist used to filter out some files
 	Blacklist string `toml:"blacklist"`
+	//The regex matched against the path of each file, its named groups can be
+	//used as placeholders in the log group and log stream names.
+	FilePathRegex string `toml:"file_path_regex"`
 
 	PublishMultiLogs bool `toml:"publish_multi_logs"`
 
+	//Tail all the files that match the file path into the same log stream, instead
+	//of only the most recently modified one.
+	TailAllFiles bool `toml:"tail_all_files"`
+
+	//The format of the container logs, "cri", "docker" or "auto". The logs are
+	//parsed and enriched with the metadata of their Kubernetes pod.
+	ContainerLogFormat string `toml:"container_log_format"`
+
 	Encoding string `toml:"encoding"`
 	//The log group name for the input log file.
 	LogGroupName string `toml:"log_group_name"`
@@ -46,14 +58,32 @@ type FileConfig struct {
 	TimestampRegex string `toml:"timestamp_regex"`
 	//The timestampFromLogLine layout used in GoLang to parse the timestampFromLogLine.
 	TimestampLayout []string `toml:"timestamp_layout"`
-	//The time zone used to parse the timestampFromLogLine in the log entry.
+	//The time zone used to parse the timestampFromLogLine in the log entry, UTC,
+	//Local or the name of an IANA time zone.
 	Timezone string `toml:"timezone"`
+	//The other formats of the timestamps, tried in order when the timestamp regex
+	//does not parse the timestamp of the log entry.
+	TimestampFormats []*TimestampFormat `toml:"timestamp_formats"`
 
 	//Indicate whether it is a start of multiline.
 	//If this config is not present, it means the multiline mode is disabled.
 	//If this config is specified as "{timestamp_regex}", it means to use the same regex as timestampFromLogLine.
 	//If this config is specified as some regex, it will use the regex to determine if this line is a start line of multiline entry.
 	MultiLineStartPattern string `toml:"multi_line_start_pattern"`
+	//The lines that match the continue pattern are added to the current multiline
+	//entry, and the other lines start a new entry when there is no start pattern.
+	MultiLineContinuePattern string `toml:"multi_line_continue_pattern"`
+	//The line that matches the end pattern is the last line of the multiline entry.
+	MultiLineEndPattern string `toml:"multi_line_end_pattern"`
+	//The multiline entry is published once it has max lines lines, or before it
+	//goes over max bytes bytes.
+	MultiLineMaxLines int `toml:"multi_line_max_lines"`
+	MultiLineMaxBytes int `toml:"multi_line_max_bytes"`
+	//The multiline entry is published when no new entry starts for the flush
+	//timeout, 5s by default.
+	MultiLineFlushTimeout internal.Duration `toml:"multi_line_flush_timeout"`
+	//The preset continue pattern of the stack traces of java, python or go.
+	MultiLinePreset string `toml:"multi_line_preset"`
 
 	// automatically remove the file / symlink after uploading.
 	// This auto removal does not support the case where other log rotation mechanism is already in place.
@@ -63,6 +93,14 @@ type FileConfig struct {
 	//The default value for this field should be set as true in configuration.
 	//Otherwise, it may skip some log entries for timestampFromLogLine suffix roatated new file.
 	FromBeginning bool `toml:"from_beginning"`
+	//Backfill the content of the files when they are first found, and the rotated
+	//files, with the log events between since and until. They are either a time in
+	//RFC3339 format or a duration before the agent starts.
+	BackfillSince string `toml:"backfill_since"`
+	BackfillUntil string `toml:"backfill_until"`
+	//The most log events backfilled per second from each file.
+	BackfillRateLimit int `toml:"backfill_rate_limit"`
+
 	//Indicate whether it is a named pipe.
 	Pipe bool `toml:"pipe"`
 
@@ -83,17 +121,32 @@ type FileConfig struct {
 
 	Filters []*LogFilter `toml:"filters"`
 
+	//Enrich the log events with the metadata of the host and the file, in a json
+	//envelope or as key_value pairs appended to the message.
+	EnrichmentFormat string `toml:"enrichment_format"`
+	//The enrichment fields, instance_id, hostname, availability_zone, file_path
+	//and offset by default.
+	EnrichmentFields []string `toml:"enrichment_fields"`
+	//The instance tags added to the log events.
+	EnrichmentEC2Tags []string `toml:"enrichment_ec2_tags"`
+
 	//Time *time.Location Go type timezone info.
 	TimezoneLoc *time.Location
 	//Regexp go type timestampFromLogLine regex
 	TimestampRegexP *regexp.Regexp
 	//Regexp go type multiline start regex
 	MultiLineStartPatternP *regexp.Regexp
+	//Regexp go type multiline continue and end regex
+	MultiLineContinuePatternP *regexp.Regexp
+	MultiLineEndPatternP      *regexp.Regexp
 	//Regexp go type blacklist regex
 	BlacklistRegexP *regexp.Regexp
+	//Regexp go type file path regex
+	FilePathRegexP *regexp.Regexp
 	//Decoder object
 	Enc         encoding.Encoding
 	sampleCount int
+	backfill    *backfillWindow
 }
 
 // Initialize some variables in the FileConfig object based on the rest info fetched from the configuration file.
@@ -108,27 +161,75 @@ func (config *FileConfig) init() error {
 			}
 		}
 	}
+	switch config.ContainerLogFormat {
+	case "":
+	case ContainerLogFormatAuto, ContainerLogFormatCRI, ContainerLogFormatDocker:
+		//Each container log file is a log stream, in a log group per namespace by default.
+		config.PublishMultiLogs = true
+		if config.LogGroupName == "" {
+			config.LogGroupName = defaultContainerLogGroupName
+		}
+		if config.LogStreamName == "" {
+			config.LogStreamName = defaultContainerLogStreamName
+		}
+	default:
+		return fmt.Errorf("container_log_format %s is not supported", config.ContainerLogFormat)
+	}
 	//If the log group name is not specified, we will use the part before the last dot in the file path as the log group name.
 	if config.LogGroupName == "" && !config.PublishMultiLogs {
 		config.LogGroupName = logGroupName(config.FilePath)
 	}
 	//If the timezone info is not specified, we will use the Local timezone as default value.
-	if config.Timezone == time.UTC.String() {
-		config.TimezoneLoc = time.UTC
-	} else {
-		config.TimezoneLoc = time.Local
+	if config.TimezoneLoc, err = loadTimezone(config.Timezone); err != nil {
+		return err
 	}
 
+	//The epoch and iso8601 layouts have a default regex.
+	if config.TimestampRegex == "" && len(config.TimestampLayout) > 0 {
+		config.TimestampRegex = defaultTimestampRegex[config.TimestampLayout[0]]
+	}
 	if config.TimestampRegex != "" {
 		if config.TimestampRegexP, err = regexp.Compile(config.TimestampRegex); err != nil {
 			return fmt.Errorf("timestamp_regex has issue, regexp: Compile( %v ): %v", config.TimestampRegex, err.Error())
 		}
 	}
 
-	if config.MultiLineStartPattern == "" {
+	for i, format := range config.TimestampFormats {
+		if err = format.init(); err != nil {
+			return fmt.Errorf("timestamp_formats[%d] has issue: %v", i, err)
+		}
+	}
+
+	if config.MultiLinePreset != "" {
+		preset, ok := multiLinePresets[config.MultiLinePreset]
+		if !ok {
+			return fmt.Errorf("multi_line_preset %s is not supported", config.MultiLinePreset)
+		}
+		if config.MultiLineContinuePattern == "" {
+			config.MultiLineContinuePattern = preset
+		}
+	}
+	if config.MultiLineContinuePattern != "" {
+		if config.MultiLineContinuePatternP, err = regexp.Compile(config.MultiLineContinuePattern); err != nil {
+			return fmt.Errorf("multi_line_continue_pattern has issue, regexp: Compile( %v ): %v", config.MultiLineContinuePattern, err.Error())
+		}
+	}
+	if config.MultiLineEndPattern != "" {
+		if config.MultiLineEndPatternP, err = regexp.Compile(config.MultiLineEndPattern); err != nil {
+			return fmt.Errorf("multi_line_end_pattern has issue, regexp: Compile( %v ): %v", config.MultiLineEndPattern, err.Error())
+		}
+	}
+	if config.MultiLineMaxLines < 0 || config.MultiLineMaxBytes < 0 || config.MultiLineFlushTimeout.Duration < 0 {
+		return errors.New("multi_line_max_lines, multi_line_max_bytes and multi_line_flush_timeout cannot be negative")
+	}
+
+	//The continue pattern alone decides which lines start a new multiline entry.
+	if config.MultiLineStartPattern == "" && config.MultiLineContinuePatternP == nil {
 		config.MultiLineStartPattern = "^[\\S]"
 	}
-	if config.MultiLineStartPattern == "{timestamp_regex}" {
+	if config.MultiLineStartPattern == "" {
+		config.MultiLineStartPatternP = nil
+	} else if config.MultiLineStartPattern == "{timestamp_regex}" {
 		config.MultiLineStartPatternP = config.TimestampRegexP
 	} else {
 		if config.MultiLineStartPatternP, err = regexp.Compile(config.MultiLineStartPattern); err != nil {
@@ -142,6 +243,24 @@ func (config *FileConfig) init() error {
 		}
 	}
 
+	if err = config.initEnrichment(); err != nil {
+		return err
+	}
+
+	if config.FilePathRegex != "" {
+		if config.FilePathRegexP, err = regexp.Compile(config.FilePathRegex); err != nil {
+			return fmt.Errorf("file_path_regex has issue, regexp: Compile( %v ): %v", config.FilePathRegex, err.Error())
+		}
+	}
+
+	if config.BackfillSince != "" {
+		if config.backfill, err = newBackfillWindow(config.BackfillSince, config.BackfillUntil, config.BackfillRateLimit, config.RetentionInDays, time.Now()); err != nil {
+			return err
+		}
+	} else if config.BackfillUntil != "" {
+		return fmt.Errorf("backfill_until %s is set without backfill_since", config.BackfillUntil)
+	}
+
 	if config.MaxEventSize == 0 {
 		config.MaxEventSize = defaultMaxEventSize
 	}
@@ -154,7 +273,7 @@ func (config *FileConfig) init() error {
 	}
 
 	for _, f := range config.Filters {
-		err = f.init()
+		err = f.Init()
 		if err != nil {
 			return err
 		}
@@ -165,58 +284,53 @@ func (config *FileConfig) init() error {
 
 // Try to parse the timestampFromLogLine value from the log entry line.
 // The parser logic will be based on the timestampFromLogLine regex, and time zone info.
-// If the parsing operation encounters any issue, int64(0) is returned.
+// The timestamp formats are tried in order when the timestamp regex does not parse the timestamp.
 func (config *FileConfig) timestampFromLogLine(logValue string) time.Time {
-	if config.TimestampRegexP == nil {
-		return time.Time{}
-	}
-	index := config.TimestampRegexP.FindStringSubmatchIndex(logValue)
-	if len(index) > 3 {
-		timestampContent := (logValue)[index[2]:index[3]]
-		if len(index) > 5 {
-			start := index[4] - index[2]
-			end := index[5] - index[2]
-			//append "000" to 2nd submatch in order to guarantee the fractional second at least has 3 digits
-			fracSecond := fmt.Sprintf("%s000", timestampContent[start:end])
-			replacement := fmt.Sprintf(".%s", fracSecond[:3])
-			timestampContent = fmt.Sprintf("%s%s%s", timestampContent[:start], replacement, timestampContent[end:])
-		}
-		var err error
-		var timestamp time.Time
-		for _, timestampLayout := range config.TimestampLayout {
-			timestamp, err = time.ParseInLocation(timestampLayout, timestampContent, config.TimezoneLoc)
-			if err == nil {
-				break
-			}
+	var parseErr error
+	if config.TimestampRegexP != nil {
+		timestamp, err := parseTimestampMatch(config.TimestampRegexP, config.TimestampLayout, config.TimezoneLoc, logValue)
+		if err == nil && !timestamp.IsZero() {
+			return timestamp
+		}
+		parseErr = err
+	}
+	for _, format := range config.TimestampFormats {
+		timestamp, err := format.parse(logValue)
+		if err == nil && !timestamp.IsZero() {
+			return timestamp
 		}
 		if err != nil {
-			log.Printf("E! Error parsing timestampFromLogLine: %s", err)
-			return time.Time{}
-		}
-		if timestamp.Year() == 0 {
-			now := time.Now()
-			timestamp = timestamp.AddDate(now.Year(), 0, 0)
-			// If now is very early January and we are pushing logs from very late
-			// December, there will be a very large number of hours different
-			// between the dates. 30 * 24 hours will be sufficient.
-			if timestamp.Sub(now) > 30*24*time.Hour {
-				timestamp = timestamp.AddDate(-1, 0, 0)
-			}
+			parseErr = err
 		}
-		return timestamp
+	}
+	if parseErr != nil {
+		log.Printf("E! Error parsing timestampFromLogLine: %s", parseErr)
 	}
 	return time.Time{}
 }
 
 // This method determine whether the line is a start line for multiline log entry.
+// The lines that match the continue pattern never start a multiline log entry.
 func (config *FileConfig) isMultilineStart(logValue string) bool {
-
+	if config.MultiLineContinuePatternP != nil {
+		if config.MultiLineContinuePatternP.MatchString(logValue) {
+			return false
+		}
+		if config.MultiLineStartPatternP == nil {
+			return true
+		}
+	}
 	if config.MultiLineStartPatternP == nil {
 		return false
 	}
 	return config.MultiLineStartPatternP.MatchString(logValue)
 }
 
+// This method determine whether the line is the end line of a multiline log entry.
+func (config *FileConfig) isMultilineEnd(logValue string) bool {
+	return config.MultiLineEndPatternP != nil && config.MultiLineEndPatternP.MatchString(logValue)
+}
+
 func ShouldPublish(logGroupName, logStreamName string, filters []*LogFilter, event logs.LogEvent) bool {
 	if len(filters) == 0 {
 		return true
@@ -233,15 +233,26 @@ func TestTimestampParserWithFracSeconds(t *testing.T) {
 		fmt.Sprintf("The timestampFromLogLine value %v is not the same as expected %v.", timestamp, expectedTimestamp))
 }
 
-func TestNonAllowlistedTimezone(t *testing.T) {
+func TestNamedTimezone(t *testing.T) {
 	fileConfig := &FileConfig{
-		Timezone: "EST",
+		Timezone: "America/New_York",
 	}
 
 	err := fileConfig.init()
 	assert.NoError(t, err)
 
+	assert.Equal(t, "America/New_York", fileConfig.TimezoneLoc.String(), "The timezone location should be the named timezone.")
+
+	fileConfig = &FileConfig{
+		Timezone: "LOCAL",
+	}
+	assert.NoError(t, fileConfig.init())
 	assert.Equal(t, time.Local, fileConfig.TimezoneLoc, "The timezone location should be in local timezone.")
+
+	fileConfig = &FileConfig{
+		Timezone: "Mars/Olympus_Mons",
+	}
+	assert.ErrorContains(t, fileConfig.init(), "timezone Mars/Olympus_Mons is not a valid time zone")
 }
 
 func TestMultiLineStartPattern(t *testing.T) {
@@ -591,7 +602,7 @@ func assertNotPublishedForFilters(t *testing.T, filters []*LogFilter, msg string
 
 func initializeLogFilters(t *testing.T, filters []*LogFilter) []*LogFilter {
 	for _, f := range filters {
-		err := f.init()
+		err := f.Init()
 		assert.NoError(t, err)
 	}
 	return filters
@@ -600,7 +611,7 @@ func initializeLogFilters(t *testing.T, filters []*LogFilter) []*LogFilter {
 func initializeLogFiltersForBenchmarks(b *testing.B, filters []*LogFilter) []*LogFilter {
 	defer b.ResetTimer()
 	for _, f := range filters {
-		err := f.init()
+		err := f.Init()
 		assert.NoError(b, err)
 	}
 	return filters
@@ -77,6 +77,23 @@ func (g *GlobPath) Match() map[string]os.FileInfo {
 	return walkFilePath(g.root, g.g)
 }
 
+// WatchRoot returns the directory that contains all the files the path can
+// match, and how many levels of subdirectories below it can contain matches,
+// which is -1 when there is no limit.
+func (g *GlobPath) WatchRoot() (string, int) {
+	if !g.hasMeta && !g.hasSuperMeta {
+		return filepath.Dir(g.path), 0
+	}
+	if strings.Contains(g.path, "**") {
+		return g.root, -1
+	}
+	rel, err := filepath.Rel(g.root, g.path)
+	if err != nil {
+		return g.root, -1
+	}
+	return g.root, strings.Count(rel, sepStr)
+}
+
 // walk the filepath from the given root and return a list of files that match
 // the given glob.
 func walkFilePath(root string, g glob.Glob) map[string]os.FileInfo {
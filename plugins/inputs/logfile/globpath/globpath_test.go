@@ -210,6 +210,30 @@ func TestFindRootDir(t *testing.T) {
 	}
 }
 
+func TestWatchRoot(t *testing.T) {
+	tests := []struct {
+		input string
+		root  string
+		depth int
+	}{
+		{"/var/log/messages", "/var/log", 0},
+		{"/var/log/*.log", "/var/log", 0},
+		{"/var/log/*/*/app.log", "/var/log", 2},
+		{"/var/log/**.log", "/var/log", -1},
+		{"/var/log/{app,web}.log", "/var/log", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			g, err := Compile(test.input)
+			require.NoError(t, err)
+			root, depth := g.WatchRoot()
+			assert.Equal(t, test.root, root)
+			assert.Equal(t, test.depth, depth)
+		})
+	}
+}
+
 func TestFindNestedTextFile(t *testing.T) {
 	dir := getTestdataDir()
 	// test super asterisk
@@ -9,8 +9,12 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/influxdata/telegraf"
@@ -20,6 +24,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/globpath"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/tail"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/tail/watch"
 )
 
 type LogFile struct {
@@ -29,6 +34,12 @@ type LogFile struct {
 	FileStateFolder string `toml:"file_state_folder"`
 	//destination
 	Destination string `toml:"destination"`
+	//how new files and changes to the files are detected, "poll" or "inotify".
+	WatchMode string `toml:"watch_mode"`
+	//the most inotify watches to use before falling back to polling.
+	MaxWatches int `toml:"max_watches"`
+	//the most files to keep open at once, 0 means no limit.
+	MaxOpenFiles int `toml:"max_open_files"`
 
 	Log telegraf.Logger `toml:"-"`
 
@@ -36,13 +47,30 @@ type LogFile struct {
 	done              chan struct{}
 	removeTailerSrcCh chan *tailerSrc
 	started           bool
+	discoveryOnce     sync.Once
+	discovery         *fileDiscovery
+	backfills         map[*FileConfig]*backfillQueue
+	// openLimited is whether the warning that the open file limit is reached
+	// was logged for the file config.
+	openLimited map[*FileConfig]bool
+	// idleClosed are the files whose tailers were stopped to stay within the
+	// open file limit, and their modification time at that point. They are
+	// not tailed again until they are modified.
+	idleClosed map[string]time.Time
 }
 
+// idleFileTimeout is how long a file has to be unchanged before its tailer is
+// stopped to make room for files waiting on the open file limit.
+var idleFileTimeout = 5 * time.Minute
+
 func NewLogFile() *LogFile {
 	return &LogFile{
 		configs:           make(map[*FileConfig]map[string]*tailerSrc),
 		done:              make(chan struct{}),
 		removeTailerSrcCh: make(chan *tailerSrc, 100),
+		backfills:         make(map[*FileConfig]*backfillQueue),
+		openLimited:       make(map[*FileConfig]bool),
+		idleClosed:        make(map[string]time.Time),
 	}
 }
 
@@ -63,12 +91,32 @@ const sampleConfig = `
   ## folder path where state of how much of a file has been transferred is stored
   file_state_folder = "/tmp/logfile/state"
 
+  ## How new files and changes to the files are detected, "poll" or "inotify".
+  ## With inotify, the file_path globs are only evaluated again after a change
+  ## in the directories they match files in. Linux only.
+  watch_mode = "poll"
+  ## The most inotify watches to use, polling is used beyond that.
+  max_watches = 4096
+  ## The most files to keep open at once, the other files are tailed once
+  ## open files are closed. 0 means no limit.
+  max_open_files = 0
+
   [[inputs.logs.file_config]]
       file_path = "/tmp/logfile.log*"
       ## Regular expression for log files to ignore
       blacklist = "logfile.log.bak"
+      ## Parse the container logs written by the container runtime, "cri",
+      ## "docker" or "auto". The logs are wrapped in a JSON envelope with the
+      ## namespace, pod, container and labels of the Kubernetes pod.
+      # container_log_format = "auto"
+      ## Regular expression matched against each file path, its named groups
+      ## like (?P<app>[^/]+) can be used as {app} in the log group and stream names
+      # file_path_regex = "^/var/log/(?P<app>[^/]+)/"
       ## Publish all log files that match file_path
       publish_multi_logs = false
+      ## Tail all log files that match file_path into the same log stream,
+      ## instead of only the most recently modified one
+      tail_all_files = false
       log_group_name = "logfile.log"
       log_stream_name = "<log_stream_name>"
       publish_multi_logs = false
@@ -78,6 +126,11 @@ const sampleConfig = `
       multi_line_start_pattern = "{timestamp_regex}"
       ## Read file from beginning.
       from_beginning = false
+      ## Backfill the log events since a time or a duration before the agent
+      ## starts, from the files when they are first found and the rotated files
+      # backfill_since = "72h"
+      # backfill_until = "2024-03-10T06:00:00Z"
+      # backfill_rate_limit = 1000
       ## Whether file is a named pipe
       pipe = false
       destination = "cloudwatchlogs"
@@ -130,15 +183,42 @@ func (t *LogFile) Start(acc telegraf.Accumulator) error {
 		}
 	}
 
+	// Start is called by both the log agent and telegraf
+	t.discoveryOnce.Do(t.startDiscovery)
+
 	t.started = true
 	t.Log.Infof("turned on logs plugin")
 	return nil
 }
 
+// startDiscovery watches the directories of the file configs with inotify.
+func (t *LogFile) startDiscovery() {
+	if t.WatchMode != WatchModeInotify {
+		return
+	}
+	if runtime.GOOS != "linux" {
+		t.Log.Warnf("watch_mode %s is only supported on Linux, polling for files instead", t.WatchMode)
+		return
+	}
+	if t.MaxWatches <= 0 {
+		t.MaxWatches = defaultMaxWatches
+	}
+	watch.SetMaxWatches(t.MaxWatches)
+	t.discovery = newFileDiscovery(t.MaxWatches)
+	for i := range t.FileConfig {
+		if err := t.discovery.add(&t.FileConfig[i]); err != nil {
+			t.Log.Errorf("Failed to watch the directories of file config %v, with error: %v", t.FileConfig[i].FilePath, err)
+		}
+	}
+}
+
 func (t *LogFile) Stop() {
 	// Tailer srcs are stopped by log agent after the output plugin is stopped instead of here
 	// because the tailersrc would like to record an accurate uploaded offset
 	close(t.done)
+	if t.discovery != nil {
+		t.discovery.close()
+	}
 }
 
 // Try to find if there is any new file needs to be added for monitoring.
@@ -151,24 +231,52 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 	var srcs []logs.LogSrc
 
 	t.cleanUpStoppedTailerSrc()
+	if t.discovery != nil {
+		t.discovery.update()
+	}
 
 	// Create a "tailer" for each file
 	for i := range t.FileConfig {
 		fileconfig := &t.FileConfig[i]
+		if t.discovery != nil && !t.discovery.needsScan(fileconfig) {
+			continue
+		}
 		targetFiles, err := t.getTargetFiles(fileconfig)
 		if err != nil {
 			t.Log.Errorf("Failed to find target files for file config %v, with error: %v", fileconfig.FilePath, err)
 		}
-		for _, filename := range targetFiles {
-			dests, ok := t.configs[fileconfig]
-			if !ok {
-				dests = make(map[string]*tailerSrc)
-				t.configs[fileconfig] = dests
-			}
-
+		// The backfill starts with the first scan of the files
+		_, backfillStarted := t.backfills[fileconfig]
+		startBackfill := fileconfig.backfill != nil && !backfillStarted
+		// restored is whether the state of a file was restored, in which case the
+		// files were already backfilled
+		restored := false
+		// backfillTargets are the target files that are backfilled after the
+		// rotated files
+		var backfillTargets []*backfill
+		dests, ok := t.configs[fileconfig]
+		if !ok {
+			dests = make(map[string]*tailerSrc)
+			t.configs[fileconfig] = dests
+		}
+		if q := t.backfills[fileconfig]; q != nil && len(q.targets) > 0 {
+			targetFiles = slices.DeleteFunc(slices.Clone(targetFiles), q.queued)
+		}
+		openFiles := targetFiles
+		if t.MaxOpenFiles > 0 {
+			openFiles = t.filesWithinOpenLimit(fileconfig, targetFiles, dests)
+		}
+		for _, filename := range openFiles {
 			if _, ok := dests[filename]; ok {
 				continue
-			} else if fileconfig.AutoRemoval {
+			} else if t.MaxOpenFiles > 0 && tail.OpenFileCount.Load() >= int64(t.MaxOpenFiles) {
+				// The remaining files are tailed once some of the open files are closed
+				t.Log.Debugf("Open file limit %d reached, not tailing file %v yet", t.MaxOpenFiles, filename)
+				if t.discovery != nil {
+					t.discovery.markDirty(fileconfig)
+				}
+				break
+			} else if fileconfig.AutoRemoval && !fileconfig.TailAllFiles {
 				// This logic means auto_removal does not work with publish_multi_logs
 				for _, dst := range dests {
 					// Stop all other tailers in favor of the newly found file
@@ -180,91 +288,237 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 			offset, err := t.restoreState(filename)
 			if err == nil { // Missing state file would be an error too
 				seekFile = &tail.SeekInfo{Whence: io.SeekStart, Offset: offset}
+				restored = true
+			} else if startBackfill {
+				// The content of the file when it is first found is backfilled
+				// after the rotated files
+				if fileBackfill := newBackfill(fileconfig.backfill, filename); fileBackfill != nil {
+					backfillTargets = append(backfillTargets, fileBackfill)
+					continue
+				}
 			} else if !fileconfig.Pipe && !fileconfig.FromBeginning {
 				seekFile = &tail.SeekInfo{Whence: io.SeekEnd, Offset: 0}
 			}
 
-			isutf16 := false
-			if fileconfig.Encoding == "utf-16" || fileconfig.Encoding == "utf-16le" || fileconfig.Encoding == "UTF-16" || fileconfig.Encoding == "UTF-16LE" {
-				isutf16 = true
-			}
-
-			tailer, err := tail.TailFile(filename,
-				tail.Config{
-					ReOpen:      false,
-					Follow:      true,
-					Location:    seekFile,
-					MustExist:   true,
-					Pipe:        fileconfig.Pipe,
-					Poll:        true,
-					MaxLineSize: fileconfig.MaxEventSize,
-					IsUTF16:     isutf16,
-				})
-
+			src, err := t.tailFile(fileconfig, filename, seekFile, true)
 			if err != nil {
 				t.Log.Errorf("Failed to tail file %v with error: %v", filename, err)
 				continue
 			}
 
-			var mlCheck func(string) bool
-			if fileconfig.MultiLineStartPattern != "" {
-				mlCheck = fileconfig.isMultilineStart
-			}
+			srcs = append(srcs, src)
 
-			groupName := fileconfig.LogGroupName
-			streamName := fileconfig.LogStreamName
+			dests[filename] = src
+		}
 
-			// In case of multilog, the group and stream has to be generated here
-			// since it is based on the actual file name
-			if fileconfig.PublishMultiLogs {
-				if groupName == "" {
-					groupName = generateLogGroupName(filename)
-				} else {
-					streamName = generateLogStreamName(filename, fileconfig.LogStreamName)
-				}
-			}
+		if startBackfill {
+			t.backfills[fileconfig] = t.newBackfillQueue(fileconfig, targetFiles, backfillTargets, restored)
+		}
+		srcs = append(srcs, t.nextBackfillSrcs(fileconfig)...)
+	}
 
-			destination := fileconfig.Destination
-			if destination == "" {
-				destination = t.Destination
-			}
+	return srcs
+}
 
-			src := NewTailerSrc(
-				groupName, streamName,
-				t.Destination,
-				t.getStateFilePath(filename),
-				fileconfig.LogGroupClass,
-				tailer,
-				fileconfig.AutoRemoval,
-				mlCheck,
-				fileconfig.Filters,
-				fileconfig.timestampFromLogLine,
-				fileconfig.Enc,
-				fileconfig.MaxEventSize,
-				fileconfig.TruncateSuffix,
-				fileconfig.RetentionInDays,
-			)
-
-			src.AddCleanUpFn(func(ts *tailerSrc) func() {
-				return func() {
-					select {
-					case <-t.done: // No clean up needed after input plugin is stopped
-					case t.removeTailerSrcCh <- ts:
-					}
+// filesWithinOpenLimit returns the files of the file config to tail when the
+// number of open files is limited. The files are opened oldest first, so that
+// on startup the backlog of the oldest files is read first. If more files are
+// waiting than the limit allows, the tailers of idle files are stopped at the
+// end of their file to make room for them. A file is idle once it is rotated
+// away or unchanged for idleFileTimeout.
+func (t *LogFile) filesWithinOpenLimit(fileconfig *FileConfig, targetFiles []string, dests map[string]*tailerSrc) []string {
+	var newFiles []string
+	for _, filename := range targetFiles {
+		if _, ok := dests[filename]; !ok && !t.isIdleClosed(filename) {
+			newFiles = append(newFiles, filename)
+		}
+	}
+	available := t.MaxOpenFiles - int(tail.OpenFileCount.Load())
+	if len(newFiles) <= available {
+		delete(t.openLimited, fileconfig)
+		return newFiles
+	}
+	if available < 0 {
+		available = 0
+	}
+	waiting := len(newFiles) - available
+	for _, filename := range targetFiles {
+		if waiting == 0 {
+			break
+		}
+		src, ok := dests[filename]
+		if !ok {
+			continue
+		}
+		if modTime, idle := isIdleFile(src.tailer, filename); idle {
+			t.Log.Debugf("Stopping the tailer of idle file %v to stay within the open file limit %d", filename, t.MaxOpenFiles)
+			src.tailer.StopAtEOF()
+			t.idleClosed[filename] = modTime
+			waiting--
+		}
+	}
+	if !t.openLimited[fileconfig] {
+		t.Log.Warnf("Open file limit %d reached, %d files of %v are tailed once open files are closed", t.MaxOpenFiles, len(newFiles)-available, fileconfig.FilePath)
+		t.openLimited[fileconfig] = true
+	}
+	if t.discovery != nil {
+		t.discovery.markDirty(fileconfig)
+	}
+	return newFiles[:available]
+}
 
-				}
-			}(src))
+// isIdleClosed checks if the tailer of the file was stopped because the file
+// was idle, and the file was not modified since.
+func (t *LogFile) isIdleClosed(filename string) bool {
+	modTime, ok := t.idleClosed[filename]
+	if !ok {
+		return false
+	}
+	if info, err := os.Stat(filename); err == nil && info.ModTime().Equal(modTime) {
+		return true
+	}
+	delete(t.idleClosed, filename)
+	return false
+}
 
-			srcs = append(srcs, src)
+// isIdleFile checks if the file of the tailer is rotated away or was not
+// modified for idleFileTimeout, and returns its modification time.
+func isIdleFile(tailer *tail.Tail, filename string) (time.Time, bool) {
+	openInfo, err := tailer.Stat()
+	if err != nil {
+		return time.Time{}, false
+	}
+	info, err := os.Stat(filename)
+	if err != nil || !os.SameFile(info, openInfo) {
+		return openInfo.ModTime(), true
+	}
+	return info.ModTime(), time.Since(info.ModTime()) >= idleFileTimeout
+}
 
-			dests[filename] = src
+// tailFile creates the tailer source of the file. The tailer stops at the end of
+// the file unless it follows the file.
+func (t *LogFile) tailFile(fileconfig *FileConfig, filename string, seekFile *tail.SeekInfo, follow bool) (*tailerSrc, error) {
+	isutf16 := false
+	if fileconfig.Encoding == "utf-16" || fileconfig.Encoding == "utf-16le" || fileconfig.Encoding == "UTF-16" || fileconfig.Encoding == "UTF-16LE" {
+		isutf16 = true
+	}
+
+	tailer, err := tail.TailFile(filename,
+		tail.Config{
+			ReOpen:      false,
+			Follow:      follow,
+			Location:    seekFile,
+			MustExist:   true,
+			Pipe:        fileconfig.Pipe,
+			Poll:        t.discovery == nil,
+			MaxLineSize: fileconfig.MaxEventSize,
+			IsUTF16:     isutf16,
+		})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var mlCheck func(string) bool
+	if fileconfig.MultiLineStartPattern != "" || fileconfig.MultiLineContinuePatternP != nil {
+		mlCheck = fileconfig.isMultilineStart
+	}
+
+	groupName := fileconfig.LogGroupName
+	streamName := fileconfig.LogStreamName
+
+	// In case of multilog, the group and stream has to be generated here
+	// since it is based on the actual file name
+	if fileconfig.PublishMultiLogs {
+		if groupName == "" {
+			groupName = generateLogGroupName(filename)
+		} else if !fileconfig.hasFilePlaceholder(streamName) {
+			streamName = generateLogStreamName(filename, fileconfig.LogStreamName)
 		}
 	}
+	var container *containerLog
+	var info *containerInfo
+	if fileconfig.ContainerLogFormat != "" {
+		container = newContainerLog(fileconfig.ContainerLogFormat, filename, fileconfig.MaxEventSize)
+		info = container.info
+	}
+	now := time.Now()
+	groupName = fileconfig.resolveName(groupName, filename, now, info)
+	streamName = fileconfig.resolveName(streamName, filename, now, info)
 
-	return srcs
+	destination := fileconfig.Destination
+	if destination == "" {
+		destination = t.Destination
+	}
+
+	src := NewTailerSrc(
+		groupName, streamName,
+		destination,
+		t.getStateFilePath(filename),
+		fileconfig.LogGroupClass,
+		tailer,
+		fileconfig.AutoRemoval && !fileconfig.TailAllFiles,
+		mlCheck,
+		fileconfig.Filters,
+		fileconfig.timestampFromLogLine,
+		fileconfig.Enc,
+		fileconfig.MaxEventSize,
+		fileconfig.TruncateSuffix,
+		fileconfig.RetentionInDays,
+	)
+
+	src.container = container
+	src.enrichment = newEnrichment(fileconfig, filename)
+	src.multiline = multilineLimits{
+		maxLines:     fileconfig.MultiLineMaxLines,
+		maxBytes:     fileconfig.MultiLineMaxBytes,
+		flushTimeout: fileconfig.MultiLineFlushTimeout.Duration,
+	}
+	if fileconfig.MultiLineEndPatternP != nil {
+		src.multiline.isEnd = fileconfig.isMultilineEnd
+	}
+
+	src.AddCleanUpFn(func(ts *tailerSrc) func() {
+		return func() {
+			select {
+			case <-t.done: // No clean up needed after input plugin is stopped
+			case t.removeTailerSrcCh <- ts:
+			}
+
+		}
+	}(src))
+
+	return src, nil
 }
 
 func (t *LogFile) getTargetFiles(fileconfig *FileConfig) ([]string, error) {
+	matchedFiles, err := t.getMatchedFiles(fileconfig)
+	if err != nil {
+		return nil, err
+	}
+	if len(matchedFiles) == 0 {
+		return nil, nil
+	}
+	//If customer doesn't enable publish_multi_logs or tail_all_files feature, only the most recently modified file is tailed.
+	if !fileconfig.PublishMultiLogs && !fileconfig.TailAllFiles {
+		return []string{matchedFiles[len(matchedFiles)-1].name}, nil
+	}
+	names := make([]string, len(matchedFiles))
+	for i, f := range matchedFiles {
+		names[i] = f.name
+	}
+	return names, nil
+}
+
+type matchedFile struct {
+	name    string
+	modTime time.Time
+}
+
+// getMatchedFiles returns the files that match the file path, oldest first, so
+// that the backlog of the oldest files is read first when the number of open
+// files is limited.
+func (t *LogFile) getMatchedFiles(fileconfig *FileConfig) ([]matchedFile, error) {
 	filePath := fileconfig.FilePath
 	blacklistP := fileconfig.BlacklistRegexP
 	g, err := globpath.Compile(filePath)
@@ -272,9 +526,7 @@ func (t *LogFile) getTargetFiles(fileconfig *FileConfig) ([]string, error) {
 		return nil, fmt.Errorf("file_path glob %s failed to compile, %s", filePath, err)
 	}
 
-	var targetFileList []string
-	var targetFileName string
-	var targetModTime time.Time
+	var matchedFiles []matchedFile
 	for matchedFileName, matchedFileInfo := range g.Match() {
 
 		// we do not allow customer to monitor the file in t.FileStateFolder, it will monitor all of the state files
@@ -298,21 +550,16 @@ func (t *LogFile) getTargetFiles(fileconfig *FileConfig) ([]string, error) {
 		if blacklistP != nil && blacklistP.MatchString(fileBaseName) {
 			continue
 		}
-		if !fileconfig.PublishMultiLogs {
-			if targetFileName == "" || matchedFileInfo.ModTime().After(targetModTime) {
-				targetFileName = matchedFileName
-				targetModTime = matchedFileInfo.ModTime()
-			}
-		} else {
-			targetFileList = append(targetFileList, matchedFileName)
-		}
-	}
-	//If targetFileName != "", it means customer doesn't enable publish_multi_logs feature, targetFileList should be empty in this case.
-	if targetFileName != "" {
-		targetFileList = append(targetFileList, targetFileName)
+		matchedFiles = append(matchedFiles, matchedFile{matchedFileName, matchedFileInfo.ModTime()})
 	}
 
-	return targetFileList, nil
+	sort.SliceStable(matchedFiles, func(i, j int) bool {
+		if !matchedFiles[i].modTime.Equal(matchedFiles[j].modTime) {
+			return matchedFiles[i].modTime.Before(matchedFiles[j].modTime)
+		}
+		return matchedFiles[i].name < matchedFiles[j].name
+	})
+	return matchedFiles, nil
 }
 
 // The plugin will look at the state folder, and restore the offset of the file seeked if such state exists.
@@ -389,10 +636,14 @@ func (t *LogFile) cleanUpStoppedTailerSrc() {
 	for {
 		select {
 		case rts := <-t.removeTailerSrcCh:
-			for _, dsts := range t.configs {
+			for fileconfig, dsts := range t.configs {
 				for n, ts := range dsts {
 					if ts == rts {
 						delete(dsts, n)
+						// the file may be tailed again
+						if t.discovery != nil {
+							t.discovery.markDirty(fileconfig)
+						}
 					}
 				}
 			}
@@ -19,6 +19,7 @@ import (
 	"golang.org/x/text/transform"
 
 	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/tail"
 )
 
 const (
@@ -89,6 +90,35 @@ func TestLogs(t *testing.T) {
 	tt.Stop()
 }
 
+func TestLogsDestination(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"app.log", "access.log"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("line\n"), 0644))
+	}
+
+	tt := NewLogFile()
+	tt.Log = TestLogger{t}
+	tt.Destination = "cloudwatchlogs"
+	tt.FileConfig = []FileConfig{
+		{FilePath: filepath.Join(dir, "app.log"), Destination: "locallogs"},
+		{FilePath: filepath.Join(dir, "access.log")},
+	}
+	for i := range tt.FileConfig {
+		require.NoError(t, tt.FileConfig[i].init())
+	}
+	tt.started = true
+	defer tt.Stop()
+
+	lsrcs := tt.FindLogSrc()
+	require.Len(t, lsrcs, 2)
+	destinations := map[string]string{}
+	for _, lsrc := range lsrcs {
+		destinations[filepath.Base(lsrc.Description())] = lsrc.Destination()
+		lsrc.Stop()
+	}
+	assert.Equal(t, map[string]string{"app.log": "locallogs", "access.log": "cloudwatchlogs"}, destinations)
+}
+
 func TestLogsEncoding(t *testing.T) {
 	multilineWaitPeriod = 10 * time.Millisecond
 	//2 * rune_len when it is coded in gbk encoding.
@@ -1110,6 +1140,131 @@ func TestLogFileMultiLogsReadingWithBlacklist(t *testing.T) {
 	tt.Stop()
 }
 
+func TestLogFileTailAllFiles(t *testing.T) {
+	multilineWaitPeriod = 10 * time.Millisecond
+	dir := t.TempDir()
+	now := time.Now()
+	for i, name := range []string{"app-2.log", "app-1.log", "app-3.log"} {
+		filename := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(filename, []byte("from "+name+"\n"), 0644))
+		modTime := now.Add(time.Duration(i-3) * time.Minute)
+		require.NoError(t, os.Chtimes(filename, modTime, modTime))
+	}
+
+	tt := NewLogFile()
+	tt.Log = TestLogger{t}
+	tt.FileConfig = []FileConfig{{
+		FilePath:      filepath.Join(dir, "app-*.log"),
+		FromBeginning: true,
+		TailAllFiles:  true,
+		LogGroupName:  "app",
+		LogStreamName: "stream",
+	}}
+	require.NoError(t, tt.FileConfig[0].init())
+	tt.started = true
+	defer tt.Stop()
+
+	// the files are tailed oldest first
+	files, err := tt.getTargetFiles(&tt.FileConfig[0])
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "app-2.log"),
+		filepath.Join(dir, "app-1.log"),
+		filepath.Join(dir, "app-3.log"),
+	}, files)
+
+	lsrcs := tt.FindLogSrc()
+	require.Len(t, lsrcs, 3)
+	events := make(chan string, 3)
+	for _, lsrc := range lsrcs {
+		assert.Equal(t, "app", lsrc.Group())
+		assert.Equal(t, "stream", lsrc.Stream())
+		lsrc.SetOutput(func(e logs.LogEvent) {
+			if e != nil {
+				events <- e.Message()
+			}
+		})
+		defer lsrc.Stop()
+	}
+	var msgs []string
+	for range lsrcs {
+		select {
+		case msg := <-events:
+			msgs = append(msgs, msg)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for the logs, got %v", msgs)
+		}
+	}
+	assert.ElementsMatch(t, []string{"from app-1.log", "from app-2.log", "from app-3.log"}, msgs)
+}
+
+func TestLogFileMaxOpenFiles(t *testing.T) {
+	defer func(timeout time.Duration) { idleFileTimeout = timeout }(idleFileTimeout)
+	idleFileTimeout = 90 * time.Second
+	dir := t.TempDir()
+	now := time.Now()
+	for i, name := range []string{"new.log", "old.log"} {
+		filename := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(filename, []byte("line\n"), 0644))
+		modTime := now.Add(time.Duration(-i-1) * time.Minute)
+		require.NoError(t, os.Chtimes(filename, modTime, modTime))
+	}
+
+	tt := NewLogFile()
+	tt.Log = TestLogger{t}
+	// leave room for a single file besides the files opened by the other tests
+	tt.MaxOpenFiles = int(tail.OpenFileCount.Load()) + 1
+	tt.FileConfig = []FileConfig{{
+		FilePath:     filepath.Join(dir, "*.log"),
+		TailAllFiles: true,
+	}}
+	require.NoError(t, tt.FileConfig[0].init())
+	tt.started = true
+	defer tt.Stop()
+
+	// the oldest file is tailed first
+	lsrcs := tt.FindLogSrc()
+	require.Len(t, lsrcs, 1)
+	assert.Equal(t, filepath.Join(dir, "old.log"), lsrcs[0].Description())
+	old := lsrcs[0].(*tailerSrc)
+	old.outputFn = func(e logs.LogEvent) {}
+	stopped := make(chan struct{})
+	go func() {
+		old.runTail()
+		close(stopped)
+	}()
+
+	// the tailer of the idle file is stopped once it is caught up, so that
+	// the waiting file is tailed
+	assert.Empty(t, tt.FindLogSrc())
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the tailer of the idle file was not stopped")
+	}
+	lsrcs = tt.FindLogSrc()
+	require.Len(t, lsrcs, 1)
+	assert.Equal(t, filepath.Join(dir, "new.log"), lsrcs[0].Description())
+	defer lsrcs[0].(*tailerSrc).tailer.Stop()
+	defer lsrcs[0].Stop()
+
+	// the idle file waits for the limit once it is modified again, and the
+	// tailer of a file that is being written to is not stopped
+	modTime := now.Add(-time.Minute)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "new.log"), now, now))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "old.log"), modTime, modTime))
+	assert.Empty(t, tt.FindLogSrc())
+	assert.Empty(t, tt.idleClosed)
+	_, idle := isIdleFile(lsrcs[0].(*tailerSrc).tailer, filepath.Join(dir, "new.log"))
+	assert.False(t, idle)
+
+	// a file that is rotated away is idle
+	require.NoError(t, os.Rename(filepath.Join(dir, "new.log"), filepath.Join(dir, "new.log.1")))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.log"), []byte("line\n"), 0644))
+	_, idle = isIdleFile(lsrcs[0].(*tailerSrc).tailer, filepath.Join(dir, "new.log"))
+	assert.True(t, idle)
+}
+
 func TestGenerateLogGroupName(t *testing.T) {
 	multilineWaitPeriod = 10 * time.Millisecond
 	fileName := "C:\\tmp\\soak Test\\tmp0.log"
@@ -29,7 +29,8 @@ type LogFilter struct {
 	expressionP *regexp.Regexp
 }
 
-func (filter *LogFilter) init() error {
+// Init validates the filter type and compiles the expression.
+func (filter *LogFilter) Init() error {
 	if _, present := validFilterTypesSet[filter.Type]; !present {
 		return fmt.Errorf("filter type %s is incorrect, valid types are: %v", filter.Type, validFilterTypes)
 	}
@@ -82,7 +82,7 @@ func initLogFilter(filterType, expressionStr string) (LogFilter, error) {
 		Type:       filterType,
 		Expression: expressionStr,
 	}
-	err := filter.init()
+	err := filter.Init()
 	return filter, err
 }
 
@@ -0,0 +1,180 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"log"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/internal/retryer"
+)
+
+const (
+	pathPlaceholder   = "path"
+	ec2TagPlaceholder = "ec2_tag"
+	datePlaceholder   = "date"
+
+	unknownPlaceholderValue = "unknown"
+	imdsTagPrefix           = "tags/instance/"
+)
+
+var (
+	// namePlaceholder matches the placeholders like {path:2}, {ec2_tag:Name},
+	// {date}, {label:app} and the named groups of file_path_regex in the log
+	// group and log stream names.
+	namePlaceholder = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)(?::([^{}]+))?\}`)
+
+	// getEC2Tag looks up the value of an instance tag. The tags must be allowed
+	// in the instance metadata options of the instance.
+	getEC2Tag = imdsTag
+
+	// ec2TagRetryInterval is how long a failed lookup of an instance tag is
+	// cached before it is looked up again.
+	ec2TagRetryInterval = 5 * time.Minute
+
+	ec2TagsMu sync.Mutex
+	ec2Tags   = make(map[string]ec2TagLookup)
+
+	// imdsClient is shared by the tag lookups. It does not retry, since the
+	// failed lookups are retried after ec2TagRetryInterval.
+	imdsClient = sync.OnceValues(func() (*ec2metadata.EC2Metadata, error) {
+		ses, err := session.NewSession()
+		if err != nil {
+			return nil, err
+		}
+		return ec2metadata.New(ses, &aws.Config{
+			LogLevel: configaws.SDKLogLevel(),
+			Logger:   configaws.SDKLogger{},
+			Retryer:  retryer.NewIMDSRetryer(0),
+		}), nil
+	})
+)
+
+// ec2TagLookup is the result of the lookup of an instance tag.
+type ec2TagLookup struct {
+	value string
+	// failedAt is when the lookup failed, zero if it succeeded.
+	failedAt time.Time
+}
+
+// resolveName replaces the placeholders in the log group or log stream name
+// with the values for the file, and the Kubernetes metadata of the container
+// for container logs. The unknown placeholders are left as is.
+func (config *FileConfig) resolveName(name, filename string, now time.Time, container *containerInfo) string {
+	if !strings.Contains(name, "{") {
+		return name
+	}
+	var captures []string
+	if config.FilePathRegexP != nil {
+		captures = config.FilePathRegexP.FindStringSubmatch(filename)
+	}
+	return namePlaceholder.ReplaceAllStringFunc(name, func(placeholder string) string {
+		m := namePlaceholder.FindStringSubmatch(placeholder)
+		key, arg := m[1], m[2]
+		if config.ContainerLogFormat != "" {
+			if value, ok := container.value(key, arg); ok {
+				return nameValue(value)
+			}
+		}
+		var value string
+		switch {
+		case key == pathPlaceholder && arg != "":
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				return placeholder
+			}
+			value = pathElement(filename, n)
+		case key == ec2TagPlaceholder && arg != "":
+			value = ec2Tag(arg)
+		case key == datePlaceholder && arg == "":
+			value = now.Format("2006-01-02")
+		case arg == "" && config.FilePathRegexP != nil && config.FilePathRegexP.SubexpIndex(key) > 0:
+			if i := config.FilePathRegexP.SubexpIndex(key); i < len(captures) {
+				value = captures[i]
+			}
+		default:
+			return placeholder
+		}
+		return nameValue(value)
+	})
+}
+
+// nameValue replaces the characters that are not allowed in log group names.
+func nameValue(value string) string {
+	if value == "" {
+		return unknownPlaceholderValue
+	}
+	return generateLogGroupName(value)
+}
+
+// hasFilePlaceholder reports whether the name has a placeholder with a value
+// that depends on the file, so that it is different for each file.
+func (config *FileConfig) hasFilePlaceholder(name string) bool {
+	for _, m := range namePlaceholder.FindAllStringSubmatch(name, -1) {
+		key, arg := m[1], m[2]
+		if key == pathPlaceholder && arg != "" {
+			return true
+		}
+		if config.ContainerLogFormat != "" && arg == "" && (key == "pod_name" || key == "container_name") {
+			return true
+		}
+		if arg == "" && config.FilePathRegexP != nil && config.FilePathRegexP.SubexpIndex(key) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// pathElement returns the nth element of the file path counting from 1, or
+// from the end when n is negative so that -1 is the file name.
+func pathElement(filename string, n int) string {
+	elements := strings.Split(strings.Trim(filepath.ToSlash(filename), "/"), "/")
+	if n < 0 {
+		n += len(elements) + 1
+	}
+	if n < 1 || n > len(elements) {
+		return ""
+	}
+	return elements[n-1]
+}
+
+// ec2Tag returns the value of the instance tag. The value is looked up once,
+// but a failed lookup is retried after ec2TagRetryInterval. The lock is not
+// held during the lookup, so a slow lookup does not block the other tags.
+func ec2Tag(key string) string {
+	ec2TagsMu.Lock()
+	lookup, ok := ec2Tags[key]
+	ec2TagsMu.Unlock()
+	if ok && (lookup.failedAt.IsZero() || time.Since(lookup.failedAt) < ec2TagRetryInterval) {
+		return lookup.value
+	}
+	value, err := getEC2Tag(key)
+	lookup = ec2TagLookup{value: value}
+	if err != nil {
+		log.Printf("W! [logfile] Failed to get the value of instance tag %s, retrying in %v: %v", key, ec2TagRetryInterval, err)
+		lookup = ec2TagLookup{failedAt: time.Now()}
+	}
+	ec2TagsMu.Lock()
+	ec2Tags[key] = lookup
+	ec2TagsMu.Unlock()
+	return lookup.value
+}
+
+func imdsTag(key string) (string, error) {
+	md, err := imdsClient()
+	if err != nil {
+		return "", err
+	}
+	return md.GetMetadata(imdsTagPrefix + key)
+}
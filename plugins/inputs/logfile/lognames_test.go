@@ -0,0 +1,122 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveName(t *testing.T) {
+	getEC2Tag = func(key string) (string, error) {
+		if key == "Team" {
+			return "payments team", nil
+		}
+		return "", errors.New("not found")
+	}
+	defer func() {
+		getEC2Tag = imdsTag
+		ec2Tags = make(map[string]ec2TagLookup)
+	}()
+
+	config := &FileConfig{FilePath: "/var/log/**.log", FilePathRegex: `^/var/log/(?P<app>[^/]+)/(?P<component>[a-z]+)?`}
+	require.NoError(t, config.init())
+	now := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+	filename := "/var/log/checkout/api/server.log"
+	testCases := map[string]string{
+		"fixed":                        "fixed",
+		"/app/{path:3}/i-123":          "/app/checkout/i-123",
+		"{path:-1}":                    "server.log",
+		"{path:-2}/{path:9}":           "api/unknown",
+		"{app}/{component}":            "checkout/api",
+		"{ec2_tag:Team}/{ec2_tag:Env}": "payments_team/unknown",
+		"{app}-{date}":                 "checkout-2024-03-10",
+		"{other}/{path:x}/{}":          "{other}/{path:x}/{}",
+	}
+	for name, want := range testCases {
+		assert.Equal(t, want, config.resolveName(name, filename, now, nil), name)
+	}
+	// the regex does not match
+	assert.Equal(t, "unknown", config.resolveName("{app}", "/opt/app.log", now, nil))
+
+	assert.True(t, config.hasFilePlaceholder("{path:2}"))
+	assert.True(t, config.hasFilePlaceholder("prefix-{app}"))
+	assert.False(t, config.hasFilePlaceholder("{date}/{ec2_tag:Team}/{other}"))
+}
+
+func TestEC2TagRetry(t *testing.T) {
+	var lookups int
+	var err error
+	getEC2Tag = func(string) (string, error) {
+		lookups++
+		if err != nil {
+			return "", err
+		}
+		return "payments", nil
+	}
+	defer func(interval time.Duration) {
+		getEC2Tag = imdsTag
+		ec2Tags = make(map[string]ec2TagLookup)
+		ec2TagRetryInterval = interval
+	}(ec2TagRetryInterval)
+
+	err = errors.New("imds unavailable")
+	assert.Equal(t, "", ec2Tag("Team"))
+	assert.Equal(t, "", ec2Tag("Team"))
+	assert.Equal(t, 1, lookups)
+
+	// the failed lookup is retried after the interval
+	ec2TagRetryInterval = 0
+	err = nil
+	assert.Equal(t, "payments", ec2Tag("Team"))
+	assert.Equal(t, 2, lookups)
+
+	// the successful lookup is cached
+	assert.Equal(t, "payments", ec2Tag("Team"))
+	assert.Equal(t, 2, lookups)
+}
+
+func TestPathElement(t *testing.T) {
+	assert.Equal(t, "var", pathElement("/var/log/app.log", 1))
+	assert.Equal(t, "app.log", pathElement("/var/log/app.log", 3))
+	assert.Equal(t, "app.log", pathElement("/var/log/app.log", -1))
+	assert.Equal(t, "var", pathElement("/var/log/app.log", -3))
+	assert.Equal(t, "", pathElement("/var/log/app.log", 0))
+	assert.Equal(t, "", pathElement("/var/log/app.log", -4))
+}
+
+func TestLogFileNameTemplates(t *testing.T) {
+	dir := t.TempDir()
+	for _, app := range []string{"checkout", "search"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, app), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, app, "server.log"), []byte("line\n"), 0644))
+	}
+
+	tt := NewLogFile()
+	tt.Log = TestLogger{t}
+	tt.FileConfig = []FileConfig{{
+		FilePath:         filepath.Join(dir, "*", "server.log"),
+		FilePathRegex:    `/(?P<app>[^/]+)/server\.log$`,
+		LogGroupName:     "/app/{app}",
+		LogStreamName:    "{path:-1}",
+		FromBeginning:    true,
+		PublishMultiLogs: true,
+	}}
+	require.NoError(t, tt.FileConfig[0].init())
+	tt.started = true
+	defer tt.Stop()
+
+	names := map[string]string{}
+	for _, src := range tt.FindLogSrc() {
+		names[src.Group()] = src.Stream()
+		src.Stop()
+	}
+	assert.Equal(t, map[string]string{"/app/checkout": "server.log", "/app/search": "server.log"}, names)
+}
@@ -0,0 +1,65 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"time"
+)
+
+const (
+	MultiLinePresetJava   = "java"
+	MultiLinePresetPython = "python"
+	MultiLinePresetGo     = "go"
+
+	// defaultMultilineFlushTicks is the number of multilineWaitPeriod after which
+	// a multiline entry is published when no new entry starts.
+	defaultMultilineFlushTicks = 5
+)
+
+// multiLinePresets are the continue patterns of the lines of the stack traces,
+// so that an exception is published as one log event with the line logging it.
+var multiLinePresets = map[string]string{
+	// "\tat com.example.Main.main(Main.java:5)", "\t... 3 more",
+	// "Caused by: java.io.IOException: boom" and "java.lang.IllegalStateException"
+	MultiLinePresetJava: `^\s|^(Caused by|Suppressed):|^[\w$.]+(Exception|Error|Throwable)(: .*)?$`,
+	// "Traceback (most recent call last):", "  File "app.py", line 5, in <module>",
+	// "ValueError: boom" and the lines between the chained exceptions
+	MultiLinePresetPython: `^\s|^$|^Traceback \(most recent call last\):|^During handling of the above exception|^The above exception was the direct cause|^[A-Za-z_][\w.]*(Error|Exception|Exit|Interrupt|Warning|Iteration)(: .*)?$`,
+	// "goroutine 1 [running]:", "main.main()", "\t/app/main.go:5 +0x1d",
+	// "created by main.start in goroutine 1", "[signal SIGSEGV ...]" and "exit status 2"
+	MultiLinePresetGo: `^\s|^$|^goroutine \d+ \[|^created by |^\[signal |^exit status \d+$|^[\w./*()-]+\(.*\)$`,
+}
+
+// multilineLimits bound the multiline entries of a file besides its start and
+// continue patterns.
+type multilineLimits struct {
+	isEnd        func(string) bool
+	maxLines     int
+	maxBytes     int
+	flushTimeout time.Duration
+}
+
+// ends reports whether the multiline entry ends with the line.
+func (m *multilineLimits) ends(text string, lines int) bool {
+	return (m.maxLines > 0 && lines >= m.maxLines) || (m.isEnd != nil && m.isEnd(text))
+}
+
+// exceeds reports whether adding the line to the multiline entry makes it go
+// over the max bytes.
+func (m *multilineLimits) exceeds(size int, text string) bool {
+	return m.maxBytes > 0 && size+1+len(text) > m.maxBytes
+}
+
+// flushTicks returns the period of the ticker of the multiline entries and the
+// number of ticks after which they are published.
+func (m *multilineLimits) flushTicks() (time.Duration, int) {
+	if m.flushTimeout <= 0 {
+		return multilineWaitPeriod, defaultMultilineFlushTicks
+	}
+	period := multilineWaitPeriod
+	if m.flushTimeout < period {
+		period = m.flushTimeout
+	}
+	return period, int((m.flushTimeout + period - 1) / period)
+}
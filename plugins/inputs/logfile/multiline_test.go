@@ -0,0 +1,164 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+// tailMultiline tails the content with the file config and returns the first n
+// log events.
+func tailMultiline(t *testing.T, config FileConfig, content []string, n int) []string {
+	filename := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(filename, []byte(strings.Join(content, "\n")+"\n"), 0644))
+
+	tt := NewLogFile()
+	tt.Log = TestLogger{t}
+	config.FilePath = filename
+	config.FromBeginning = true
+	tt.FileConfig = []FileConfig{config}
+	require.NoError(t, tt.FileConfig[0].init())
+	tt.started = true
+	defer tt.Stop()
+
+	lsrcs := tt.FindLogSrc()
+	require.Len(t, lsrcs, 1)
+	events := make(chan logs.LogEvent, n)
+	lsrcs[0].SetOutput(func(e logs.LogEvent) {
+		if e != nil {
+			events <- e
+		}
+	})
+	defer lsrcs[0].Stop()
+
+	var msgs []string
+	for len(msgs) < n {
+		select {
+		case e := <-events:
+			msgs = append(msgs, e.Message())
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for log event %d, got %q", len(msgs), msgs)
+		}
+	}
+	return msgs
+}
+
+func TestMultilinePresets(t *testing.T) {
+	original := multilineWaitPeriod
+	defer resetState(original)
+	multilineWaitPeriod = 10 * time.Millisecond
+
+	testCases := map[string][]string{
+		MultiLinePresetJava: {
+			"2024-03-10 12:00:00 ERROR Request failed\njava.lang.IllegalStateException: boom\n" +
+				"\tat com.example.App.handle(App.java:42)\n\tat com.example.App.main(App.java:10)\n" +
+				"Caused by: java.io.IOException: disk full\n\tat com.example.Store.write(Store.java:7)\n\t... 2 more",
+		},
+		MultiLinePresetPython: {
+			"2024-03-10 12:00:00 ERROR Request failed\nTraceback (most recent call last):\n" +
+				"  File \"app.py\", line 5, in handle\n    store()\nOSError: disk full\n\n" +
+				"During handling of the above exception, another exception occurred:\n\n" +
+				"Traceback (most recent call last):\n  File \"app.py\", line 7, in handle\n    raise ValueError(\"boom\")\nValueError: boom",
+		},
+		MultiLinePresetGo: {
+			"panic: runtime error: invalid memory address or nil pointer dereference\n" +
+				"[signal SIGSEGV: segmentation violation code=0x1 addr=0x0 pc=0x45a2b1]\n\n" +
+				"goroutine 1 [running]:\nmain.(*Server).handle(0x0, {0x4b7f38, 0x3})\n\t/app/main.go:12 +0x11\n" +
+				"main.main()\n\t/app/main.go:20 +0x25\nexit status 2",
+		},
+	}
+	for preset, traces := range testCases {
+		t.Run(preset, func(t *testing.T) {
+			content := []string{"2024-03-10 11:59:59 INFO Started"}
+			content = append(content, traces...)
+			content = append(content, "2024-03-10 12:00:01 INFO Stopped")
+			msgs := tailMultiline(t, FileConfig{MultiLinePreset: preset}, content, len(content))
+			assert.Equal(t, content, msgs)
+		})
+	}
+
+	config := &FileConfig{FilePath: "/var/log/app.log", MultiLinePreset: "ruby"}
+	assert.EqualError(t, config.init(), "multi_line_preset ruby is not supported")
+}
+
+func TestMultilineContinuePattern(t *testing.T) {
+	original := multilineWaitPeriod
+	defer resetState(original)
+	multilineWaitPeriod = 10 * time.Millisecond
+
+	// the lines that match the continue pattern are added to the entry even when
+	// they are not indented
+	msgs := tailMultiline(t, FileConfig{MultiLineContinuePattern: `^(\s|>)`}, []string{
+		"first", "> second", "  third", "fourth", "> fifth",
+	}, 2)
+	assert.Equal(t, []string{"first\n> second\n  third", "fourth\n> fifth"}, msgs)
+
+	// the lines that match the continue pattern are added to the entry even when
+	// they match the start pattern
+	msgs = tailMultiline(t, FileConfig{MultiLineStartPattern: `^\d`, MultiLineContinuePattern: `^\d+ more$`}, []string{
+		"1 first", "2 more", "text", "3 second",
+	}, 2)
+	assert.Equal(t, []string{"1 first\n2 more\ntext", "3 second"}, msgs)
+
+	config := &FileConfig{FilePath: "/var/log/app.log", MultiLineContinuePattern: "("}
+	assert.ErrorContains(t, config.init(), "multi_line_continue_pattern has issue")
+}
+
+func TestMultilineLimits(t *testing.T) {
+	original := multilineWaitPeriod
+	defer resetState(original)
+	multilineWaitPeriod = 10 * time.Millisecond
+
+	// the entry is published with its end line without waiting for the next one
+	msgs := tailMultiline(t, FileConfig{MultiLineStartPattern: `^BEGIN`, MultiLineEndPattern: `^END`}, []string{
+		"BEGIN one", "a", "END one", "trailing", "BEGIN END", "BEGIN two", "b",
+	}, 4)
+	assert.Equal(t, []string{"BEGIN one\na\nEND one", "trailing", "BEGIN END", "BEGIN two\nb"}, msgs)
+
+	msgs = tailMultiline(t, FileConfig{MultiLineMaxLines: 2}, []string{
+		"one", " a", " b", " c", "two",
+	}, 3)
+	assert.Equal(t, []string{"one\n a", " b\n c", "two"}, msgs)
+
+	msgs = tailMultiline(t, FileConfig{MultiLineMaxBytes: 10}, []string{
+		"one", " aaa", " bbb", " c", "two",
+	}, 3)
+	assert.Equal(t, []string{"one\n aaa", " bbb\n c", "two"}, msgs)
+
+	config := &FileConfig{FilePath: "/var/log/app.log", MultiLineMaxLines: -1}
+	assert.Error(t, config.init())
+}
+
+func TestMultilineFlushTimeout(t *testing.T) {
+	original := multilineWaitPeriod
+	defer resetState(original)
+	multilineWaitPeriod = time.Minute
+
+	start := time.Now()
+	msgs := tailMultiline(t, FileConfig{MultiLineFlushTimeout: internal.Duration{Duration: 50 * time.Millisecond}}, []string{
+		"one", " a",
+	}, 1)
+	assert.Equal(t, []string{"one\n a"}, msgs)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	m := multilineLimits{flushTimeout: 2500 * time.Millisecond}
+	multilineWaitPeriod = time.Second
+	period, ticks := m.flushTicks()
+	assert.Equal(t, time.Second, period)
+	assert.Equal(t, 3, ticks)
+	m.flushTimeout = 0
+	period, ticks = m.flushTicks()
+	assert.Equal(t, time.Second, period)
+	assert.Equal(t, defaultMultilineFlushTicks, ticks)
+}
@@ -158,6 +158,15 @@ func (tail *Tail) Tell() (offset int64, err error) {
 	return
 }
 
+// Stat returns the FileInfo of the open file, which differs from the file at
+// the file name once the file is rotated away.
+func (tail *Tail) Stat() (os.FileInfo, error) {
+	if tail.file == nil {
+		return nil, os.ErrClosed
+	}
+	return tail.file.Stat()
+}
+
 // Stop stops the tailing activity.
 func (tail *Tail) Stop() error {
 	tail.Kill(nil)
@@ -435,6 +444,13 @@ func (tail *Tail) watchChanges() error {
 		return err
 	}
 	tail.changes, err = tail.watcher.ChangeEvents(&tail.Tomb, pos)
+	if err != nil && !tail.Poll {
+		// e.g. when the inotify watches are exhausted
+		tail.Logger.Warnf("Failed to watch %s with inotify, polling for changes instead: %v", tail.Filename, err)
+		tail.Poll = true
+		tail.watcher = watch.NewPollingFileWatcher(tail.Filename)
+		tail.changes, err = tail.watcher.ChangeEvents(&tail.Tomb, pos)
+	}
 	return err
 }
 
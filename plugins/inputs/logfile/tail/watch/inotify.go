@@ -120,6 +120,7 @@ func (fw *InotifyFileWatcher) ChangeEvents(t *tomb.Tomb, pos int64) (*FileChange
 						return
 					}
 					log.Printf("E! [logfile] Failed to stat file %v: %v", fw.Filename, err)
+					continue
 				}
 				fw.Size = fi.Size()
 
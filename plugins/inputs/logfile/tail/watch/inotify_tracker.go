@@ -4,6 +4,7 @@
 package watch
 
 import (
+	"errors"
 	"log"
 	"os"
 	"path/filepath"
@@ -19,20 +20,29 @@ type InotifyTracker struct {
 	chans     map[string]chan fsnotify.Event
 	done      map[string]chan bool
 	watchNums map[string]int
-	watch     chan *watchInfo
-	remove    chan *watchInfo
-	error     chan error
+	dirChans  map[string][]chan<- fsnotify.Event
+	// maxWatches bounds the number of watched paths, 0 means no bound.
+	maxWatches int
+	watch      chan *watchInfo
+	remove     chan *watchInfo
+	error      chan error
 }
 
 type watchInfo struct {
 	op    fsnotify.Op
 	fname string
+	// dirCh receives the events of the files in the fname directory.
+	dirCh chan<- fsnotify.Event
 }
 
 func (this *watchInfo) isCreate() bool {
 	return this.op == fsnotify.Create
 }
 
+// ErrTooManyWatches is returned when watching a path would exceed the
+// number of watches set with SetMaxWatches.
+var ErrTooManyWatches = errors.New("too many inotify watches")
+
 var (
 	// globally shared InotifyTracker; ensures only one fsnotify.Watcher is used
 	shared *InotifyTracker
@@ -45,6 +55,7 @@ var (
 			chans:     make(map[string]chan fsnotify.Event),
 			done:      make(map[string]chan bool),
 			watchNums: make(map[string]int),
+			dirChans:  make(map[string][]chan<- fsnotify.Event),
 			watch:     make(chan *watchInfo),
 			remove:    make(chan *watchInfo),
 			error:     make(chan error),
@@ -71,6 +82,26 @@ func WatchCreate(fname string) error {
 	})
 }
 
+// WatchDir signals the run goroutine to begin watching the directory. The
+// create, remove and rename events of the files in the directory are sent to
+// ch without blocking, so they are dropped when ch is full.
+func WatchDir(dir string, ch chan<- fsnotify.Event) error {
+	return watch(&watchInfo{
+		fname: dir,
+		dirCh: ch,
+	})
+}
+
+// SetMaxWatches bounds the number of paths watched by the shared
+// InotifyTracker. Watching more paths fails with ErrTooManyWatches.
+func SetMaxWatches(n int) {
+	once.Do(goRun)
+
+	shared.mux.Lock()
+	defer shared.mux.Unlock()
+	shared.maxWatches = n
+}
+
 func watch(winfo *watchInfo) error {
 	// start running the shared InotifyTracker if not already running
 	once.Do(goRun)
@@ -112,6 +143,18 @@ func remove(winfo *watchInfo) error {
 	return <-shared.error
 }
 
+// RemoveWatchDir signals the run goroutine to stop sending the events of the
+// directory to ch.
+func RemoveWatchDir(dir string, ch chan<- fsnotify.Event) error {
+	once.Do(goRun)
+
+	shared.remove <- &watchInfo{
+		fname: filepath.Clean(dir),
+		dirCh: ch,
+	}
+	return <-shared.error
+}
+
 // Events returns a channel to which FileEvents corresponding to the input filename
 // will be sent. This channel will be closed when removeWatch is called on this
 // filename.
@@ -133,28 +176,37 @@ func (shared *InotifyTracker) addWatch(winfo *watchInfo) error {
 	shared.mux.Lock()
 	defer shared.mux.Unlock()
 
-	if shared.chans[winfo.fname] == nil {
-		shared.chans[winfo.fname] = make(chan fsnotify.Event)
-	}
-	if shared.done[winfo.fname] == nil {
-		shared.done[winfo.fname] = make(chan bool)
-	}
-
 	fname := winfo.fname
 	if winfo.isCreate() {
 		// Watch for new files to be created in the parent directory.
 		fname = filepath.Dir(fname)
 	}
+	if shared.maxWatches > 0 && shared.watchNums[fname] == 0 && len(shared.watchNums) >= shared.maxWatches {
+		return ErrTooManyWatches
+	}
 
 	var err error
 	// already in inotify watch
 	if shared.watchNums[fname] == 0 {
 		err = shared.watcher.Add(fname)
 	}
-	if err == nil {
-		shared.watchNums[fname]++
+	if err != nil {
+		// nothing reads the events of a path that failed to be watched
+		return err
 	}
-	return err
+	shared.watchNums[fname]++
+
+	if winfo.dirCh != nil {
+		shared.dirChans[fname] = append(shared.dirChans[fname], winfo.dirCh)
+		return nil
+	}
+	if shared.chans[winfo.fname] == nil {
+		shared.chans[winfo.fname] = make(chan fsnotify.Event)
+	}
+	if shared.done[winfo.fname] == nil {
+		shared.done[winfo.fname] = make(chan bool)
+	}
+	return nil
 }
 
 // removeWatch calls fsnotify.RemoveWatch for the input filename and closes the
@@ -162,8 +214,20 @@ func (shared *InotifyTracker) addWatch(winfo *watchInfo) error {
 func (shared *InotifyTracker) removeWatch(winfo *watchInfo) error {
 	shared.mux.Lock()
 
-	ch := shared.chans[winfo.fname]
-	if ch != nil {
+	if winfo.dirCh != nil {
+		chans := shared.dirChans[winfo.fname]
+		for i, ch := range chans {
+			if ch == winfo.dirCh {
+				chans = append(chans[:i], chans[i+1:]...)
+				break
+			}
+		}
+		if len(chans) == 0 {
+			delete(shared.dirChans, winfo.fname)
+		} else {
+			shared.dirChans[winfo.fname] = chans
+		}
+	} else if ch := shared.chans[winfo.fname]; ch != nil {
 		delete(shared.chans, winfo.fname)
 		close(ch)
 	}
@@ -199,6 +263,15 @@ func (shared *InotifyTracker) sendEvent(event fsnotify.Event) {
 	shared.mux.Lock()
 	ch := shared.chans[name]
 	done := shared.done[name]
+	// the directories are only watched for files being added or removed
+	if event.Op&(fsnotify.Create|fsnotify.Remove|fsnotify.Rename) != 0 {
+		for _, dirCh := range shared.dirChans[filepath.Dir(name)] {
+			select {
+			case dirCh <- event:
+			default:
+			}
+		}
+	}
 	shared.mux.Unlock()
 
 	if ch != nil && done != nil {
@@ -214,7 +287,16 @@ func (shared *InotifyTracker) sendEvent(event fsnotify.Event) {
 func (shared *InotifyTracker) run() {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
-		log.Printf("E! [logfile] Failed to create Watcher")
+		log.Printf("E! [logfile] Failed to create Watcher: %v", err)
+		// fail the watches so that polling is used instead
+		for {
+			select {
+			case <-shared.watch:
+				shared.error <- err
+			case <-shared.remove:
+				shared.error <- err
+			}
+		}
 	}
 	shared.watcher = watcher
 
@@ -9,10 +9,12 @@ import (
 	"os"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/text/encoding"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/status"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/tail"
 )
@@ -69,6 +71,16 @@ type tailerSrc struct {
 	maxEventSize    int
 	truncateSuffix  string
 	retentionInDays int
+	// container parses the lines of container logs, nil for other files.
+	container *containerLog
+	// backfill bounds the time of the log events in the content of the file
+	// when it was found, nil when it is not backfilled.
+	backfill *backfill
+	// multiline bounds the multiline entries besides isMLStart.
+	multiline multilineLimits
+	// enrichment adds the metadata of the host and the file to the log events,
+	// nil when they are not enriched.
+	enrichment *enrichment
 
 	outputFn        func(logs.LogEvent)
 	isMLStart       func(string) bool
@@ -77,6 +89,10 @@ type tailerSrc struct {
 	done            chan struct{}
 	startTailerOnce sync.Once
 	cleanUpFns      []func()
+
+	// readOffset and savedOffset are reported by the status API.
+	readOffset  atomic.Int64
+	savedOffset atomic.Int64
 }
 
 // Verify tailerSrc implements LogSrc
@@ -168,11 +184,16 @@ func (ts *tailerSrc) AddCleanUpFn(f func()) {
 
 func (ts *tailerSrc) runTail() {
 	defer ts.cleanUp()
-	t := time.NewTicker(multilineWaitPeriod)
+	defer status.RegisterLogSource(ts.reportStatus)()
+	period, flushTicks := ts.multiline.flushTicks()
+	t := time.NewTicker(period)
 	defer t.Stop()
 	var init string
 	var msgBuf bytes.Buffer
-	var cnt int
+	// initLine and msgLine are the container log lines of init and msgBuf.
+	var initLine, msgLine containerLine
+	// lines is the number of lines in msgBuf.
+	var cnt, lines int
 	fo := &fileOffset{}
 
 	ignoreUntilNextEvent := false
@@ -182,17 +203,7 @@ func (ts *tailerSrc) runTail() {
 		case line, ok := <-ts.tailer.Lines:
 			if !ok {
 				if msgBuf.Len() > 0 {
-					msg := msgBuf.String()
-					e := &LogEvent{
-						msg:    msg,
-						t:      ts.timestampFn(msg),
-						offset: *fo,
-						src:    ts,
-					}
-
-					if ShouldPublish(ts.group, ts.stream, ts.filters, e) {
-						ts.outputFn(e)
-					}
+					ts.publish(msgBuf.String(), msgLine, *fo)
 				}
 				return
 			}
@@ -201,6 +212,7 @@ func (ts *tailerSrc) runTail() {
 				log.Printf("E! [logfile] Error tailing line in file %s, Error: %s\n", ts.tailer.Filename, line.Err)
 				continue
 			}
+			ts.readOffset.Store(line.Offset)
 
 			text := line.Text
 			if ts.enc != nil {
@@ -212,18 +224,34 @@ func (ts *tailerSrc) runTail() {
 				}
 			}
 
+			var cline containerLine
+			if ts.container != nil {
+				var complete bool
+				cline, complete = ts.container.parse(text)
+				if !complete {
+					continue
+				}
+				text = cline.log
+			}
+
 			if ts.isMLStart == nil {
 				msgBuf.Reset()
 				msgBuf.WriteString(text)
+				msgLine = cline
 				fo.SetOffset(line.Offset)
 				init = ""
 			} else if ts.isMLStart(text) || (!ignoreUntilNextEvent && msgBuf.Len() == 0) {
 				init = text
+				initLine = cline
 				ignoreUntilNextEvent = false
 			} else if ignoreUntilNextEvent || msgBuf.Len() >= ts.maxEventSize {
 				ignoreUntilNextEvent = true
 				fo.SetOffset(line.Offset)
 				continue
+			} else if ts.multiline.exceeds(msgBuf.Len(), text) {
+				// the line starts a new entry instead of going over the max bytes
+				init = text
+				initLine = cline
 			} else {
 				msgBuf.WriteString("\n")
 				msgBuf.WriteString(text)
@@ -232,55 +260,93 @@ func (ts *tailerSrc) runTail() {
 					msgBuf.WriteString(ts.truncateSuffix)
 				}
 				fo.SetOffset(line.Offset)
+				lines++
+				if ts.multiline.ends(text, lines) {
+					ts.publish(msgBuf.String(), msgLine, *fo)
+					msgBuf.Reset()
+					cnt = 0
+					lines = 0
+				}
 				continue
 			}
 
 			if msgBuf.Len() > 0 {
-				msg := msgBuf.String()
-				e := &LogEvent{
-					msg:    msg,
-					t:      ts.timestampFn(msg),
-					offset: *fo,
-					src:    ts,
-				}
-				// Note: This only checks against the truncated log message, so it is not necessary to load
-				//       the entire log message for filtering.
-				if ShouldPublish(ts.group, ts.stream, ts.filters, e) {
-					ts.outputFn(e)
-				}
+				ts.publish(msgBuf.String(), msgLine, *fo)
 			}
 
 			msgBuf.Reset()
 			msgBuf.WriteString(init)
+			msgLine = initLine
 			fo.SetOffset(line.Offset)
 			cnt = 0
+			lines = 1
+			if ts.isMLStart != nil && msgBuf.Len() > 0 && ts.multiline.ends(init, lines) {
+				ts.publish(msgBuf.String(), msgLine, *fo)
+				msgBuf.Reset()
+				lines = 0
+			}
 		case <-t.C:
 			if msgBuf.Len() > 0 {
 				cnt++
 			}
 
-			if cnt < 5 {
+			if cnt < flushTicks {
 				continue
 			}
 
-			msg := msgBuf.String()
-			e := &LogEvent{
-				msg:    msg,
-				t:      ts.timestampFn(msg),
-				offset: *fo,
-				src:    ts,
-			}
-			if ShouldPublish(ts.group, ts.stream, ts.filters, e) {
-				ts.outputFn(e)
-			}
+			ts.publish(msgBuf.String(), msgLine, *fo)
 			msgBuf.Reset()
 			cnt = 0
+			lines = 0
 		case <-ts.done:
 			return
 		}
 	}
 }
 
+// publish sends the message to the output unless it is filtered out. The
+// messages of container logs are wrapped with the metadata of the container,
+// and the enriched messages with the metadata of the host.
+func (ts *tailerSrc) publish(msg string, line containerLine, offset fileOffset) {
+	e := &LogEvent{
+		msg:    msg,
+		t:      ts.timestampFn(msg),
+		offset: offset,
+		src:    ts,
+	}
+	if e.t.IsZero() {
+		e.t = line.time
+	}
+	if ts.backfill != nil && !ts.backfill.admit(e, ts.done) {
+		// the skipped log events are done
+		ts.Done(offset)
+		return
+	}
+	// Note: This only checks against the truncated log message, so it is not necessary to load
+	//       the entire log message for filtering.
+	if !ShouldPublish(ts.group, ts.stream, ts.filters, e) {
+		return
+	}
+	if ts.container != nil {
+		e.msg = ts.container.wrap(msg, line)
+	}
+	if ts.enrichment != nil {
+		e.msg = ts.enrichment.wrap(e.msg, offset.offset)
+	}
+	ts.outputFn(e)
+}
+
+func (ts *tailerSrc) reportStatus() status.LogSource {
+	return status.LogSource{
+		Path:        ts.tailer.Filename,
+		Group:       ts.group,
+		Stream:      ts.stream,
+		Destination: ts.destination,
+		ReadOffset:  ts.readOffset.Load(),
+		SavedOffset: ts.savedOffset.Load(),
+	}
+}
+
 func (ts *tailerSrc) cleanUp() {
 	if ts.autoRemoval {
 		if err := os.Remove(ts.tailer.Filename); err != nil {
@@ -342,5 +408,9 @@ func (ts *tailerSrc) saveState(offset int64) error {
 	}
 
 	content := []byte(strconv.FormatInt(offset, 10) + "\n" + ts.tailer.Filename)
-	return os.WriteFile(ts.stateFilePath, content, stateFileMode)
+	if err := os.WriteFile(ts.stateFilePath, content, stateFileMode); err != nil {
+		return err
+	}
+	ts.savedOffset.Store(offset)
+	return nil
 }
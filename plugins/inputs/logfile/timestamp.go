@@ -0,0 +1,250 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	// The IANA time zones are embedded for the hosts without a time zone database.
+	_ "time/tzdata"
+)
+
+const (
+	TimestampLayoutEpochSeconds = "epoch_seconds"
+	TimestampLayoutEpochMillis  = "epoch_millis"
+	TimestampLayoutEpochNanos   = "epoch_nanos"
+	TimestampLayoutISO8601      = "iso8601"
+)
+
+var (
+	// defaultTimestampRegex is the regex of the timestamps in the layouts that
+	// are not Go layouts, used when no timestamp regex is set.
+	defaultTimestampRegex = map[string]string{
+		TimestampLayoutEpochSeconds: `\b(\d{10}(?:\.\d{1,9})?)\b`,
+		TimestampLayoutEpochMillis:  `\b(\d{13})\b`,
+		TimestampLayoutEpochNanos:   `\b(\d{19})\b`,
+		TimestampLayoutISO8601:      `(\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d{1,9})?(?:Z|[+-]\d{2}(?::?\d{2})?)?)`,
+	}
+
+	// iso8601Layouts are the Go layouts of the ISO8601 timestamps with the
+	// different offset formats. The fractional seconds are parsed without
+	// being in the layouts.
+	iso8601Layouts = []string{
+		"2006-01-02T15:04:05Z07:00",
+		"2006-01-02T15:04:05Z0700",
+		"2006-01-02T15:04:05Z07",
+		"2006-01-02T15:04:05",
+	}
+)
+
+// TimestampFormat is a format of the timestamps of the log events. The formats
+// of a file are tried in order until one of them parses the timestamp, so that
+// the files with log events in several formats have the right timestamps.
+type TimestampFormat struct {
+	//The regex with the timestamp as its first submatch. It defaults to the regex
+	//of the timestamps of the epoch and iso8601 layouts.
+	TimestampRegex string `toml:"timestamp_regex"`
+	//The layouts of the timestamp, Go layouts or epoch_seconds, epoch_millis,
+	//epoch_nanos and iso8601.
+	TimestampLayout []string `toml:"timestamp_layout"`
+	//The time zone of the timestamps without an offset, UTC, Local or the name of
+	//an IANA time zone like America/New_York.
+	Timezone string `toml:"timezone"`
+	//The field of the JSON log events with the timestamp, like time or meta.time.
+	JSONField string `toml:"json_field"`
+
+	regexP *regexp.Regexp
+	loc    *time.Location
+}
+
+func (f *TimestampFormat) init() error {
+	if len(f.TimestampLayout) == 0 {
+		return errors.New("timestamp_layout is missing")
+	}
+	regex := f.TimestampRegex
+	if regex == "" && f.JSONField == "" {
+		if regex = defaultTimestampRegex[f.TimestampLayout[0]]; regex == "" {
+			return fmt.Errorf("timestamp_regex or json_field is required for timestamp_layout %v", f.TimestampLayout)
+		}
+	}
+	var err error
+	if regex != "" {
+		if f.regexP, err = regexp.Compile(regex); err != nil {
+			return fmt.Errorf("timestamp_regex has issue, regexp: Compile( %v ): %v", regex, err)
+		}
+	}
+	if f.loc, err = loadTimezone(f.Timezone); err != nil {
+		return err
+	}
+	return nil
+}
+
+// parse returns the timestamp of the log event, or the zero time when the log
+// event has no timestamp in the format.
+func (f *TimestampFormat) parse(logValue string) (time.Time, error) {
+	if f.JSONField != "" {
+		value, ok := jsonField(logValue, f.JSONField)
+		if !ok {
+			return time.Time{}, nil
+		}
+		if f.regexP == nil {
+			return parseTimestamp(value, f.TimestampLayout, f.loc)
+		}
+		logValue = value
+	}
+	return parseTimestampMatch(f.regexP, f.TimestampLayout, f.loc, logValue)
+}
+
+// parseTimestampMatch parses the first submatch of the regex in the log event.
+func parseTimestampMatch(regexP *regexp.Regexp, layouts []string, loc *time.Location, logValue string) (time.Time, error) {
+	index := regexP.FindStringSubmatchIndex(logValue)
+	if len(index) <= 3 || index[2] < 0 {
+		return time.Time{}, nil
+	}
+	timestampContent := (logValue)[index[2]:index[3]]
+	if len(index) > 5 && index[4] >= 0 {
+		start := index[4] - index[2]
+		end := index[5] - index[2]
+		//append "000" to 2nd submatch in order to guarantee the fractional second at least has 3 digits
+		fracSecond := fmt.Sprintf("%s000", timestampContent[start:end])
+		replacement := fmt.Sprintf(".%s", fracSecond[:3])
+		timestampContent = fmt.Sprintf("%s%s%s", timestampContent[:start], replacement, timestampContent[end:])
+	}
+	return parseTimestamp(timestampContent, layouts, loc)
+}
+
+// parseTimestamp parses the timestamp with the first layout that matches it.
+func parseTimestamp(value string, layouts []string, loc *time.Location) (time.Time, error) {
+	var err error
+	var timestamp time.Time
+	for _, layout := range layouts {
+		switch layout {
+		case TimestampLayoutEpochSeconds:
+			timestamp, err = parseEpoch(value, time.Second)
+		case TimestampLayoutEpochMillis:
+			timestamp, err = parseEpoch(value, time.Millisecond)
+		case TimestampLayoutEpochNanos:
+			timestamp, err = parseEpoch(value, time.Nanosecond)
+		case TimestampLayoutISO8601:
+			timestamp, err = parseISO8601(value, loc)
+		default:
+			timestamp, err = time.ParseInLocation(layout, value, loc)
+		}
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	if timestamp.Year() == 0 {
+		now := time.Now()
+		timestamp = timestamp.AddDate(now.Year(), 0, 0)
+		// If now is very early January and we are pushing logs from very late
+		// December, there will be a very large number of hours different
+		// between the dates. 30 * 24 hours will be sufficient.
+		if timestamp.Sub(now) > 30*24*time.Hour {
+			timestamp = timestamp.AddDate(-1, 0, 0)
+		}
+	}
+	return timestamp, nil
+}
+
+// parseEpoch parses the number of units since the Unix epoch. The seconds can
+// have a fractional part.
+func parseEpoch(value string, unit time.Duration) (time.Time, error) {
+	whole, frac, hasFrac := strings.Cut(strings.TrimSpace(value), ".")
+	n, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("epoch timestamp %q is not a number", value)
+	}
+	var nanos int64
+	if hasFrac {
+		if unit != time.Second || len(frac) == 0 || len(frac) > 9 {
+			return time.Time{}, fmt.Errorf("epoch timestamp %q is not a number", value)
+		}
+		if nanos, err = strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64); err != nil {
+			return time.Time{}, fmt.Errorf("epoch timestamp %q is not a number", value)
+		}
+	}
+	switch unit {
+	case time.Second:
+		return time.Unix(n, nanos), nil
+	case time.Millisecond:
+		return time.UnixMilli(n), nil
+	default:
+		return time.Unix(0, n), nil
+	}
+}
+
+// parseISO8601 parses the ISO8601 timestamps, with their offset when they have
+// one and in the time zone otherwise.
+func parseISO8601(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if len(value) > 10 && value[10] == ' ' {
+		value = value[:10] + "T" + value[11:]
+	}
+	value = strings.Replace(value, ",", ".", 1)
+	var err error
+	for _, layout := range iso8601Layouts {
+		var timestamp time.Time
+		if timestamp, err = time.ParseInLocation(layout, value, loc); err == nil {
+			return timestamp, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// jsonField returns the value of the field of the JSON log event. The fields of
+// the nested objects are separated by dots.
+func jsonField(logValue, field string) (string, bool) {
+	logValue = strings.TrimSpace(logValue)
+	if !strings.HasPrefix(logValue, "{") {
+		return "", false
+	}
+	decoder := json.NewDecoder(bytes.NewBufferString(logValue))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", false
+	}
+	for _, key := range strings.Split(field, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if value, ok = m[key]; !ok {
+			return "", false
+		}
+	}
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	}
+	return "", false
+}
+
+// loadTimezone returns the location of the time zone, UTC, Local or the name of
+// an IANA time zone. Local is the default.
+func loadTimezone(name string) (*time.Location, error) {
+	switch name {
+	case "", "LOCAL", "Local":
+		return time.Local, nil
+	case "UTC":
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("timezone %s is not a valid time zone: %v", name, err)
+	}
+	return loc, nil
+}
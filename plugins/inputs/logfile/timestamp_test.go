@@ -0,0 +1,159 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestampLayouts(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	want := time.Date(2024, time.March, 10, 12, 30, 15, 0, time.UTC)
+
+	testCases := map[string]struct {
+		layout   string
+		timezone string
+		logValue string
+		want     time.Time
+	}{
+		"epoch seconds": {
+			layout:   TimestampLayoutEpochSeconds,
+			logValue: "1710073815 GET /health",
+			want:     want,
+		},
+		"epoch seconds with fraction": {
+			layout:   TimestampLayoutEpochSeconds,
+			logValue: "ts=1710073815.25 GET /health",
+			want:     want.Add(250 * time.Millisecond),
+		},
+		"epoch millis": {
+			layout:   TimestampLayoutEpochMillis,
+			logValue: "1710073815123 GET /health",
+			want:     want.Add(123 * time.Millisecond),
+		},
+		"epoch nanos": {
+			layout:   TimestampLayoutEpochNanos,
+			logValue: "1710073815123456789 GET /health",
+			want:     want.Add(123456789 * time.Nanosecond),
+		},
+		"iso8601 utc": {
+			layout:   TimestampLayoutISO8601,
+			logValue: "[2024-03-10T12:30:15.5Z] GET /health",
+			want:     want.Add(500 * time.Millisecond),
+		},
+		"iso8601 offset": {
+			layout:   TimestampLayoutISO8601,
+			timezone: "UTC",
+			logValue: "2024-03-10T14:30:15+02:00 GET /health",
+			want:     want,
+		},
+		"iso8601 offset without colon": {
+			layout:   TimestampLayoutISO8601,
+			logValue: "2024-03-10 07:30:15,000-0500 GET /health",
+			want:     want,
+		},
+		"iso8601 without offset": {
+			layout:   TimestampLayoutISO8601,
+			timezone: "America/New_York",
+			logValue: "2024-03-10T08:30:15 GET /health",
+			want:     want,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config := &FileConfig{
+				FilePath:        "/var/log/app.log",
+				TimestampLayout: []string{testCase.layout},
+				Timezone:        testCase.timezone,
+			}
+			require.NoError(t, config.init())
+			assert.True(t, testCase.want.Equal(config.timestampFromLogLine(testCase.logValue)), config.timestampFromLogLine(testCase.logValue))
+		})
+	}
+
+	// the named time zone is used for the timestamps without an offset
+	config := &FileConfig{
+		FilePath:        "/var/log/app.log",
+		TimestampRegex:  `^(\w{3} \s{0,1}\d{1,2} \d{2}:\d{2}:\d{2} \d{4})`,
+		TimestampLayout: []string{"Jan _2 15:04:05 2006"},
+		Timezone:        "America/New_York",
+	}
+	require.NoError(t, config.init())
+	assert.Equal(t, newYork, config.TimezoneLoc)
+	assert.True(t, want.Equal(config.timestampFromLogLine("Mar 10 08:30:15 2024 GET /health")))
+}
+
+func TestTimestampFormats(t *testing.T) {
+	want := time.Date(2024, time.March, 10, 12, 30, 15, 0, time.UTC)
+	config := &FileConfig{
+		FilePath:        "/var/log/app.log",
+		TimestampRegex:  `^(\d{2} \w{3} \d{4} \d{2}:\d{2}:\d{2})`,
+		TimestampLayout: []string{"02 Jan 2006 15:04:05"},
+		Timezone:        "UTC",
+		TimestampFormats: []*TimestampFormat{
+			{JSONField: "meta.time", TimestampLayout: []string{TimestampLayoutEpochMillis}},
+			{JSONField: "time", TimestampRegex: `(\d{2}/\d{2}/\d{4} \d{2}:\d{2}:\d{2})`, TimestampLayout: []string{"01/02/2006 15:04:05"}, Timezone: "Europe/Paris"},
+			{TimestampLayout: []string{TimestampLayoutISO8601}},
+		},
+	}
+	require.NoError(t, config.init())
+
+	testCases := map[string]time.Time{
+		"10 Mar 2024 12:30:15 [INFO] from the app":               want,
+		`{"meta":{"time":1710073815000},"msg":"from a sidecar"}`: want,
+		`{"time":"at 03/10/2024 13:30:15","msg":"from a proxy"}`: want,
+		`{"msg":"from the mesh","at":"2024-03-10T12:30:15Z"}`:    want,
+		"INFO 2024-03-10T13:30:15+01:00 from a job":              want,
+		"no timestamp": {},
+	}
+	for logValue, expected := range testCases {
+		assert.True(t, expected.Equal(config.timestampFromLogLine(logValue)), logValue)
+	}
+}
+
+func TestTimestampFormatInit(t *testing.T) {
+	testCases := map[string]struct {
+		format *TimestampFormat
+		err    string
+	}{
+		"missing layout": {
+			format: &TimestampFormat{TimestampRegex: `(\d+)`},
+			err:    "timestamp_layout is missing",
+		},
+		"missing regex": {
+			format: &TimestampFormat{TimestampLayout: []string{"2006-01-02"}},
+			err:    "timestamp_regex or json_field is required for timestamp_layout [2006-01-02]",
+		},
+		"invalid timezone": {
+			format: &TimestampFormat{TimestampLayout: []string{TimestampLayoutISO8601}, Timezone: "Nowhere"},
+			err:    "timezone Nowhere is not a valid time zone",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config := &FileConfig{FilePath: "/var/log/app.log", TimestampFormats: []*TimestampFormat{testCase.format}}
+			assert.ErrorContains(t, config.init(), testCase.err)
+		})
+	}
+}
+
+func TestJSONField(t *testing.T) {
+	value, ok := jsonField(`{"a":{"b":"x"},"n":12}`, "a.b")
+	assert.True(t, ok)
+	assert.Equal(t, "x", value)
+	value, ok = jsonField(`{"a":{"b":"x"},"n":12}`, "n")
+	assert.True(t, ok)
+	assert.Equal(t, "12", value)
+	_, ok = jsonField(`{"a":{"b":"x"}}`, "a")
+	assert.False(t, ok)
+	_, ok = jsonField(`{"a":{"b":"x"}}`, "a.c")
+	assert.False(t, ok)
+	_, ok = jsonField(`plain text`, "a")
+	assert.False(t, ok)
+}
@@ -0,0 +1,464 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package syslog
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile"
+	"github.com/aws/amazon-cloudwatch-agent/profiler"
+)
+
+const (
+	unknownFieldValue = "unknown"
+	srcBufferSize     = 100
+	// truncateSuffix is added to messages cut at the max message size, as the
+	// logfile plugin does by default.
+	truncateSuffix = "[Truncated...]"
+)
+
+var (
+	// fieldPlaceholder matches the message fields like {app_name} in the log
+	// group and log stream names.
+	fieldPlaceholder = regexp.MustCompile(`\{(syslog_hostname|app_name|proc_id|msg_id|facility|severity)\}`)
+)
+
+// listener receives syslog messages and routes them to a syslogSrc per log
+// group and log stream.
+type listener struct {
+	cfg ListenerConfig
+
+	packetConn  net.PacketConn
+	netListener net.Listener
+	conns       map[net.Conn]struct{}
+	wg          sync.WaitGroup
+
+	mu      sync.Mutex
+	srcs    map[string]*syslogSrc
+	pending []logs.LogSrc
+
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newListener(cfg ListenerConfig) *listener {
+	return &listener{
+		cfg:   cfg,
+		conns: make(map[net.Conn]struct{}),
+		srcs:  make(map[string]*syslogSrc),
+		done:  make(chan struct{}),
+	}
+}
+
+func (l *listener) Start() error {
+	var err error
+	switch l.cfg.Protocol {
+	case ProtocolUDP:
+		if l.packetConn, err = net.ListenPacket("udp", l.cfg.ServiceAddress); err != nil {
+			return err
+		}
+		l.wg.Add(1)
+		go l.readPackets()
+	case ProtocolTCP:
+		if l.netListener, err = net.Listen("tcp", l.cfg.ServiceAddress); err != nil {
+			return err
+		}
+		l.wg.Add(1)
+		go l.acceptConns()
+	case ProtocolTLS:
+		var tlsConfig *tls.Config
+		if tlsConfig, err = l.cfg.TLSConfig(); err != nil {
+			return err
+		}
+		if l.netListener, err = tls.Listen("tcp", l.cfg.ServiceAddress, tlsConfig); err != nil {
+			return err
+		}
+		l.wg.Add(1)
+		go l.acceptConns()
+	}
+	log.Printf("I! [syslog] Listening for syslog messages on %s", l.description())
+	return nil
+}
+
+func (l *listener) Stop() {
+	l.stopOnce.Do(func() {
+		close(l.done)
+		if l.packetConn != nil {
+			l.packetConn.Close()
+		}
+		if l.netListener != nil {
+			l.netListener.Close()
+		}
+		l.mu.Lock()
+		for conn := range l.conns {
+			conn.Close()
+		}
+		l.mu.Unlock()
+	})
+	l.wg.Wait()
+}
+
+func (l *listener) description() string {
+	return fmt.Sprintf("%s://%s", l.cfg.Protocol, l.addr())
+}
+
+func (l *listener) addr() string {
+	if l.packetConn != nil {
+		return l.packetConn.LocalAddr().String()
+	}
+	if l.netListener != nil {
+		return l.netListener.Addr().String()
+	}
+	return l.cfg.ServiceAddress
+}
+
+// newSrcs returns the log sources created since the last call.
+func (l *listener) newSrcs() []logs.LogSrc {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	srcs := l.pending
+	l.pending = nil
+	return srcs
+}
+
+// readPackets handles UDP, where each datagram is a single message.
+func (l *listener) readPackets() {
+	defer l.wg.Done()
+	buf := make([]byte, l.cfg.MaxMessageSize)
+	for {
+		n, _, err := l.packetConn.ReadFrom(buf)
+		if err != nil {
+			if l.stopped() {
+				return
+			}
+			log.Printf("W! [syslog] Failed to read from %s: %v", l.description(), err)
+			continue
+		}
+		l.handle(string(buf[:n]))
+	}
+}
+
+func (l *listener) acceptConns() {
+	defer l.wg.Done()
+	for {
+		conn, err := l.netListener.Accept()
+		if err != nil {
+			if l.stopped() {
+				return
+			}
+			log.Printf("W! [syslog] Failed to accept connection on %s: %v", l.description(), err)
+			continue
+		}
+		l.mu.Lock()
+		// the connection is accepted before the listener was closed
+		if l.stopped() {
+			l.mu.Unlock()
+			conn.Close()
+			return
+		}
+		if len(l.conns) >= l.cfg.MaxConnections {
+			l.mu.Unlock()
+			log.Printf("W! [syslog] Closing connection from %s on %s, the limit of %d connections is reached", conn.RemoteAddr(), l.description(), l.cfg.MaxConnections)
+			conn.Close()
+			continue
+		}
+		l.conns[conn] = struct{}{}
+		l.mu.Unlock()
+		l.wg.Add(1)
+		go l.readConn(conn)
+	}
+}
+
+// readConn handles a TCP or TLS connection. The messages are framed with
+// either octet counting or a trailing newline as described in RFC6587. The
+// connection is closed if no message is received within the read timeout.
+func (l *listener) readConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		l.mu.Lock()
+		delete(l.conns, conn)
+		l.mu.Unlock()
+		l.wg.Done()
+	}()
+	reader := bufio.NewReader(conn)
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(time.Duration(l.cfg.ReadTimeout))); err != nil {
+			log.Printf("W! [syslog] Failed to set read deadline for %s on %s: %v", conn.RemoteAddr(), l.description(), err)
+			return
+		}
+		msg, err := readFrame(reader, l.cfg.MaxMessageSize)
+		if len(strings.TrimSpace(msg)) > 0 {
+			l.handle(msg)
+		}
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				log.Printf("D! [syslog] Closing idle connection from %s on %s", conn.RemoteAddr(), l.description())
+			} else if err != io.EOF && !l.stopped() {
+				log.Printf("W! [syslog] Failed to read from %s on %s: %v", conn.RemoteAddr(), l.description(), err)
+			}
+			return
+		}
+	}
+}
+
+// readFrame reads the next message from the stream. Messages that start with
+// a digit are octet counted, the others are terminated by a newline. A newline
+// terminated message longer than maxSize is truncated and the rest of the
+// line is dropped.
+func readFrame(reader *bufio.Reader, maxSize int) (string, error) {
+	first, err := reader.Peek(1)
+	if err != nil {
+		return "", err
+	}
+	if first[0] >= '0' && first[0] <= '9' {
+		prefix, err := reader.ReadString(' ')
+		if err != nil {
+			return "", err
+		}
+		size, err := strconv.Atoi(strings.TrimSuffix(prefix, " "))
+		if err != nil || size <= 0 || size > maxSize {
+			return "", fmt.Errorf("invalid message length %q", prefix)
+		}
+		buf := make([]byte, size)
+		if _, err = io.ReadFull(reader, buf); err != nil {
+			return "", err
+		}
+		return string(buf), nil
+	}
+	var line []byte
+	truncated := false
+	for {
+		chunk, isPrefix, err := reader.ReadLine()
+		if !truncated {
+			line = append(line, chunk...)
+			if len(line) > maxSize {
+				if maxSize > len(truncateSuffix) {
+					line = append(line[:maxSize-len(truncateSuffix)], truncateSuffix...)
+				} else {
+					line = line[:maxSize]
+				}
+				truncated = true
+			}
+		}
+		if err != nil || !isPrefix {
+			return string(line), err
+		}
+	}
+}
+
+func (l *listener) stopped() bool {
+	select {
+	case <-l.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *listener) handle(raw string) {
+	m, err := parse(raw, l.cfg.Format, time.Now())
+	if err != nil {
+		log.Printf("W! [syslog] Failed to parse message received on %s: %v", l.description(), err)
+		return
+	}
+	msg, err := l.format(raw, m)
+	if err != nil {
+		log.Printf("W! [syslog] Failed to format message received on %s: %v", l.description(), err)
+		return
+	}
+	group := resolveFields(l.cfg.LogGroupName, m)
+	stream := resolveFields(l.cfg.LogStreamName, m)
+	e := &LogEvent{msg: msg, t: m.timestamp}
+	if !shouldPublish(group, stream, l.cfg.Filters, e) {
+		return
+	}
+	src := l.getSrc(group, stream)
+	select {
+	case src.events <- e:
+	case <-src.done:
+	case <-l.done:
+	}
+}
+
+// format returns the message as received, the MSG part of the message, or
+// all the fields of the message in the JSON format.
+func (l *listener) format(raw string, m *message) (string, error) {
+	switch l.cfg.EventFormat {
+	case EventFormatText:
+		return m.msg, nil
+	case EventFormatJSON:
+		fields := map[string]string{
+			"facility":  m.facilityName(),
+			"severity":  m.severityName(),
+			"timestamp": m.timestamp.Format(time.RFC3339Nano),
+			"message":   m.msg,
+		}
+		for key, value := range map[string]string{
+			"hostname":        m.hostname,
+			"app_name":        m.appName,
+			"proc_id":         m.procID,
+			"msg_id":          m.msgID,
+			"structured_data": m.structuredData,
+		} {
+			if value != "" {
+				fields[key] = value
+			}
+		}
+		out, err := json.Marshal(fields)
+		return string(out), err
+	default:
+		return strings.TrimRight(raw, "\r\n\x00"), nil
+	}
+}
+
+func (l *listener) getSrc(group, stream string) *syslogSrc {
+	key := group + "\n" + stream
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if src, ok := l.srcs[key]; ok {
+		return src
+	}
+	src := &syslogSrc{
+		group:    group,
+		stream:   stream,
+		listener: l,
+		events:   make(chan *LogEvent, srcBufferSize),
+		done:     make(chan struct{}),
+	}
+	l.srcs[key] = src
+	l.pending = append(l.pending, src)
+	return src
+}
+
+func shouldPublish(group, stream string, filters []*logfile.LogFilter, e logs.LogEvent) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, filter := range filters {
+		if !filter.ShouldPublish(e) {
+			profiler.Profiler.AddStats([]string{"syslog", group, stream, "messages", "dropped"}, 1)
+			return false
+		}
+	}
+	return true
+}
+
+// resolveFields replaces the field placeholders in the template with the
+// values of the message.
+func resolveFields(template string, m *message) string {
+	return fieldPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		var value string
+		switch placeholder[1 : len(placeholder)-1] {
+		case "syslog_hostname":
+			value = m.hostname
+		case "app_name":
+			value = m.appName
+		case "proc_id":
+			value = m.procID
+		case "msg_id":
+			value = m.msgID
+		case "facility":
+			value = m.facilityName()
+		case "severity":
+			value = m.severityName()
+		}
+		if value == "" {
+			return unknownFieldValue
+		}
+		return value
+	})
+}
+
+// syslogSrc is the log source of the messages mapped to a single log group
+// and log stream.
+type syslogSrc struct {
+	group    string
+	stream   string
+	listener *listener
+
+	events    chan *LogEvent
+	outputFn  func(logs.LogEvent)
+	startOnce sync.Once
+	done      chan struct{}
+	stopOnce  sync.Once
+}
+
+var _ logs.LogSrc = (*syslogSrc)(nil)
+
+func (s *syslogSrc) SetOutput(fn func(logs.LogEvent)) {
+	if fn == nil {
+		return
+	}
+	s.outputFn = fn
+	s.startOnce.Do(func() { go s.run() })
+}
+
+func (s *syslogSrc) run() {
+	for {
+		select {
+		case e := <-s.events:
+			s.outputFn(e)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *syslogSrc) Group() string {
+	return s.group
+}
+
+func (s *syslogSrc) Stream() string {
+	return s.stream
+}
+
+func (s *syslogSrc) Destination() string {
+	return s.listener.cfg.Destination
+}
+
+func (s *syslogSrc) Description() string {
+	return s.listener.description()
+}
+
+func (s *syslogSrc) Retention() int {
+	return s.listener.cfg.Retention
+}
+
+func (s *syslogSrc) Class() string {
+	return s.listener.cfg.LogGroupClass
+}
+
+func (s *syslogSrc) Stop() {
+	s.stopOnce.Do(func() { close(s.done) })
+}
+
+type LogEvent struct {
+	msg string
+	t   time.Time
+}
+
+func (le LogEvent) Message() string {
+	return le.msg
+}
+
+func (le LogEvent) Time() time.Time {
+	return le.t
+}
+
+// Done is a no-op since the messages cannot be received again.
+func (le LogEvent) Done() {}
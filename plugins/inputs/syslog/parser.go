@@ -0,0 +1,246 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package syslog
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatAuto    = "auto"
+	FormatRFC5424 = "rfc5424"
+	FormatRFC3164 = "rfc3164"
+
+	nilValue = "-"
+	utf8BOM  = "\xef\xbb\xbf"
+	maxPri   = 191
+	// maxTagLength is the longest TAG accepted in an RFC3164 message.
+	maxTagLength = 48
+)
+
+var (
+	facilityNames = []string{
+		"kern", "user", "mail", "daemon", "auth", "syslog", "lpr", "news",
+		"uucp", "cron", "authpriv", "ftp", "ntp", "security", "console", "solaris-cron",
+		"local0", "local1", "local2", "local3", "local4", "local5", "local6", "local7",
+	}
+	severityNames = []string{
+		"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug",
+	}
+
+	errMissingPri = errors.New("message does not start with a valid <PRI>")
+)
+
+// message is a parsed syslog message. Fields that are not present in the
+// message are left empty.
+type message struct {
+	facility       int
+	severity       int
+	timestamp      time.Time
+	hostname       string
+	appName        string
+	procID         string
+	msgID          string
+	structuredData string
+	msg            string
+}
+
+func (m *message) facilityName() string {
+	return facilityNames[m.facility]
+}
+
+func (m *message) severityName() string {
+	return severityNames[m.severity]
+}
+
+// parse parses an RFC5424 or RFC3164 message. With FormatAuto, the format is
+// detected from the VERSION that follows the PRI in RFC5424 messages. The
+// timestamp is set to now when the message does not contain a valid one.
+func parse(raw string, format string, now time.Time) (*message, error) {
+	raw = strings.TrimRight(raw, "\r\n\x00")
+	pri, rest, err := parsePri(raw)
+	if err != nil {
+		return nil, err
+	}
+	m := &message{facility: pri / 8, severity: pri % 8}
+	switch format {
+	case FormatRFC5424:
+		err = parseRFC5424(m, rest)
+	case FormatRFC3164:
+		parseRFC3164(m, rest, now)
+	default:
+		if strings.HasPrefix(rest, "1 ") {
+			err = parseRFC5424(m, rest)
+		} else {
+			parseRFC3164(m, rest, now)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if m.timestamp.IsZero() {
+		m.timestamp = now
+	}
+	return m, nil
+}
+
+func parsePri(raw string) (int, string, error) {
+	if !strings.HasPrefix(raw, "<") {
+		return 0, "", errMissingPri
+	}
+	end := strings.IndexByte(raw, '>')
+	if end < 2 || end > 4 {
+		return 0, "", errMissingPri
+	}
+	pri, err := strconv.Atoi(raw[1:end])
+	if err != nil || pri < 0 || pri > maxPri {
+		return 0, "", errMissingPri
+	}
+	return pri, raw[end+1:], nil
+}
+
+// parseRFC5424 parses the message following the PRI:
+// VERSION SP TIMESTAMP SP HOSTNAME SP APP-NAME SP PROCID SP MSGID SP STRUCTURED-DATA [SP MSG]
+func parseRFC5424(m *message, rest string) error {
+	fields := make([]string, 0, 6)
+	for i := 0; i < 6; i++ {
+		field, remaining, found := strings.Cut(rest, " ")
+		if !found {
+			return fmt.Errorf("RFC5424 message is missing header fields")
+		}
+		fields = append(fields, field)
+		rest = remaining
+	}
+	if fields[0] != "1" {
+		return fmt.Errorf("unsupported RFC5424 version %s", fields[0])
+	}
+	if fields[1] != nilValue {
+		t, err := time.Parse(time.RFC3339Nano, fields[1])
+		if err != nil {
+			return fmt.Errorf("invalid RFC5424 timestamp %s", fields[1])
+		}
+		m.timestamp = t
+	}
+	m.hostname = headerField(fields[2])
+	m.appName = headerField(fields[3])
+	m.procID = headerField(fields[4])
+	m.msgID = headerField(fields[5])
+
+	sd, rest, err := splitStructuredData(rest)
+	if err != nil {
+		return err
+	}
+	m.structuredData = headerField(sd)
+	m.msg = strings.TrimPrefix(strings.TrimPrefix(rest, " "), utf8BOM)
+	return nil
+}
+
+func headerField(field string) string {
+	if field == nilValue {
+		return ""
+	}
+	return field
+}
+
+// splitStructuredData returns the STRUCTURED-DATA, which is either the
+// NILVALUE or a series of [SD-ELEMENT]s, and the rest of the message.
+func splitStructuredData(rest string) (string, string, error) {
+	if strings.HasPrefix(rest, nilValue) {
+		return nilValue, rest[len(nilValue):], nil
+	}
+	i := 0
+	for i < len(rest) && rest[i] == '[' {
+		inValue := false
+		for i++; i < len(rest); i++ {
+			c := rest[i]
+			if inValue && c == '\\' {
+				i++
+				continue
+			}
+			if c == '"' {
+				inValue = !inValue
+			} else if c == ']' && !inValue {
+				break
+			}
+		}
+		if i >= len(rest) {
+			return "", "", fmt.Errorf("unterminated RFC5424 structured data")
+		}
+		i++
+	}
+	if i == 0 {
+		return "", "", fmt.Errorf("RFC5424 message is missing structured data")
+	}
+	return rest[:i], rest[i:], nil
+}
+
+// parseRFC3164 parses the message following the PRI on a best effort basis,
+// since the format is only a description of common practice:
+// TIMESTAMP SP HOSTNAME SP TAG[PID]: MSG
+// The parts that cannot be found are left in the message.
+func parseRFC3164(m *message, rest string, now time.Time) {
+	if t, remaining, ok := parseRFC3164Timestamp(rest, now); ok {
+		m.timestamp = t
+		rest = remaining
+		// the hostname only follows a timestamp
+		if field, remaining, found := strings.Cut(rest, " "); found && field != "" && !isTag(field) {
+			m.hostname = field
+			rest = remaining
+		}
+	}
+	m.appName, m.procID, rest = parseTag(rest)
+	m.msg = strings.TrimPrefix(rest, " ")
+}
+
+// parseRFC3164Timestamp parses the "Mmm dd hh:mm:ss" timestamp, which has no
+// year or time zone, as well as the RFC3339 timestamps sent by some daemons.
+func parseRFC3164Timestamp(rest string, now time.Time) (time.Time, string, bool) {
+	if len(rest) > len(time.Stamp) && rest[len(time.Stamp)] == ' ' {
+		if t, err := time.ParseInLocation(time.Stamp, rest[:len(time.Stamp)], now.Location()); err == nil {
+			t = t.AddDate(now.Year(), 0, 0)
+			// messages from the end of last year received early in January
+			if t.After(now.AddDate(0, 0, 1)) {
+				t = t.AddDate(-1, 0, 0)
+			}
+			return t, rest[len(time.Stamp)+1:], true
+		}
+	}
+	if field, remaining, found := strings.Cut(rest, " "); found {
+		if t, err := time.Parse(time.RFC3339Nano, field); err == nil {
+			return t, remaining, true
+		}
+	}
+	return time.Time{}, rest, false
+}
+
+// parseTag splits "app[pid]: msg" or "app: msg" into its parts.
+func parseTag(rest string) (appName, procID, msg string) {
+	end := strings.IndexAny(rest, ":[ ")
+	if end <= 0 || end > maxTagLength {
+		return "", "", rest
+	}
+	appName = rest[:end]
+	switch rest[end] {
+	case ':':
+		return appName, "", rest[end+1:]
+	case '[':
+		closing := strings.Index(rest[end:], "]:")
+		if closing < 0 {
+			return "", "", rest
+		}
+		return appName, rest[end+1 : end+closing], rest[end+closing+2:]
+	default:
+		return "", "", rest
+	}
+}
+
+// isTag reports whether the field following the timestamp is the TAG of a
+// message without a hostname.
+func isTag(field string) bool {
+	return strings.HasSuffix(field, ":")
+}
@@ -0,0 +1,167 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package syslog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2024, time.March, 10, 12, 0, 0, 0, time.UTC)
+	testCases := map[string]struct {
+		raw     string
+		format  string
+		want    *message
+		wantErr bool
+	}{
+		"RFC5424": {
+			raw:    `<165>1 2024-03-10T11:59:58.123Z router1 sshd 4242 ID47 [exampleSDID@32473 iut="3" eventSource="App\]lication"] Accepted publickey`,
+			format: FormatAuto,
+			want: &message{
+				facility:       20,
+				severity:       5,
+				timestamp:      time.Date(2024, time.March, 10, 11, 59, 58, 123000000, time.UTC),
+				hostname:       "router1",
+				appName:        "sshd",
+				procID:         "4242",
+				msgID:          "ID47",
+				structuredData: `[exampleSDID@32473 iut="3" eventSource="App\]lication"]`,
+				msg:            "Accepted publickey",
+			},
+		},
+		"RFC5424WithNilValues": {
+			raw:    "<13>1 - - - - - -\n",
+			format: FormatRFC5424,
+			want:   &message{facility: 1, severity: 5, timestamp: now},
+		},
+		"RFC5424WithBOM": {
+			raw:    "<13>1 2024-03-10T11:00:00+02:00 host app - - [a@1][b@1 x=\"y\"] \xef\xbb\xbfhello",
+			format: FormatAuto,
+			want: &message{
+				facility:       1,
+				severity:       5,
+				timestamp:      time.Date(2024, time.March, 10, 9, 0, 0, 0, time.UTC),
+				hostname:       "host",
+				appName:        "app",
+				structuredData: `[a@1][b@1 x="y"]`,
+				msg:            "hello",
+			},
+		},
+		"RFC3164": {
+			raw:    "<34>Mar  9 22:14:15 mymachine su[123]: 'su root' failed for lonvick on /dev/pts/8",
+			format: FormatAuto,
+			want: &message{
+				facility:  4,
+				severity:  2,
+				timestamp: time.Date(2024, time.March, 9, 22, 14, 15, 0, time.UTC),
+				hostname:  "mymachine",
+				appName:   "su",
+				procID:    "123",
+				msg:       "'su root' failed for lonvick on /dev/pts/8",
+			},
+		},
+		"RFC3164FromLastYear": {
+			raw:    "<13>Dec 31 23:59:59 host cron: job",
+			format: FormatRFC3164,
+			want: &message{
+				facility:  1,
+				severity:  5,
+				timestamp: time.Date(2023, time.December, 31, 23, 59, 59, 0, time.UTC),
+				hostname:  "host",
+				appName:   "cron",
+				msg:       "job",
+			},
+		},
+		"RFC3164WithoutHostname": {
+			raw:    "<13>Mar 10 11:00:00 kernel: link up",
+			format: FormatAuto,
+			want: &message{
+				facility:  1,
+				severity:  5,
+				timestamp: time.Date(2024, time.March, 10, 11, 0, 0, 0, time.UTC),
+				appName:   "kernel",
+				msg:       "link up",
+			},
+		},
+		"RFC3164WithRFC3339Timestamp": {
+			raw:    "<30>2024-03-10T11:00:00.5Z host systemd[1]: Started",
+			format: FormatAuto,
+			want: &message{
+				facility:  3,
+				severity:  6,
+				timestamp: time.Date(2024, time.March, 10, 11, 0, 0, 500000000, time.UTC),
+				hostname:  "host",
+				appName:   "systemd",
+				procID:    "1",
+				msg:       "Started",
+			},
+		},
+		"RFC3164WithoutHeader": {
+			raw:    "<13>just a message",
+			format: FormatAuto,
+			want:   &message{facility: 1, severity: 5, timestamp: now, msg: "just a message"},
+		},
+		"MissingPri": {
+			raw:     "Mar 10 11:00:00 host app: message",
+			format:  FormatAuto,
+			wantErr: true,
+		},
+		"InvalidPri": {
+			raw:     "<192>1 - - - - - -",
+			format:  FormatAuto,
+			wantErr: true,
+		},
+		"RFC5424MissingHeader": {
+			raw:     "<13>1 2024-03-10T11:00:00Z host",
+			format:  FormatAuto,
+			wantErr: true,
+		},
+		"RFC5424InvalidTimestamp": {
+			raw:     "<13>1 yesterday host app - - -",
+			format:  FormatRFC5424,
+			wantErr: true,
+		},
+		"RFC5424UnterminatedStructuredData": {
+			raw:     `<13>1 - host app - - [a@1 x="]`,
+			format:  FormatRFC5424,
+			wantErr: true,
+		},
+		"RFC3164AsRFC5424": {
+			raw:     "<13>Mar 10 11:00:00 host app: message",
+			format:  FormatRFC5424,
+			wantErr: true,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parse(testCase.raw, testCase.format, now)
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, testCase.want.timestamp.Equal(got.timestamp), got.timestamp)
+			got.timestamp = testCase.want.timestamp
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestResolveFields(t *testing.T) {
+	m := &message{facility: 4, severity: 6, hostname: "router1", appName: "sshd"}
+	testCases := map[string]string{
+		"syslog":                          "syslog",
+		"syslog/{facility}/{severity}":    "syslog/auth/info",
+		"{syslog_hostname}-{app_name}":    "router1-sshd",
+		"{proc_id}/{msg_id}":              "unknown/unknown",
+		"{instance_id}/{syslog_hostname}": "{instance_id}/router1",
+	}
+	for template, want := range testCases {
+		assert.Equal(t, want, resolveFields(template, m), template)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package syslog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/plugins/inputs"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/tls"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile"
+)
+
+const (
+	ProtocolUDP = "udp"
+	ProtocolTCP = "tcp"
+	ProtocolTLS = "tls"
+
+	EventFormatRaw  = "raw"
+	EventFormatText = "text"
+	EventFormatJSON = "json"
+
+	defaultMaxMessageSize = 64 * 1024
+	// TCP and TLS connections that send nothing within the read timeout are
+	// closed, and new connections are refused at the max connections.
+	defaultReadTimeout    = 5 * time.Minute
+	defaultMaxConnections = 1024
+)
+
+type ListenerConfig struct {
+	Protocol       string               `toml:"protocol"`
+	ServiceAddress string               `toml:"service_address"`
+	Format         string               `toml:"format"`
+	EventFormat    string               `toml:"event_format"`
+	MaxMessageSize int                  `toml:"max_message_size"`
+	ReadTimeout    config.Duration      `toml:"read_timeout"`
+	MaxConnections int                  `toml:"max_connections"`
+	LogGroupName   string               `toml:"log_group_name"`
+	LogStreamName  string               `toml:"log_stream_name"`
+	LogGroupClass  string               `toml:"log_group_class"`
+	Destination    string               `toml:"destination"`
+	Retention      int                  `toml:"retention_in_days"`
+	Filters        []*logfile.LogFilter `toml:"filters"`
+	tls.ServerConfig
+}
+
+type Plugin struct {
+	Listeners   []ListenerConfig `toml:"listener_config"`
+	Destination string           `toml:"destination"`
+	Log         telegraf.Logger  `toml:"-"`
+
+	startOnce sync.Once
+	listeners []*listener
+}
+
+func (p *Plugin) Description() string {
+	return "A plugin to receive RFC5424 and RFC3164 syslog messages over UDP, TCP or TLS"
+}
+
+func (p *Plugin) SampleConfig() string {
+	return `
+	[[inputs.syslog.listener_config]]
+	protocol = "udp"
+	service_address = ":514"
+	format = "auto"
+	event_format = "raw"
+	log_group_name = "syslog/{facility}"
+	log_stream_name = "{syslog_hostname}"
+	destination = "cloudwatchlogs"
+	`
+}
+
+func (p *Plugin) Gather(acc telegraf.Accumulator) error {
+	return nil
+}
+
+// FindLogSrc returns a log source for each log group and log stream that the
+// received messages have been mapped to since the last call.
+func (p *Plugin) FindLogSrc() []logs.LogSrc {
+	var srcs []logs.LogSrc
+	for _, l := range p.listeners {
+		srcs = append(srcs, l.newSrcs()...)
+	}
+	return srcs
+}
+
+// Start is called by both the log agent and telegraf, so the listeners are
+// only started once.
+func (p *Plugin) Start(acc telegraf.Accumulator) error {
+	var err error
+	p.startOnce.Do(func() {
+		for i := range p.Listeners {
+			cfg := p.Listeners[i]
+			if err = initListenerConfig(&cfg, p.Destination); err != nil {
+				return
+			}
+			l := newListener(cfg)
+			if err = l.Start(); err != nil {
+				err = fmt.Errorf("failed to start syslog listener on %s://%s: %w", cfg.Protocol, cfg.ServiceAddress, err)
+				return
+			}
+			p.listeners = append(p.listeners, l)
+		}
+	})
+	return err
+}
+
+// Stop closes the listeners. The log sources are stopped by the log agent
+// after the output plugin is stopped.
+func (p *Plugin) Stop() {
+	for _, l := range p.listeners {
+		l.Stop()
+	}
+}
+
+func initListenerConfig(cfg *ListenerConfig, destination string) error {
+	if cfg.Destination == "" {
+		cfg.Destination = destination
+	}
+	switch cfg.Protocol {
+	case "":
+		cfg.Protocol = ProtocolUDP
+	case ProtocolUDP, ProtocolTCP:
+	case ProtocolTLS:
+		if cfg.TLSCert == "" || cfg.TLSKey == "" {
+			return fmt.Errorf("syslog listener on %s requires tls_cert and tls_key", cfg.ServiceAddress)
+		}
+	default:
+		return fmt.Errorf("syslog protocol %s is incorrect, valid protocols are: %v", cfg.Protocol, []string{ProtocolUDP, ProtocolTCP, ProtocolTLS})
+	}
+	switch cfg.Format {
+	case "":
+		cfg.Format = FormatAuto
+	case FormatAuto, FormatRFC5424, FormatRFC3164:
+	default:
+		return fmt.Errorf("syslog format %s is incorrect, valid formats are: %v", cfg.Format, []string{FormatAuto, FormatRFC5424, FormatRFC3164})
+	}
+	if cfg.EventFormat == "" {
+		cfg.EventFormat = EventFormatRaw
+	}
+	if cfg.MaxMessageSize <= 0 {
+		cfg.MaxMessageSize = defaultMaxMessageSize
+	}
+	if cfg.ReadTimeout <= 0 {
+		cfg.ReadTimeout = config.Duration(defaultReadTimeout)
+	}
+	if cfg.MaxConnections <= 0 {
+		cfg.MaxConnections = defaultMaxConnections
+	}
+	if cfg.Retention == 0 {
+		cfg.Retention = -1
+	}
+	for _, f := range cfg.Filters {
+		if err := f.Init(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	inputs.Add("syslog", func() telegraf.Input { return &Plugin{} })
+}
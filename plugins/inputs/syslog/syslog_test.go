@@ -0,0 +1,300 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package syslog
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	cryptotls "crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/tls"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile"
+)
+
+const (
+	sshdMessage = `<38>1 2024-03-10T11:59:58Z router1 sshd 4242 - - Accepted publickey`
+	cronMessage = "<78>Mar 10 11:00:00 router2 CRON[99]: Job started"
+)
+
+type collector struct {
+	mu     sync.Mutex
+	events map[string][]logs.LogEvent
+	srcs   []logs.LogSrc
+}
+
+func (c *collector) find(plugin *Plugin) int {
+	for _, src := range plugin.FindLogSrc() {
+		src := src
+		c.srcs = append(c.srcs, src)
+		src.SetOutput(func(e logs.LogEvent) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			key := src.Group() + "/" + src.Stream()
+			c.events[key] = append(c.events[key], e)
+		})
+	}
+	return len(c.srcs)
+}
+
+func (c *collector) messages(key string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var messages []string
+	for _, e := range c.events[key] {
+		messages = append(messages, e.Message())
+	}
+	return messages
+}
+
+func startPlugin(t *testing.T, plugin *Plugin) *collector {
+	require.NoError(t, plugin.Start(nil))
+	// the log agent and telegraf both call Start
+	require.NoError(t, plugin.Start(nil))
+	c := &collector{events: map[string][]logs.LogEvent{}}
+	t.Cleanup(func() {
+		plugin.Stop()
+		for _, src := range c.srcs {
+			src.Stop()
+		}
+	})
+	return c
+}
+
+func TestSyslogUDP(t *testing.T) {
+	plugin := &Plugin{
+		Destination: "cloudwatchlogs",
+		Listeners: []ListenerConfig{{
+			ServiceAddress: "127.0.0.1:0",
+			LogGroupName:   "syslog/{facility}",
+			LogStreamName:  "{syslog_hostname}",
+			Filters:        []*logfile.LogFilter{{Type: "exclude", Expression: "DEBUG"}},
+		}},
+	}
+	c := startPlugin(t, plugin)
+
+	conn, err := net.Dial("udp", plugin.listeners[0].addr())
+	require.NoError(t, err)
+	defer conn.Close()
+	for _, msg := range []string{sshdMessage, cronMessage, "<38>1 - router1 sshd - - - DEBUG message", "not syslog"} {
+		_, err = conn.Write([]byte(msg))
+		require.NoError(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		return c.find(plugin) == 2 && len(c.messages("syslog/auth/router1")) == 1 && len(c.messages("syslog/cron/router2")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{sshdMessage}, c.messages("syslog/auth/router1"))
+	assert.Equal(t, []string{cronMessage}, c.messages("syslog/cron/router2"))
+	assert.Equal(t, time.Date(2024, time.March, 10, 11, 59, 58, 0, time.UTC), c.events["syslog/auth/router1"][0].Time())
+
+	src := c.srcs[0]
+	assert.Equal(t, "cloudwatchlogs", src.Destination())
+	assert.Equal(t, -1, src.Retention())
+	assert.True(t, strings.HasPrefix(src.Description(), "udp://127.0.0.1:"))
+}
+
+func TestSyslogTCP(t *testing.T) {
+	plugin := &Plugin{
+		Listeners: []ListenerConfig{{
+			Protocol:       ProtocolTCP,
+			ServiceAddress: "127.0.0.1:0",
+			EventFormat:    EventFormatText,
+			LogGroupName:   "syslog",
+			LogStreamName:  "{app_name}",
+			Destination:    "cloudwatchlogs",
+		}},
+	}
+	c := startPlugin(t, plugin)
+
+	conn, err := net.Dial("tcp", plugin.listeners[0].addr())
+	require.NoError(t, err)
+	defer conn.Close()
+	// newline and octet counting framing can be mixed on a connection
+	_, err = fmt.Fprintf(conn, "%s\n%d %s", cronMessage, len(sshdMessage), sshdMessage)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return c.find(plugin) == 2 && len(c.messages("syslog/sshd")) == 1 && len(c.messages("syslog/CRON")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"Accepted publickey"}, c.messages("syslog/sshd"))
+	assert.Equal(t, []string{"Job started"}, c.messages("syslog/CRON"))
+}
+
+func TestSyslogTLS(t *testing.T) {
+	certFile, keyFile := writeCertificate(t)
+	plugin := &Plugin{
+		Listeners: []ListenerConfig{{
+			Protocol:       ProtocolTLS,
+			ServiceAddress: "127.0.0.1:0",
+			EventFormat:    EventFormatJSON,
+			LogGroupName:   "syslog",
+			LogStreamName:  "{syslog_hostname}",
+			ServerConfig:   tls.ServerConfig{TLSCert: certFile, TLSKey: keyFile},
+		}},
+	}
+	c := startPlugin(t, plugin)
+
+	conn, err := cryptotls.Dial("tcp", plugin.listeners[0].addr(), &cryptotls.Config{InsecureSkipVerify: true})
+	require.NoError(t, err)
+	defer conn.Close()
+	w := bufio.NewWriter(conn)
+	_, err = fmt.Fprintf(w, "%d %s", len(sshdMessage), sshdMessage)
+	require.NoError(t, err)
+	require.NoError(t, w.Flush())
+
+	require.Eventually(t, func() bool {
+		return c.find(plugin) == 1 && len(c.messages("syslog/router1")) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	var fields map[string]string
+	require.NoError(t, json.Unmarshal([]byte(c.messages("syslog/router1")[0]), &fields))
+	assert.Equal(t, map[string]string{
+		"facility":  "auth",
+		"severity":  "info",
+		"timestamp": "2024-03-10T11:59:58Z",
+		"hostname":  "router1",
+		"app_name":  "sshd",
+		"proc_id":   "4242",
+		"message":   "Accepted publickey",
+	}, fields)
+}
+
+func TestInvalidListenerConfig(t *testing.T) {
+	testCases := map[string]struct {
+		cfg  ListenerConfig
+		want string
+	}{
+		"InvalidProtocol": {
+			cfg:  ListenerConfig{Protocol: "sctp"},
+			want: "syslog protocol sctp is incorrect",
+		},
+		"TLSWithoutCertificate": {
+			cfg:  ListenerConfig{Protocol: ProtocolTLS, ServiceAddress: ":6514"},
+			want: "syslog listener on :6514 requires tls_cert and tls_key",
+		},
+		"InvalidFormat": {
+			cfg:  ListenerConfig{Format: "rfc3339"},
+			want: "syslog format rfc3339 is incorrect",
+		},
+		"InvalidFilter": {
+			cfg:  ListenerConfig{Filters: []*logfile.LogFilter{{Type: "drop", Expression: "x"}}},
+			want: "filter type drop is incorrect",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			plugin := &Plugin{Listeners: []ListenerConfig{testCase.cfg}}
+			err := plugin.Start(nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), testCase.want)
+		})
+	}
+}
+
+func TestReadFrame(t *testing.T) {
+	reader := bufio.NewReader(strings.NewReader("5 <13>a<13>b\n3 <1"))
+	msg, err := readFrame(reader, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, "<13>a", msg)
+	msg, err = readFrame(reader, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, "<13>b", msg)
+	_, err = readFrame(reader, 100)
+	assert.Error(t, err)
+
+	_, err = readFrame(bufio.NewReader(strings.NewReader("101 <13>a")), 100)
+	assert.EqualError(t, err, `invalid message length "101 "`)
+}
+
+func TestReadFrameTruncated(t *testing.T) {
+	long := "<13>" + strings.Repeat("a", 200) + strings.Repeat("b", 100)
+	// the buffer is smaller than the line, so it is read in several chunks
+	reader := bufio.NewReaderSize(strings.NewReader(long+"\n<13>c\n"), 16)
+	msg, err := readFrame(reader, 100)
+	assert.NoError(t, err)
+	assert.Len(t, msg, 100)
+	assert.Equal(t, "<13>"+strings.Repeat("a", 82)+truncateSuffix, msg)
+	msg, err = readFrame(reader, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, "<13>c", msg)
+}
+
+func TestSyslogTCPConnectionLimits(t *testing.T) {
+	plugin := &Plugin{
+		Listeners: []ListenerConfig{{
+			Protocol:       ProtocolTCP,
+			ServiceAddress: "127.0.0.1:0",
+			LogGroupName:   "syslog",
+			ReadTimeout:    config.Duration(200 * time.Millisecond),
+			MaxConnections: 1,
+		}},
+	}
+	startPlugin(t, plugin)
+	l := plugin.listeners[0]
+
+	first, err := net.Dial("tcp", l.addr())
+	require.NoError(t, err)
+	defer first.Close()
+	require.Eventually(t, func() bool { return connCount(l) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// connections over the limit are closed
+	second, err := net.Dial("tcp", l.addr())
+	require.NoError(t, err)
+	defer second.Close()
+	require.NoError(t, second.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = second.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+
+	// idle connections are closed after the read timeout
+	require.Eventually(t, func() bool { return connCount(l) == 0 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func connCount(l *listener) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.conns)
+}
+
+func writeCertificate(t *testing.T) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certFile, keyFile
+}
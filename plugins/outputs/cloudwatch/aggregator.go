@@ -29,6 +29,8 @@ type aggregationDatum struct {
 	cloudwatch.MetricDatum
 	aggregationInterval time.Duration
 	distribution        distribution.Distribution
+	// namespace overrides the namespace of the exporter if set.
+	namespace string
 }
 
 type Aggregator interface {
@@ -63,7 +65,7 @@ func getAggregationKey(m *aggregationDatum, unixTime int64) string {
 		tmp[i] = fmt.Sprintf("%s=%s", *d.Name, *d.Value)
 	}
 	// Assume m.Dimensions was already sorted.
-	return fmt.Sprintf("%s:%s:%v", *m.MetricName, strings.Join(tmp, ","), unixTime)
+	return fmt.Sprintf("%s:%s:%s:%v", m.namespace, *m.MetricName, strings.Join(tmp, ","), unixTime)
 }
 
 func (agg *aggregator) AddMetric(m *aggregationDatum) {
@@ -8,7 +8,9 @@ import (
 	"log"
 	"reflect"
 	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/amazon-contributing/opentelemetry-collector-contrib/extension/awsmiddleware"
@@ -30,6 +32,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/handlers"
 	"github.com/aws/amazon-cloudwatch-agent/internal/publisher"
 	"github.com/aws/amazon-cloudwatch-agent/internal/retryer"
+	"github.com/aws/amazon-cloudwatch-agent/internal/status"
 	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
 )
@@ -43,6 +46,7 @@ const (
 	maxConcurrentPublisher                = 10 // the number of CloudWatch clients send request concurrently
 	defaultForceFlushInterval             = time.Minute
 	highResolutionTagKey                  = "aws:StorageResolution"
+	namespaceAttributeKey                 = "aws.cloudwatch.namespace"
 	defaultRetryCount                     = 5 // this is the retry count, the total attempts would be retry count + 1 at most.
 	backoffRetryBase                      = 200 * time.Millisecond
 	MaxDimensions                         = 30
@@ -61,17 +65,23 @@ type CloudWatch struct {
 	// 1 telegraf Metric could have many Fields.
 	// Each field corresponds to a MetricDatum.
 	metricChan             chan *aggregationDatum
-	datumBatchChan         chan []*cloudwatch.MetricDatum
-	metricDatumBatch       *MetricDatumBatch
+	datumBatchChan         chan namespacedDatums
+	metricDatumBatches     map[string]*MetricDatumBatch
 	shutdownChan           chan struct{}
 	retries                int
 	publisher              *publisher.Publisher
+	publishQueue           *publisher.NonBlockingFifoQueue
 	retryer                *retryer.LogThrottleRetryer
 	droppingOriginMetrics  collections.Set[string]
 	aggregator             Aggregator
 	aggregatorShutdownChan chan struct{}
 	aggregatorWaitGroup    sync.WaitGroup
 	lastRequestBytes       int
+	// batchSize is the number of datums in metricDatumBatches for the status API.
+	batchSize        atomic.Int64
+	unregisterStatus func()
+	// deltaCalculator is only set if cumulative to delta is enabled.
+	deltaCalculator *deltaCalculator
 }
 
 // Compile time interface check.
@@ -82,8 +92,9 @@ func (c *CloudWatch) Capabilities() consumer.Capabilities {
 }
 
 func (c *CloudWatch) Start(_ context.Context, host component.Host) error {
+	c.publishQueue = publisher.NewNonBlockingFifoQueue(metricChanBufferSize)
 	c.publisher, _ = publisher.NewPublisher(
-		publisher.NewNonBlockingFifoQueue(metricChanBufferSize),
+		c.publishQueue,
 		maxConcurrentPublisher,
 		2*time.Second,
 		c.WriteToCloudWatch)
@@ -115,19 +126,22 @@ func (c *CloudWatch) Start(_ context.Context, host component.Host) error {
 	c.config.RollupDimensions = GetUniqueRollupList(c.config.RollupDimensions)
 	c.svc = svc
 	c.retryer = logThrottleRetryer
+	if c.config.CumulativeToDelta != nil {
+		c.deltaCalculator = newDeltaCalculator(c.config.CumulativeToDelta)
+	}
 	c.startRoutines()
+	c.unregisterStatus = status.RegisterMetricsExporter(c.reportStatus)
 	return nil
 }
 
 func (c *CloudWatch) startRoutines() {
 	setNewDistributionFunc(c.config.MaxValuesPerDatum)
 	c.metricChan = make(chan *aggregationDatum, metricChanBufferSize)
-	c.datumBatchChan = make(chan []*cloudwatch.MetricDatum, datumBatchChanBufferSize)
+	c.datumBatchChan = make(chan namespacedDatums, datumBatchChanBufferSize)
 	c.shutdownChan = make(chan struct{})
 	c.aggregatorShutdownChan = make(chan struct{})
 	c.aggregator = NewAggregator(c.metricChan, c.aggregatorShutdownChan, &c.aggregatorWaitGroup)
-	perRequestConstSize := overallConstPerRequestSize + len(c.config.Namespace) + namespaceOverheads
-	c.metricDatumBatch = newMetricDatumBatch(c.config.MaxDatumsPerCall, perRequestConstSize)
+	c.metricDatumBatches = map[string]*MetricDatumBatch{}
 	go c.pushMetricDatum()
 	go c.publish()
 }
@@ -145,6 +159,9 @@ func (c *CloudWatch) Shutdown(ctx context.Context) error {
 	if metricChanLen, datumBatchChanLen := len(c.metricChan), len(c.datumBatchChan); metricChanLen != 0 || datumBatchChanLen != 0 {
 		log.Printf("D! CloudWatch Close, metricChan length = %v, datumBatchChan length = %v.", metricChanLen, datumBatchChanLen)
 	}
+	if c.unregisterStatus != nil {
+		c.unregisterStatus()
+	}
 	close(c.shutdownChan)
 	c.publisher.Close()
 	c.retryer.Stop()
@@ -156,6 +173,9 @@ func (c *CloudWatch) Shutdown(ctx context.Context) error {
 // The actual publishing will occur in a long running goroutine.
 // This method can block when publishing is backed up.
 func (c *CloudWatch) ConsumeMetrics(ctx context.Context, metrics pmetric.Metrics) error {
+	if c.deltaCalculator != nil {
+		metrics = c.deltaCalculator.Convert(metrics)
+	}
 	datums := ConvertOtelMetrics(metrics)
 	for _, d := range datums {
 		c.aggregator.AddMetric(d)
@@ -173,29 +193,104 @@ func (c *CloudWatch) pushMetricDatum() {
 		select {
 		case metric := <-c.metricChan:
 			datums := c.BuildMetricDatum(metric)
+			namespace := c.getNamespace(metric)
+			batch := c.getMetricDatumBatch(namespace)
 			numberOfPartitions := len(datums)
 			for i := 0; i < numberOfPartitions; i++ {
-				c.metricDatumBatch.Partition = append(c.metricDatumBatch.Partition, datums[i])
-				c.metricDatumBatch.Size += payload(datums[i])
-				if c.metricDatumBatch.isFull() {
+				batch.Partition = append(batch.Partition, datums[i])
+				batch.Size += payload(datums[i])
+				if batch.isFull() {
 					// if batch is full
-					c.datumBatchChan <- c.metricDatumBatch.Partition
-					c.metricDatumBatch.clear()
+					c.datumBatchChan <- namespacedDatums{namespace: namespace, datums: batch.Partition}
+					batch.clear()
 				}
 			}
+			c.storeBatchSize()
 		case <-ticker.C:
-			if c.timeToPublish(c.metricDatumBatch) {
-				// if the time to publish comes
-				c.lastRequestBytes = c.metricDatumBatch.Size
-				c.datumBatchChan <- c.metricDatumBatch.Partition
-				c.metricDatumBatch.clear()
-			}
+			c.publishDueBatches()
+			c.storeBatchSize()
 		case <-c.shutdownChan:
 			return
 		}
 	}
 }
 
+// publishDueBatches queues the batches whose time to publish has come.
+// Namespaces can come from attributes, so the batches are not kept around
+// once they are published since they may never be used again.
+func (c *CloudWatch) publishDueBatches() {
+	for namespace, batch := range c.metricDatumBatches {
+		if len(batch.Partition) == 0 {
+			// the batch was published when it was full and the namespace
+			// has not been used since
+			delete(c.metricDatumBatches, namespace)
+		} else if c.timeToPublish(batch) {
+			c.lastRequestBytes = batch.Size
+			c.datumBatchChan <- namespacedDatums{namespace: namespace, datums: batch.Partition}
+			delete(c.metricDatumBatches, namespace)
+		}
+	}
+}
+
+// getNamespace returns the namespace from the datum's attributes if it was
+// set. Otherwise it returns the namespace mapped to the longest matching
+// metric name prefix, or the namespace of the exporter.
+func (c *CloudWatch) getNamespace(metric *aggregationDatum) string {
+	if metric.namespace != "" {
+		return metric.namespace
+	}
+	namespace := c.config.Namespace
+	longestPrefix := -1
+	for prefix, mapped := range c.config.NamespaceMappings {
+		if len(prefix) > longestPrefix && strings.HasPrefix(*metric.MetricName, prefix) {
+			longestPrefix = len(prefix)
+			namespace = mapped
+		}
+	}
+	return namespace
+}
+
+// getMetricDatumBatch returns the batch for the namespace and creates it if
+// it does not exist yet.
+func (c *CloudWatch) getMetricDatumBatch(namespace string) *MetricDatumBatch {
+	batch, ok := c.metricDatumBatches[namespace]
+	if !ok {
+		perRequestConstSize := overallConstPerRequestSize + len(namespace) + namespaceOverheads
+		batch = newMetricDatumBatch(c.config.MaxDatumsPerCall, perRequestConstSize)
+		c.metricDatumBatches[namespace] = batch
+	}
+	return batch
+}
+
+func (c *CloudWatch) storeBatchSize() {
+	var size int
+	for _, batch := range c.metricDatumBatches {
+		size += len(batch.Partition)
+	}
+	c.batchSize.Store(int64(size))
+}
+
+func (c *CloudWatch) reportStatus() status.MetricsExporter {
+	s := status.MetricsExporter{
+		Name:                "awscloudwatch",
+		BatchSize:           int(c.batchSize.Load()),
+		MetricQueueSize:     len(c.metricChan),
+		MetricQueueCapacity: cap(c.metricChan),
+		BatchQueueSize:      len(c.datumBatchChan),
+		BatchQueueCapacity:  cap(c.datumBatchChan),
+	}
+	if c.publishQueue != nil {
+		s.PublishQueueSize = c.publishQueue.Len()
+	}
+	return s
+}
+
+// namespacedDatums is the request payload of a single PutMetricData call.
+type namespacedDatums struct {
+	namespace string
+	datums    []*cloudwatch.MetricDatum
+}
+
 type MetricDatumBatch struct {
 	MaxDatumsPerCall    int
 	Partition           []*cloudwatch.MetricDatum
@@ -335,10 +430,10 @@ func (c *CloudWatch) backoffSleep() {
 }
 
 func (c *CloudWatch) WriteToCloudWatch(req interface{}) {
-	datums := req.([]*cloudwatch.MetricDatum)
+	datums := req.(namespacedDatums)
 	params := &cloudwatch.PutMetricDataInput{
-		MetricData: datums,
-		Namespace:  aws.String(c.config.Namespace),
+		MetricData: datums.datums,
+		Namespace:  aws.String(datums.namespace),
 	}
 	var err error
 	for i := 0; i < defaultRetryCount; i++ {
@@ -407,6 +407,43 @@ func makeMetrics(count int) []telegraf.Metric {
 	return metrics
 }
 
+// TestBuildMetricDatumNegativeValues aggregates negative values with the
+// distribution of the default config.
+func TestBuildMetricDatumNegativeValues(t *testing.T) {
+	defer func(newDistribution func() distribution.Distribution) {
+		distribution.NewDistribution = newDistribution
+	}(distribution.NewDistribution)
+	cfg := NewFactory().CreateDefaultConfig().(*Config)
+	setNewDistributionFunc(cfg.MaxValuesPerDatum)
+	cw := &CloudWatch{config: cfg}
+
+	dist := distribution.NewDistribution()
+	assert.NoError(t, dist.AddEntryWithUnit(-5, 1, "Count"))
+	assert.NoError(t, dist.AddEntryWithUnit(-1.5, 2, "Count"))
+	assert.NoError(t, dist.AddEntryWithUnit(3, 1, "Count"))
+	datums := cw.BuildMetricDatum(&aggregationDatum{
+		MetricDatum: cloudwatch.MetricDatum{
+			MetricName: aws.String("test"),
+			Timestamp:  aws.Time(time.Now()),
+		},
+		distribution: dist,
+	})
+	require.Len(t, datums, 1)
+	assert.Equal(t, "Count", *datums[0].Unit)
+	assert.Equal(t, -5.0, *datums[0].StatisticValues.Minimum)
+	assert.Equal(t, 3.0, *datums[0].StatisticValues.Maximum)
+	assert.Equal(t, -5.0, *datums[0].StatisticValues.Sum)
+	assert.Equal(t, 4.0, *datums[0].StatisticValues.SampleCount)
+	require.Len(t, datums[0].Values, 3)
+	var negative float64
+	for i, value := range datums[0].Values {
+		if *value < 0 {
+			negative += *datums[0].Counts[i]
+		}
+	}
+	assert.Equal(t, 3.0, negative)
+}
+
 func TestConsumeMetrics(t *testing.T) {
 	svc := new(mockCloudWatchClient)
 	res := cloudwatch.PutMetricDataOutput{}
@@ -436,6 +473,97 @@ func TestConsumeMetrics(t *testing.T) {
 	cw.Shutdown(ctx)
 }
 
+func TestGetNamespace(t *testing.T) {
+	cw := &CloudWatch{
+		config: &Config{
+			Namespace: "default",
+			NamespaceMappings: map[string]string{
+				"app_":      "App",
+				"app_http_": "AppHTTP",
+			},
+		},
+	}
+	testCases := map[string]struct {
+		metricName string
+		namespace  string
+		want       string
+	}{
+		"WithNoMatch":         {metricName: "cpu_usage", want: "default"},
+		"WithPrefix":          {metricName: "app_latency", want: "App"},
+		"WithLongestPrefix":   {metricName: "app_http_latency", want: "AppHTTP"},
+		"WithAttribute":       {metricName: "app_latency", namespace: "Custom", want: "Custom"},
+		"WithAttributeNoMaps": {metricName: "cpu_usage", namespace: "Custom", want: "Custom"},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			datum := &aggregationDatum{
+				MetricDatum: cloudwatch.MetricDatum{MetricName: aws.String(testCase.metricName)},
+				namespace:   testCase.namespace,
+			}
+			assert.Equal(t, testCase.want, cw.getNamespace(datum))
+		})
+	}
+}
+
+func TestConsumeMetricsNamespaces(t *testing.T) {
+	svc := new(mockCloudWatchClient)
+	res := cloudwatch.PutMetricDataOutput{}
+	svc.On("PutMetricData", mock.Anything).Return(&res, nil)
+	cw := newCloudWatchClient(svc, time.Second)
+	cw.config.Namespace = "default"
+	cw.config.NamespaceMappings = map[string]string{namePrefix + "1": "Mapped"}
+	cw.publisher, _ = publisher.NewPublisher(
+		publisher.NewNonBlockingFifoQueue(10),
+		10,
+		2*time.Second,
+		cw.WriteToCloudWatch)
+	pmetrics := createTestMetrics(3, 1, 1, "")
+	ms := pmetrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	ms.At(2).Gauge().DataPoints().At(0).Attributes().PutStr(namespaceAttributeKey, "Custom")
+	ctx := context.Background()
+	assert.NoError(t, cw.ConsumeMetrics(ctx, pmetrics))
+	time.Sleep(2*time.Second + 2*cw.config.ForceFlushInterval)
+
+	got := map[string][]string{}
+	for _, call := range svc.Calls {
+		input := call.Arguments.Get(0).(*cloudwatch.PutMetricDataInput)
+		for _, datum := range input.MetricData {
+			got[*input.Namespace] = append(got[*input.Namespace], *datum.MetricName)
+			for _, dimension := range datum.Dimensions {
+				assert.NotEqual(t, namespaceAttributeKey, *dimension.Name)
+			}
+		}
+	}
+	assert.Equal(t, map[string][]string{
+		"default": {namePrefix + "0"},
+		"Mapped":  {namePrefix + "1"},
+		"Custom":  {namePrefix + "2"},
+	}, got)
+	cw.Shutdown(ctx)
+}
+
+func TestPublishDueBatches(t *testing.T) {
+	cw := &CloudWatch{
+		config:             &Config{ForceFlushInterval: time.Minute, MaxDatumsPerCall: defaultMaxDatumsPerCall},
+		datumBatchChan:     make(chan namespacedDatums, 10),
+		metricDatumBatches: map[string]*MetricDatumBatch{},
+	}
+	datum := &cloudwatch.MetricDatum{MetricName: aws.String("test_metric"), Value: aws.Float64(1)}
+	// flushed when it was full and not used since
+	cw.getMetricDatumBatch("Flushed")
+	due := cw.getMetricDatumBatch("Due")
+	due.Partition = append(due.Partition, datum)
+	due.BeginTime = time.Now().Add(-time.Hour)
+	pending := cw.getMetricDatumBatch("Pending")
+	pending.Partition = append(pending.Partition, datum)
+
+	cw.publishDueBatches()
+	assert.Len(t, cw.datumBatchChan, 1)
+	assert.Equal(t, "Due", (<-cw.datumBatchChan).namespace)
+	assert.Len(t, cw.metricDatumBatches, 1)
+	assert.Contains(t, cw.metricDatumBatches, "Pending")
+}
+
 func TestWriteError(t *testing.T) {
 	svc := new(mockCloudWatchClient)
 	res := cloudwatch.PutMetricDataOutput{}
@@ -572,11 +700,11 @@ func TestBackoffRetries(t *testing.T) {
 // Take 1 item out of the channel and verify it is no longer full.
 func TestCloudWatch_metricDatumBatchFull(t *testing.T) {
 	c := &CloudWatch{
-		datumBatchChan: make(chan []*cloudwatch.MetricDatum, datumBatchChanBufferSize),
+		datumBatchChan: make(chan namespacedDatums, datumBatchChanBufferSize),
 	}
 	assert.False(t, c.metricDatumBatchFull())
 	for i := 0; i < datumBatchChanBufferSize; i++ {
-		c.datumBatchChan <- []*cloudwatch.MetricDatum{}
+		c.datumBatchChan <- namespacedDatums{}
 	}
 	assert.True(t, c.metricDatumBatchFull())
 	<-c.datumBatchChan
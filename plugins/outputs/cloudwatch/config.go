@@ -5,6 +5,7 @@ package cloudwatch
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/resourcetotelemetry"
@@ -27,6 +28,10 @@ type Config struct {
 	RollupDimensions         [][]string      `mapstructure:"rollup_dimensions,omitempty"`
 	DropOriginalConfigs      map[string]bool `mapstructure:"drop_original_metrics,omitempty"`
 	Namespace                string          `mapstructure:"namespace"`
+	// NamespaceMappings maps metric name prefixes to the namespace the
+	// metrics are published to instead of Namespace. The longest matching
+	// prefix wins. The "aws.cloudwatch.namespace" attribute takes precedence.
+	NamespaceMappings map[string]string `mapstructure:"namespace_mappings,omitempty"`
 
 	// ResourceToTelemetrySettings is the option for converting resource
 	// attributes to telemetry attributes.
@@ -35,6 +40,9 @@ type Config struct {
 	ResourceToTelemetrySettings resourcetotelemetry.Settings `mapstructure:"resource_to_telemetry_conversion"`
 	// MiddlewareID is an ID for an extension that can be used to configure the AWS client.
 	MiddlewareID *component.ID `mapstructure:"middleware,omitempty"`
+	// CumulativeToDelta enables converting monotonic cumulative sums and
+	// cumulative histograms to deltas before they are published.
+	CumulativeToDelta *CumulativeToDeltaConfig `mapstructure:"cumulative_to_delta,omitempty"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -47,8 +55,16 @@ func (c *Config) Validate() error {
 	if c.Namespace == "" {
 		return errors.New("'namespace' must be set")
 	}
+	for prefix, namespace := range c.NamespaceMappings {
+		if namespace == "" {
+			return fmt.Errorf("'namespace_mappings' has an empty namespace for prefix %q", prefix)
+		}
+	}
 	if c.ForceFlushInterval < time.Millisecond {
 		return errors.New("'force_flush_interval' must be at least 1 millisecond")
 	}
+	if c.CumulativeToDelta != nil && c.CumulativeToDelta.MaxStaleness < 0 {
+		return errors.New("'max_staleness' must not be negative")
+	}
 	return nil
 }
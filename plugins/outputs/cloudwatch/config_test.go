@@ -65,6 +65,7 @@ func TestConfig(t *testing.T) {
 	assert.Equal(t, 7, c2.MaxDatumsPerCall)
 	assert.Equal(t, 9, c2.MaxValuesPerDatum)
 	assert.Equal(t, 60*time.Second, c2.ForceFlushInterval)
+	assert.Equal(t, map[string]string{"app_": "val10"}, c2.NamespaceMappings)
 	// todo: verify MetricDecorations
 }
 
@@ -111,3 +112,24 @@ func TestConfigDropOriginConfigs(t *testing.T) {
 	assert.True(t, drop["cpu_usage"])
 	assert.True(t, drop["foo_bar"])
 }
+
+func TestConfigCumulativeToDelta(t *testing.T) {
+	factories, err := otelcoltest.NopFactories()
+	assert.NoError(t, err)
+	factory := NewFactory()
+	factories.Exporters[TypeStr] = factory
+
+	fp := filepath.Join("testdata", "cumulative_to_delta.yaml")
+	c, err := otelcoltest.LoadConfigAndValidate(fp, factories)
+	assert.NoError(t, err)
+
+	c2, ok := c.Exporters[component.NewID(TypeStr)].(*Config)
+	assert.True(t, ok)
+	assert.NotNil(t, c2.CumulativeToDelta)
+	assert.Equal(t, 10*time.Minute, c2.CumulativeToDelta.MaxStaleness)
+
+	fp = filepath.Join("testdata", "minimal.yaml")
+	c, err = otelcoltest.LoadConfigAndValidate(fp, factories)
+	assert.NoError(t, err)
+	assert.Nil(t, c.Exporters[component.NewID(TypeStr)].(*Config).CumulativeToDelta)
+}
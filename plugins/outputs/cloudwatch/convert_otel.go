@@ -69,6 +69,18 @@ func getAggregationInterval(attributes *pcommon.Map) time.Duration {
 	return interval
 }
 
+// getNamespace removes the special attribute and returns its value.
+// Return "" if it was not present.
+func getNamespace(attributes *pcommon.Map) string {
+	v, ok := attributes.Get(namespaceAttributeKey)
+	if !ok {
+		return ""
+	}
+	namespace := v.AsString()
+	attributes.Remove(namespaceAttributeKey)
+	return namespace
+}
+
 // ConvertOtelNumberDataPoints converts each datapoint in the given slice to
 // 1 or more MetricDatums and returns them.
 func ConvertOtelNumberDataPoints(
@@ -84,6 +96,7 @@ func ConvertOtelNumberDataPoints(
 		attrs := dp.Attributes()
 		storageResolution := checkHighResolution(&attrs)
 		aggregationInterval := getAggregationInterval(&attrs)
+		namespace := getNamespace(&attrs)
 		dimensions := ConvertOtelDimensions(attrs)
 		value := NumberDataPointValue(dp) * scale
 		ad := aggregationDatum{
@@ -96,6 +109,7 @@ func ConvertOtelNumberDataPoints(
 				StorageResolution: aws.Int64(storageResolution),
 			},
 			aggregationInterval: aggregationInterval,
+			namespace:           namespace,
 		}
 		datums = append(datums, &ad)
 	}
@@ -116,6 +130,7 @@ func ConvertOtelHistogramDataPoints(
 		attrs := dp.Attributes()
 		storageResolution := checkHighResolution(&attrs)
 		aggregationInterval := getAggregationInterval(&attrs)
+		namespace := getNamespace(&attrs)
 		dimensions := ConvertOtelDimensions(attrs)
 		ad := aggregationDatum{
 			MetricDatum: cloudwatch.MetricDatum{
@@ -126,6 +141,7 @@ func ConvertOtelHistogramDataPoints(
 				StorageResolution: aws.Int64(storageResolution),
 			},
 			aggregationInterval: aggregationInterval,
+			namespace:           namespace,
 		}
 		// Assume function pointer is valid.
 		ad.distribution = distribution.NewDistribution()
@@ -135,10 +151,45 @@ func ConvertOtelHistogramDataPoints(
 	return datums
 }
 
+// ConvertOtelExponentialHistogramDataPoints converts each datapoint in the
+// given slice to Distribution.
+func ConvertOtelExponentialHistogramDataPoints(
+	dataPoints pmetric.ExponentialHistogramDataPointSlice,
+	name string,
+	unit string,
+	scale float64,
+) []*aggregationDatum {
+	datums := make([]*aggregationDatum, 0, dataPoints.Len())
+	for i := 0; i < dataPoints.Len(); i++ {
+		dp := dataPoints.At(i)
+		attrs := dp.Attributes()
+		storageResolution := checkHighResolution(&attrs)
+		aggregationInterval := getAggregationInterval(&attrs)
+		namespace := getNamespace(&attrs)
+		dimensions := ConvertOtelDimensions(attrs)
+		ad := aggregationDatum{
+			MetricDatum: cloudwatch.MetricDatum{
+				Dimensions:        dimensions,
+				MetricName:        aws.String(name),
+				Unit:              aws.String(unit),
+				Timestamp:         aws.Time(dp.Timestamp().AsTime()),
+				StorageResolution: aws.Int64(storageResolution),
+			},
+			aggregationInterval: aggregationInterval,
+			namespace:           namespace,
+		}
+		// Assume function pointer is valid.
+		ad.distribution = distribution.NewDistribution()
+		ad.distribution.ConvertFromOtelExponential(dp, unit, scale)
+		datums = append(datums, &ad)
+	}
+	return datums
+}
+
 // ConvertOtelMetric creates a list of datums from the datapoints in the given
 // metric and returns it. Only supports the metric DataTypes that we plan to use.
-// Intentionally not caching previous values and converting cumulative to delta.
-// Instead use cumulativetodeltaprocessor which supports monotonic cumulative sums.
+// The datapoints are converted as they are. Cumulative metrics are converted to
+// deltas before this, when CumulativeToDelta is set in the exporter config.
 func ConvertOtelMetric(m pmetric.Metric) []*aggregationDatum {
 	name := m.Name()
 	unit, scale, err := cloudwatchutil.ToStandardUnit(m.Unit())
@@ -152,6 +203,8 @@ func ConvertOtelMetric(m pmetric.Metric) []*aggregationDatum {
 		return ConvertOtelNumberDataPoints(m.Sum().DataPoints(), name, unit, scale)
 	case pmetric.MetricTypeHistogram:
 		return ConvertOtelHistogramDataPoints(m.Histogram().DataPoints(), name, unit, scale)
+	case pmetric.MetricTypeExponentialHistogram:
+		return ConvertOtelExponentialHistogramDataPoints(m.ExponentialHistogram().DataPoints(), name, unit, scale)
 	default:
 		log.Printf("E! cloudwatch: Unsupported type, %s", m.Type())
 	}
@@ -161,17 +214,29 @@ func ConvertOtelMetric(m pmetric.Metric) []*aggregationDatum {
 // ConvertOtelMetrics only uses dimensions/attributes on each "datapoint",
 // not each "Resource".
 // This is acceptable because ResourceToTelemetrySettings defaults to true.
+// The namespace attribute of the resource is the exception, it is used even if
+// the resource attributes are not converted and takes precedence over the
+// namespace attribute of the datapoints, like the conversion does.
 func ConvertOtelMetrics(m pmetric.Metrics) []*aggregationDatum {
 	datums := make([]*aggregationDatum, 0, m.DataPointCount())
 	// Metrics -> ResourceMetrics -> ScopeMetrics -> MetricSlice -> DataPoints
 	resourceMetrics := m.ResourceMetrics()
 	for i := 0; i < resourceMetrics.Len(); i++ {
+		var resourceNamespace string
+		if v, ok := resourceMetrics.At(i).Resource().Attributes().Get(namespaceAttributeKey); ok {
+			resourceNamespace = v.AsString()
+		}
 		scopeMetrics := resourceMetrics.At(i).ScopeMetrics()
 		for j := 0; j < scopeMetrics.Len(); j++ {
 			metrics := scopeMetrics.At(j).Metrics()
 			for k := 0; k < metrics.Len(); k++ {
 				metric := metrics.At(k)
 				newDatums := ConvertOtelMetric(metric)
+				if resourceNamespace != "" {
+					for _, datum := range newDatums {
+						datum.namespace = resourceNamespace
+					}
+				}
 				datums = append(datums, newDatums...)
 			}
 		}
@@ -10,11 +10,13 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/regular"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/seh1"
 )
 
 const (
@@ -190,6 +192,62 @@ func TestConvertOtelMetrics_Histogram(t *testing.T) {
 	}
 }
 
+func TestConvertOtelMetrics_ExponentialHistogram(t *testing.T) {
+	defer func(newDistribution func() distribution.Distribution) {
+		distribution.NewDistribution = newDistribution
+	}(distribution.NewDistribution)
+	units := map[string]struct {
+		unit  string
+		scale float64
+	}{
+		"ms":  {unit: "Milliseconds", scale: 1},
+		"min": {unit: "Seconds", scale: 60},
+	}
+	for name, newDistribution := range map[string]func() distribution.Distribution{
+		"regular": regular.NewRegularDistribution,
+		"seh1":    seh1.NewSEH1Distribution,
+	} {
+		for otelUnit, want := range units {
+			t.Run(name+"/"+otelUnit, func(t *testing.T) {
+				distribution.NewDistribution = newDistribution
+				metrics := pmetric.NewMetrics()
+				m := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+				m.SetName(namePrefix + "0")
+				m.SetUnit(otelUnit)
+				dp := m.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+				dp.SetScale(0)
+				dp.SetCount(histogramCount)
+				dp.SetSum(histogramSum)
+				dp.SetMin(histogramMin)
+				dp.SetMax(histogramMax)
+				dp.Positive().SetOffset(1)
+				dp.Positive().BucketCounts().FromRaw([]uint64{1, 2})
+				dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+				dp.Attributes().PutStr(keyPrefix+"0", valPrefix+"0")
+
+				datums := ConvertOtelMetrics(metrics)
+				require.Len(t, datums, 1)
+				d := datums[0]
+				assert.Equal(t, want.unit, *d.Unit)
+				assert.Len(t, d.Dimensions, 1)
+				require.NotNil(t, d.distribution)
+				assert.Equal(t, float64(histogramMax)*want.scale, d.distribution.Maximum())
+				assert.Equal(t, float64(histogramMin)*want.scale, d.distribution.Minimum())
+				assert.Equal(t, float64(histogramSum)*want.scale, d.distribution.Sum())
+				assert.Equal(t, float64(histogramCount), d.distribution.SampleCount())
+				values, counts := d.distribution.ValuesAndCounts()
+				assert.Len(t, values, 2)
+				assert.ElementsMatch(t, []float64{1, 2}, counts)
+				// the values are in the middle of the buckets (2, 4] and (4, 8]
+				for _, value := range values {
+					assert.Greater(t, value, 2*want.scale)
+					assert.Less(t, value, 8*want.scale)
+				}
+			})
+		}
+	}
+}
+
 func TestConvertOtelMetrics_Dimensions(t *testing.T) {
 	for i := 0; i < 100; i++ {
 		// 1 data point per metric, but vary the number dimensions.
@@ -216,3 +274,21 @@ func TestInvalidMetric(t *testing.T) {
 	m.SetUnit("unit")
 	assert.Empty(t, ConvertOtelMetric(m))
 }
+
+func TestConvertOtelMetrics_ResourceNamespace(t *testing.T) {
+	metrics := createTestMetrics(2, 1, 0, "")
+	metrics.ResourceMetrics().At(0).Resource().Attributes().PutStr(namespaceAttributeKey, "Resource")
+	ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	ms.At(1).Sum().DataPoints().At(0).Attributes().PutStr(namespaceAttributeKey, "DataPoint")
+	other := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	other.SetName("other")
+	other.SetEmptyGauge().DataPoints().AppendEmpty().Attributes().PutStr(namespaceAttributeKey, "DataPoint")
+
+	datums := ConvertOtelMetrics(metrics)
+	require.Len(t, datums, 3)
+	// the resource attribute takes precedence over the datapoint attribute
+	assert.Equal(t, "Resource", datums[0].namespace)
+	assert.Equal(t, "Resource", datums[1].namespace)
+	assert.Empty(t, datums[1].Dimensions)
+	assert.Equal(t, "DataPoint", datums[2].namespace)
+}
@@ -0,0 +1,366 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"golang.org/x/exp/maps"
+)
+
+const defaultMaxStaleness = 5 * time.Minute
+
+// CumulativeToDeltaConfig enables converting cumulative metrics to deltas in
+// the exporter.
+type CumulativeToDeltaConfig struct {
+	// MaxStaleness is how long a series is remembered after its last data
+	// point. Defaults to 5 minutes.
+	MaxStaleness time.Duration `mapstructure:"max_staleness,omitempty"`
+}
+
+// seriesState is the last cumulative data point seen for a series.
+type seriesState struct {
+	startTime pcommon.Timestamp
+	timestamp pcommon.Timestamp
+	lastSeen  time.Time
+
+	value float64
+	// intValue is the value of an int data point, which is kept separately
+	// since a float64 cannot hold every int64.
+	intValue int64
+
+	count        uint64
+	sum          float64
+	zeroCount    uint64
+	bounds       []float64
+	bucketCounts []uint64
+	scale        int32
+	positive     exponentialBuckets
+	negative     exponentialBuckets
+}
+
+type exponentialBuckets struct {
+	offset int32
+	counts []uint64
+}
+
+// deltaCalculator converts monotonic cumulative sums and cumulative
+// histograms to deltas by remembering the previous data point of each series.
+// The first data point of a series is dropped unless it started after the
+// calculator did. A decrease or a new start time is treated as a reset, in
+// which case the data point is passed through as the delta since the reset.
+type deltaCalculator struct {
+	mu           sync.Mutex
+	series       map[string]*seriesState
+	startTime    pcommon.Timestamp
+	maxStaleness time.Duration
+	lastExpiry   time.Time
+	now          func() time.Time
+}
+
+func newDeltaCalculator(cfg *CumulativeToDeltaConfig) *deltaCalculator {
+	maxStaleness := cfg.MaxStaleness
+	if maxStaleness <= 0 {
+		maxStaleness = defaultMaxStaleness
+	}
+	return &deltaCalculator{
+		series:       map[string]*seriesState{},
+		startTime:    pcommon.NewTimestampFromTime(time.Now()),
+		maxStaleness: maxStaleness,
+		now:          time.Now,
+	}
+}
+
+// Convert returns the metrics with the cumulative data points replaced by
+// deltas. The input is not modified.
+func (dc *deltaCalculator) Convert(metrics pmetric.Metrics) pmetric.Metrics {
+	converted := pmetric.NewMetrics()
+	metrics.CopyTo(converted)
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	now := dc.now()
+	rms := converted.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		sms := rms.At(i).ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			sms.At(j).Metrics().RemoveIf(func(m pmetric.Metric) bool {
+				return dc.convertMetric(m, now)
+			})
+		}
+	}
+	dc.expire(now)
+	return converted
+}
+
+// convertMetric converts the data points of the metric in place and returns
+// true if no data points are left.
+func (dc *deltaCalculator) convertMetric(m pmetric.Metric, now time.Time) bool {
+	switch m.Type() {
+	case pmetric.MetricTypeSum:
+		sum := m.Sum()
+		if !sum.IsMonotonic() || sum.AggregationTemporality() != pmetric.AggregationTemporalityCumulative {
+			return false
+		}
+		sum.DataPoints().RemoveIf(func(dp pmetric.NumberDataPoint) bool {
+			return !dc.convertNumber(seriesKey(m, dp.Attributes()), dp, now)
+		})
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		return sum.DataPoints().Len() == 0
+	case pmetric.MetricTypeHistogram:
+		histogram := m.Histogram()
+		if histogram.AggregationTemporality() != pmetric.AggregationTemporalityCumulative {
+			return false
+		}
+		histogram.DataPoints().RemoveIf(func(dp pmetric.HistogramDataPoint) bool {
+			return !dc.convertHistogram(seriesKey(m, dp.Attributes()), dp, now)
+		})
+		histogram.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		return histogram.DataPoints().Len() == 0
+	case pmetric.MetricTypeExponentialHistogram:
+		histogram := m.ExponentialHistogram()
+		if histogram.AggregationTemporality() != pmetric.AggregationTemporalityCumulative {
+			return false
+		}
+		histogram.DataPoints().RemoveIf(func(dp pmetric.ExponentialHistogramDataPoint) bool {
+			return !dc.convertExponentialHistogram(seriesKey(m, dp.Attributes()), dp, now)
+		})
+		histogram.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		return histogram.DataPoints().Len() == 0
+	}
+	return false
+}
+
+// track stores the state for the series and returns the previous state. If
+// the returned bool is false, the data point should be kept as is. If the
+// returned state is nil, the data point should be dropped.
+func (dc *deltaCalculator) track(key string, state *seriesState, isReset func(prev *seriesState) bool) (*seriesState, bool) {
+	prev, ok := dc.series[key]
+	dc.series[key] = state
+	if !ok {
+		// Points that started after the calculator already are deltas.
+		if state.startTime != 0 && state.startTime >= dc.startTime {
+			return nil, false
+		}
+		return nil, true
+	}
+	if (state.startTime != 0 && state.startTime != prev.startTime) || isReset(prev) {
+		return nil, false
+	}
+	return prev, true
+}
+
+func (dc *deltaCalculator) convertNumber(key string, dp pmetric.NumberDataPoint, now time.Time) bool {
+	state := &seriesState{startTime: dp.StartTimestamp(), timestamp: dp.Timestamp(), lastSeen: now}
+	var isReset func(prev *seriesState) bool
+	switch dp.ValueType() {
+	case pmetric.NumberDataPointValueTypeInt:
+		state.intValue = dp.IntValue()
+		isReset = func(prev *seriesState) bool {
+			return state.intValue < prev.intValue
+		}
+	case pmetric.NumberDataPointValueTypeDouble:
+		state.value = dp.DoubleValue()
+		isReset = func(prev *seriesState) bool {
+			return state.value < prev.value
+		}
+	default:
+		return false
+	}
+	prev, convert := dc.track(key, state, isReset)
+	if !convert {
+		return true
+	}
+	if prev == nil {
+		return false
+	}
+	if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		dp.SetIntValue(state.intValue - prev.intValue)
+	} else {
+		dp.SetDoubleValue(state.value - prev.value)
+	}
+	dp.SetStartTimestamp(prev.timestamp)
+	return true
+}
+
+func (dc *deltaCalculator) convertHistogram(key string, dp pmetric.HistogramDataPoint, now time.Time) bool {
+	state := &seriesState{
+		startTime:    dp.StartTimestamp(),
+		timestamp:    dp.Timestamp(),
+		lastSeen:     now,
+		count:        dp.Count(),
+		sum:          dp.Sum(),
+		bounds:       dp.ExplicitBounds().AsRaw(),
+		bucketCounts: dp.BucketCounts().AsRaw(),
+	}
+	prev, convert := dc.track(key, state, func(prev *seriesState) bool {
+		return state.count < prev.count || !boundsEqual(prev.bounds, state.bounds) ||
+			!countsIncreased(prev.bucketCounts, state.bucketCounts)
+	})
+	if !convert {
+		return true
+	}
+	if prev == nil {
+		return false
+	}
+	dp.SetCount(state.count - prev.count)
+	dp.SetSum(state.sum - prev.sum)
+	dp.BucketCounts().FromRaw(subtractCounts(state.bucketCounts, prev.bucketCounts))
+	dp.SetStartTimestamp(prev.timestamp)
+	return true
+}
+
+func (dc *deltaCalculator) convertExponentialHistogram(key string, dp pmetric.ExponentialHistogramDataPoint, now time.Time) bool {
+	state := &seriesState{
+		startTime: dp.StartTimestamp(),
+		timestamp: dp.Timestamp(),
+		lastSeen:  now,
+		count:     dp.Count(),
+		sum:       dp.Sum(),
+		zeroCount: dp.ZeroCount(),
+		scale:     dp.Scale(),
+		positive:  exponentialBuckets{offset: dp.Positive().Offset(), counts: dp.Positive().BucketCounts().AsRaw()},
+		negative:  exponentialBuckets{offset: dp.Negative().Offset(), counts: dp.Negative().BucketCounts().AsRaw()},
+	}
+	// The buckets of both data points are compared at the lower of their
+	// scales, since the scale of a series goes down as its range grows.
+	aligned := func(prev *seriesState) (scale int32, positive, negative, prevPositive, prevNegative exponentialBuckets) {
+		scale = min(state.scale, prev.scale)
+		return scale, state.positive.downscale(state.scale - scale), state.negative.downscale(state.scale - scale),
+			prev.positive.downscale(prev.scale - scale), prev.negative.downscale(prev.scale - scale)
+	}
+	prev, convert := dc.track(key, state, func(prev *seriesState) bool {
+		if state.count < prev.count || state.zeroCount < prev.zeroCount {
+			return true
+		}
+		_, positive, negative, prevPositive, prevNegative := aligned(prev)
+		return !prevPositive.within(positive) || !prevNegative.within(negative)
+	})
+	if !convert {
+		return true
+	}
+	if prev == nil {
+		return false
+	}
+	scale, positive, negative, prevPositive, prevNegative := aligned(prev)
+	dp.SetCount(state.count - prev.count)
+	dp.SetSum(state.sum - prev.sum)
+	dp.SetZeroCount(state.zeroCount - prev.zeroCount)
+	dp.SetScale(scale)
+	dp.Positive().SetOffset(positive.offset)
+	dp.Positive().BucketCounts().FromRaw(positive.subtract(prevPositive))
+	dp.Negative().SetOffset(negative.offset)
+	dp.Negative().BucketCounts().FromRaw(negative.subtract(prevNegative))
+	// The min and max of a cumulative histogram cover its whole lifetime,
+	// so let the distribution estimate them from the buckets instead.
+	dp.RemoveMin()
+	dp.RemoveMax()
+	dp.SetStartTimestamp(prev.timestamp)
+	return true
+}
+
+// expire removes the series that have not been seen within the max staleness.
+// It runs at most once per max staleness.
+func (dc *deltaCalculator) expire(now time.Time) {
+	if now.Sub(dc.lastExpiry) < dc.maxStaleness {
+		return
+	}
+	dc.lastExpiry = now
+	for key, state := range dc.series {
+		if now.Sub(state.lastSeen) > dc.maxStaleness {
+			delete(dc.series, key)
+		}
+	}
+}
+
+// within returns true if each bucket of b is also in other with at least the
+// same count.
+func (b exponentialBuckets) within(other exponentialBuckets) bool {
+	for i, count := range b.counts {
+		if count == 0 {
+			continue
+		}
+		j := int(b.offset) + i - int(other.offset)
+		if j < 0 || j >= len(other.counts) || other.counts[j] < count {
+			return false
+		}
+	}
+	return true
+}
+
+// downscale returns the buckets at a scale lower by the given number, where
+// each bucket merges the 2^by buckets of b with the same index shifted right.
+func (b exponentialBuckets) downscale(by int32) exponentialBuckets {
+	if by <= 0 || len(b.counts) == 0 {
+		return b
+	}
+	offset := b.offset >> by
+	last := (b.offset + int32(len(b.counts)) - 1) >> by
+	counts := make([]uint64, last-offset+1)
+	for i, count := range b.counts {
+		counts[(b.offset+int32(i))>>by-offset] += count
+	}
+	return exponentialBuckets{offset: offset, counts: counts}
+}
+
+// subtract returns the counts of b minus the counts of prev, which must be
+// within b.
+func (b exponentialBuckets) subtract(prev exponentialBuckets) []uint64 {
+	counts := append([]uint64(nil), b.counts...)
+	for i, count := range prev.counts {
+		if j := int(prev.offset) + i - int(b.offset); j >= 0 && j < len(counts) {
+			counts[j] -= count
+		}
+	}
+	return counts
+}
+
+func boundsEqual(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func countsIncreased(prev, cur []uint64) bool {
+	if len(prev) != len(cur) {
+		return false
+	}
+	for i := range prev {
+		if cur[i] < prev[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func subtractCounts(cur, prev []uint64) []uint64 {
+	counts := make([]uint64, len(cur))
+	for i := range cur {
+		counts[i] = cur[i] - prev[i]
+	}
+	return counts
+}
+
+// seriesKey identifies a series by the metric name, type and attributes.
+func seriesKey(m pmetric.Metric, attrs pcommon.Map) string {
+	raw := attrs.AsRaw()
+	keys := maps.Keys(raw)
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s=%v", k, raw[k])
+	}
+	return fmt.Sprintf("%s:%s:%s", m.Name(), m.Type(), strings.Join(pairs, ","))
+}
@@ -0,0 +1,230 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func newCumulativeSum(name string, start pcommon.Timestamp, value float64) pmetric.Metrics {
+	metrics := pmetric.NewMetrics()
+	m := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName(name)
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp := sum.DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(start)
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	dp.SetDoubleValue(value)
+	dp.Attributes().PutStr("host", "a")
+	return metrics
+}
+
+func sumValues(metrics pmetric.Metrics) []float64 {
+	var values []float64
+	ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		dps := ms.At(i).Sum().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			values = append(values, dps.At(j).DoubleValue())
+		}
+	}
+	return values
+}
+
+func TestDeltaCalculatorSum(t *testing.T) {
+	dc := newDeltaCalculator(&CumulativeToDeltaConfig{})
+	oldStart := pcommon.NewTimestampFromTime(time.Now().Add(-time.Hour))
+
+	// The test cases depend on the previous ones, so they run in order.
+	testCases := []struct {
+		name  string
+		start pcommon.Timestamp
+		value float64
+		want  []float64
+	}{
+		{name: "FirstPointDropped", start: oldStart, value: 10},
+		{name: "Delta", start: oldStart, value: 15, want: []float64{5}},
+		{name: "Unchanged", start: oldStart, value: 15, want: []float64{0}},
+		{name: "ResetOnDecrease", start: oldStart, value: 3, want: []float64{3}},
+		{name: "DeltaAfterReset", start: oldStart, value: 7, want: []float64{4}},
+		{name: "ResetOnStartChange", start: oldStart + 1, value: 20, want: []float64{20}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			input := newCumulativeSum("counter", testCase.start, testCase.value)
+			got := dc.Convert(input)
+			assert.Equal(t, testCase.want, sumValues(got))
+			// input is not modified
+			assert.Equal(t, []float64{testCase.value}, sumValues(input))
+			if len(testCase.want) > 0 {
+				assert.Equal(t, pmetric.AggregationTemporalityDelta, got.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().AggregationTemporality())
+			}
+		})
+	}
+}
+
+func TestDeltaCalculatorPassThrough(t *testing.T) {
+	dc := newDeltaCalculator(&CumulativeToDeltaConfig{})
+
+	// started after the calculator, so the first point is already a delta
+	got := dc.Convert(newCumulativeSum("new", pcommon.NewTimestampFromTime(time.Now().Add(time.Second)), 4))
+	assert.Equal(t, []float64{4}, sumValues(got))
+
+	// non-monotonic sums are left alone
+	metrics := newCumulativeSum("gauge_like", 0, 4)
+	metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().SetIsMonotonic(false)
+	got = dc.Convert(metrics)
+	assert.Equal(t, []float64{4}, sumValues(got))
+	got = dc.Convert(metrics)
+	assert.Equal(t, []float64{4}, sumValues(got))
+}
+
+func TestDeltaCalculatorHistogram(t *testing.T) {
+	dc := newDeltaCalculator(&CumulativeToDeltaConfig{})
+	newHistogram := func(count uint64, sum float64, counts []uint64) pmetric.Metrics {
+		metrics := pmetric.NewMetrics()
+		m := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("histogram")
+		h := m.SetEmptyHistogram()
+		h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		dp := h.DataPoints().AppendEmpty()
+		dp.SetCount(count)
+		dp.SetSum(sum)
+		dp.SetMin(1)
+		dp.SetMax(5)
+		dp.ExplicitBounds().FromRaw([]float64{1, 5})
+		dp.BucketCounts().FromRaw(counts)
+		return metrics
+	}
+
+	got := dc.Convert(newHistogram(3, 10, []uint64{1, 2}))
+	assert.Equal(t, 0, got.MetricCount())
+
+	got = dc.Convert(newHistogram(7, 25, []uint64{2, 5}))
+	require.Equal(t, 1, got.MetricCount())
+	dp := got.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints().At(0)
+	assert.EqualValues(t, 4, dp.Count())
+	assert.EqualValues(t, 15, dp.Sum())
+	assert.Equal(t, []uint64{1, 3}, dp.BucketCounts().AsRaw())
+
+	// reset
+	got = dc.Convert(newHistogram(2, 3, []uint64{1, 1}))
+	dp = got.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Histogram().DataPoints().At(0)
+	assert.EqualValues(t, 2, dp.Count())
+	assert.Equal(t, []uint64{1, 1}, dp.BucketCounts().AsRaw())
+}
+
+func TestDeltaCalculatorExponentialHistogram(t *testing.T) {
+	dc := newDeltaCalculator(&CumulativeToDeltaConfig{})
+	newHistogram := func(count uint64, offset int32, counts []uint64) pmetric.Metrics {
+		metrics := pmetric.NewMetrics()
+		m := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("exponential")
+		h := m.SetEmptyExponentialHistogram()
+		h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		dp := h.DataPoints().AppendEmpty()
+		dp.SetCount(count)
+		dp.SetSum(float64(count))
+		dp.SetMax(100)
+		dp.Positive().SetOffset(offset)
+		dp.Positive().BucketCounts().FromRaw(counts)
+		return metrics
+	}
+
+	dc.Convert(newHistogram(3, 2, []uint64{1, 2}))
+	// the bucket range grew downwards
+	got := dc.Convert(newHistogram(6, 1, []uint64{1, 2, 3}))
+	require.Equal(t, 1, got.MetricCount())
+	dp := got.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).ExponentialHistogram().DataPoints().At(0)
+	assert.EqualValues(t, 3, dp.Count())
+	assert.EqualValues(t, 1, dp.Positive().Offset())
+	assert.Equal(t, []uint64{1, 1, 1}, dp.Positive().BucketCounts().AsRaw())
+	assert.False(t, dp.HasMax())
+}
+
+func TestDeltaCalculatorExponentialHistogramScale(t *testing.T) {
+	dc := newDeltaCalculator(&CumulativeToDeltaConfig{})
+	newHistogram := func(count uint64, scale, offset int32, counts []uint64) pmetric.Metrics {
+		metrics := pmetric.NewMetrics()
+		m := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("exponential")
+		h := m.SetEmptyExponentialHistogram()
+		h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		dp := h.DataPoints().AppendEmpty()
+		dp.SetCount(count)
+		dp.SetSum(float64(count))
+		dp.SetScale(scale)
+		dp.Positive().SetOffset(offset)
+		dp.Positive().BucketCounts().FromRaw(counts)
+		// a single negative value in bucket -3 at scale 2
+		dp.Negative().SetOffset(-3 >> (2 - scale))
+		dp.Negative().BucketCounts().FromRaw([]uint64{1})
+		return metrics
+	}
+	exponentialDataPoint := func(metrics pmetric.Metrics) pmetric.ExponentialHistogramDataPoint {
+		require.Equal(t, 1, metrics.MetricCount())
+		return metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).ExponentialHistogram().DataPoints().At(0)
+	}
+
+	// buckets 3, 4, 5 and 6 at scale 2
+	dc.Convert(newHistogram(5, 2, 3, []uint64{1, 1, 1, 1}))
+	// the scale went down by one, so buckets 3 | 4, 5 | 6, 7 are merged into 1, 2, 3
+	dp := exponentialDataPoint(dc.Convert(newHistogram(8, 1, 1, []uint64{2, 3, 2})))
+	assert.EqualValues(t, 3, dp.Count())
+	assert.EqualValues(t, 1, dp.Scale())
+	assert.EqualValues(t, 1, dp.Positive().Offset())
+	assert.Equal(t, []uint64{1, 1, 1}, dp.Positive().BucketCounts().AsRaw())
+	assert.EqualValues(t, -2, dp.Negative().Offset())
+	assert.Equal(t, []uint64{0}, dp.Negative().BucketCounts().AsRaw())
+
+	// a count drop is a reset
+	dp = exponentialDataPoint(dc.Convert(newHistogram(2, 1, 1, []uint64{1})))
+	assert.EqualValues(t, 2, dp.Count())
+	assert.Equal(t, []uint64{1}, dp.Positive().BucketCounts().AsRaw())
+}
+
+func TestDeltaCalculatorIntSum(t *testing.T) {
+	dc := newDeltaCalculator(&CumulativeToDeltaConfig{})
+	newIntSum := func(value int64) pmetric.Metrics {
+		metrics := newCumulativeSum("int", 0, 0)
+		metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0).SetIntValue(value)
+		return metrics
+	}
+
+	// the values are above 2^53, where a float64 cannot hold every int64
+	dc.Convert(newIntSum(1<<60 + 1))
+	got := dc.Convert(newIntSum(1<<60 + 4))
+	require.Equal(t, 1, got.MetricCount())
+	assert.EqualValues(t, 3, got.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0).IntValue())
+	// a decrease of one is a reset
+	got = dc.Convert(newIntSum(1<<60 + 3))
+	assert.EqualValues(t, 1<<60+3, got.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum().DataPoints().At(0).IntValue())
+}
+
+func TestDeltaCalculatorExpire(t *testing.T) {
+	dc := newDeltaCalculator(&CumulativeToDeltaConfig{MaxStaleness: time.Minute})
+	now := time.Now()
+	dc.now = func() time.Time { return now }
+
+	dc.Convert(newCumulativeSum("a", 0, 1))
+	dc.Convert(newCumulativeSum("b", 0, 1))
+	assert.Len(t, dc.series, 2)
+
+	now = now.Add(45 * time.Second)
+	dc.Convert(newCumulativeSum("b", 0, 2))
+	now = now.Add(45 * time.Second)
+	dc.Convert(newCumulativeSum("b", 0, 3))
+	assert.Len(t, dc.series, 1)
+	for key := range dc.series {
+		assert.Equal(t, "b:Sum:host=a", key)
+	}
+}
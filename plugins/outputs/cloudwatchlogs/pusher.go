@@ -7,6 +7,7 @@ import (
 	"math/rand"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -14,6 +15,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/influxdata/telegraf"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/status"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/profiler"
 )
@@ -61,6 +63,14 @@ type pusher struct {
 	initNonBlockingChOnce sync.Once
 	startNonBlockCh       chan struct{}
 	wg                    *sync.WaitGroup
+
+	// The fields below are reported by the status API.
+	hasNonBlockingCh atomic.Bool
+	batchSize        atomic.Int64
+	statusMu         sync.Mutex
+	lastSendTime     time.Time
+	lastError        error
+	lastErrorTime    time.Time
 }
 
 func NewPusher(target Target, service CloudWatchLogsService, flushTimeout time.Duration, retryDuration time.Duration, logger telegraf.Logger, stop <-chan struct{}, wg *sync.WaitGroup) *pusher {
@@ -79,7 +89,11 @@ func NewPusher(target Target, service CloudWatchLogsService, flushTimeout time.D
 	}
 	p.putRetentionPolicy()
 	p.wg.Add(1)
-	go p.start()
+	unregister := status.RegisterLogPusher(p.reportStatus)
+	go func() {
+		defer unregister()
+		p.start()
+	}()
 	return p
 }
 
@@ -99,6 +113,7 @@ func (p *pusher) AddEventNonBlocking(e logs.LogEvent) {
 
 	p.initNonBlockingChOnce.Do(func() {
 		p.nonBlockingEventsCh = make(chan logs.LogEvent, reqEventsLimit*2)
+		p.hasNonBlockingCh.Store(true)
 		p.startNonBlockCh <- struct{}{} // Unblock the select loop to recogonize the channel merge
 	})
 
@@ -110,6 +125,7 @@ func (p *pusher) AddEventNonBlocking(e logs.LogEvent) {
 		default:
 			<-p.nonBlockingEventsCh
 			p.addStats("emfMetricDrop", 1)
+			status.AddDropped("emfMetricDrop", 1)
 		}
 	}
 }
@@ -182,6 +198,7 @@ func (p *pusher) start() {
 			if p.maxT == nil || p.maxT.Before(et) {
 				p.maxT = &et
 			}
+			p.batchSize.Store(int64(len(p.events)))
 
 		case <-p.flushTimer.C:
 			if time.Since(p.lastSentTime) >= p.FlushTimeout && len(p.events) > 0 {
@@ -211,6 +228,7 @@ func (p *pusher) reset() {
 	p.needSort = false
 	p.minT = nil
 	p.maxT = nil
+	p.batchSize.Store(0)
 }
 
 func (p *pusher) send() {
@@ -255,12 +273,15 @@ func (p *pusher) send() {
 
 			p.Log.Debugf("Pusher published %v log events to group: %v stream: %v with size %v KB in %v.", len(p.events), p.Group, p.Stream, p.bufferredSize/1024, time.Since(startTime))
 			p.addStats("rawSize", float64(p.bufferredSize))
+			p.addStats("events", float64(len(p.events)))
 
 			p.reset()
 			p.lastSentTime = time.Now()
+			p.setLastSend(p.lastSentTime)
 
 			return
 		}
+		p.setLastError(err)
 
 		awsErr, ok := err.(awserr.Error)
 		if !ok {
@@ -305,6 +326,7 @@ func (p *pusher) send() {
 		}
 
 		p.Log.Warnf("Retried %v time, going to sleep %v before retrying.", retryCount, wait)
+		p.addStats("retries", 1)
 
 		select {
 		case <-p.stop:
@@ -407,6 +429,43 @@ func (p *pusher) putRetentionPolicy() {
 	}
 }
 
+func (p *pusher) setLastSend(t time.Time) {
+	p.statusMu.Lock()
+	defer p.statusMu.Unlock()
+	p.lastSendTime = t
+}
+
+func (p *pusher) setLastError(err error) {
+	p.statusMu.Lock()
+	defer p.statusMu.Unlock()
+	p.lastError = err
+	p.lastErrorTime = time.Now()
+}
+
+func (p *pusher) reportStatus() status.LogPusher {
+	s := status.LogPusher{
+		Group:      p.Group,
+		Stream:     p.Stream,
+		QueueDepth: len(p.eventsCh),
+		BatchSize:  int(p.batchSize.Load()),
+	}
+	if p.hasNonBlockingCh.Load() {
+		s.QueueDepth += len(p.nonBlockingEventsCh)
+	}
+	p.statusMu.Lock()
+	defer p.statusMu.Unlock()
+	if !p.lastSendTime.IsZero() {
+		lastSendTime := p.lastSendTime
+		s.LastSendTime = &lastSendTime
+	}
+	if p.lastError != nil {
+		lastErrorTime := p.lastErrorTime
+		s.LastError = p.lastError.Error()
+		s.LastErrorTime = &lastErrorTime
+	}
+	return s
+}
+
 func (p *pusher) resetFlushTimer() {
 	p.flushTimer.Stop()
 	p.flushTimer.Reset(p.FlushTimeout)
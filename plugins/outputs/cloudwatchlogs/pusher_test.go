@@ -763,6 +763,42 @@ func TestResendWouldStopAfterExhaustedRetries(t *testing.T) {
 	wg.Wait()
 }
 
+func TestPusherStatus(t *testing.T) {
+	var s svcMock
+	cnt := 0
+	s.ple = func(in *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+		cnt++
+		if cnt == 1 {
+			return nil, &cloudwatchlogs.ServiceUnavailableException{Message_: aws.String("unavailable")}
+		}
+		return &cloudwatchlogs.PutLogEventsOutput{}, nil
+	}
+
+	stop, p := testPreparation(-1, &s, 1*time.Hour, maxRetryTimeout)
+	p.AddEvent(evtMock{"msg", time.Now(), nil})
+	time.Sleep(100 * time.Millisecond)
+
+	report := p.reportStatus()
+	require.Equal(t, "G", report.Group)
+	require.Equal(t, "S", report.Stream)
+	require.Equal(t, 1, report.BatchSize)
+	require.Nil(t, report.LastSendTime)
+	require.Empty(t, report.LastError)
+
+	p.FlushTimeout = 10 * time.Millisecond
+	p.resetFlushTimer()
+	time.Sleep(2 * time.Second)
+
+	report = p.reportStatus()
+	require.Equal(t, 0, report.BatchSize)
+	require.NotNil(t, report.LastSendTime)
+	require.Contains(t, report.LastError, "unavailable")
+	require.NotNil(t, report.LastErrorTime)
+
+	close(stop)
+	wg.Wait()
+}
+
 func testPreparation(retention int, s *svcMock, flushTimeout time.Duration, retryDuration time.Duration) (chan struct{}, *pusher) {
 	stop := make(chan struct{})
 	p := NewPusher(Target{"G", "S", util.StandardLogGroupClass, retention}, s, flushTimeout, retryDuration, models.NewLogger("cloudwatchlogs", "test", ""), stop, &wg)
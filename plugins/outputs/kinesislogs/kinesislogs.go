@@ -0,0 +1,235 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesislogs
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/outputs"
+
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/agent"
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+const (
+	defaultFlushTimeout = 5 * time.Second
+	maxRetryTimeout     = 14*24*time.Hour + 10*time.Minute
+	// maxPartitionKeyLength is the max number of characters of a partition key.
+	maxPartitionKeyLength = 256
+)
+
+// KinesisLogs puts the log events to a Kinesis Data Firehose delivery stream or
+// a Kinesis data stream, instead of sending them to CloudWatch Logs.
+type KinesisLogs struct {
+	Region           string `toml:"region"`
+	RegionType       string `toml:"region_type"`
+	Mode             string `toml:"mode"`
+	EndpointOverride string `toml:"endpoint_override"`
+	AccessKey        string `toml:"access_key"`
+	SecretKey        string `toml:"secret_key"`
+	RoleARN          string `toml:"role_arn"`
+	Profile          string `toml:"profile"`
+	Filename         string `toml:"shared_credential_file"`
+	Token            string `toml:"token"`
+
+	//The service of the stream, firehose or kinesis.
+	Service string `toml:"service"`
+	//The name of the delivery stream or the data stream.
+	StreamName string `toml:"stream_name"`
+	//Put as many log events as fit in each record.
+	Aggregate bool `toml:"aggregate"`
+
+	ForceFlushInterval internal.Duration `toml:"force_flush_interval"` // unit is second
+
+	Log telegraf.Logger `toml:"-"`
+
+	pusherStopChan  chan struct{}
+	pusherWaitGroup sync.WaitGroup
+	dests           map[Target]*kinesisDest
+	destsMu         sync.Mutex
+
+	putterOnce sync.Once
+	putter     putter
+	putterErr  error
+}
+
+type Target struct {
+	Group, Stream string
+}
+
+var _ logs.LogBackend = (*KinesisLogs)(nil)
+
+func (k *KinesisLogs) Connect() error {
+	_, err := k.getPutter()
+	return err
+}
+
+func (k *KinesisLogs) Close() error {
+	close(k.pusherStopChan)
+	k.pusherWaitGroup.Wait()
+
+	k.destsMu.Lock()
+	defer k.destsMu.Unlock()
+	for _, d := range k.dests {
+		d.stopped.Store(true)
+	}
+	return nil
+}
+
+// Write drops the metrics, only the log events are put to the stream.
+func (k *KinesisLogs) Write(_ []telegraf.Metric) error {
+	return nil
+}
+
+func (k *KinesisLogs) CreateDest(group, stream string, _ int, _ string) logs.LogDest {
+	k.destsMu.Lock()
+	defer k.destsMu.Unlock()
+
+	t := Target{Group: group, Stream: stream}
+	if d, ok := k.dests[t]; ok {
+		return d
+	}
+	d := &kinesisDest{}
+	putter, err := k.getPutter()
+	if err != nil {
+		d.err = err
+	} else {
+		d.pusher = NewPusher(t, putter, partitionKey(putter, t), k.Aggregate, k.ForceFlushInterval.Duration, maxRetryTimeout, k.Log, k.pusherStopChan, &k.pusherWaitGroup)
+	}
+	k.dests[t] = d
+	return d
+}
+
+// getPutter returns the putter of the stream, which is only created once since
+// Connect is not called when the agent runs the OTEL pipelines.
+func (k *KinesisLogs) getPutter() (putter, error) {
+	k.putterOnce.Do(func() {
+		k.putter, k.putterErr = k.newPutter()
+	})
+	return k.putter, k.putterErr
+}
+
+func (k *KinesisLogs) newPutter() (putter, error) {
+	if k.StreamName == "" {
+		return nil, errors.New("stream_name is required")
+	}
+	if k.Service != ServiceFirehose && k.Service != ServiceKinesis {
+		return nil, fmt.Errorf("service %s is not supported", k.Service)
+	}
+
+	credentialConfig := &configaws.CredentialConfig{
+		Region:    k.Region,
+		AccessKey: k.AccessKey,
+		SecretKey: k.SecretKey,
+		RoleARN:   k.RoleARN,
+		Profile:   k.Profile,
+		Filename:  k.Filename,
+		Token:     k.Token,
+	}
+	config := &aws.Config{
+		Endpoint: aws.String(k.EndpointOverride),
+		LogLevel: configaws.SDKLogLevel(),
+		Logger:   configaws.SDKLogger{},
+	}
+	agent.UsageFlags().SetValue(agent.FlagRegionType, k.RegionType)
+	agent.UsageFlags().SetValue(agent.FlagMode, k.Mode)
+	if k.Service == ServiceKinesis {
+		return &kinesisPutter{service: kinesis.New(credentialConfig.Credentials(), config), stream: k.StreamName}, nil
+	}
+	return &firehosePutter{service: firehose.New(credentialConfig.Credentials(), config), stream: k.StreamName}, nil
+}
+
+// partitionKey returns the partition key of the records of the target, so that
+// the log events of a log stream are in order in a shard of a data stream.
+func partitionKey(p putter, t Target) string {
+	if _, ok := p.(*kinesisPutter); !ok {
+		return ""
+	}
+	key := []rune(t.Group + "/" + t.Stream)
+	if len(key) > maxPartitionKeyLength {
+		key = key[:maxPartitionKeyLength]
+	}
+	return string(key)
+}
+
+// kinesisDest is a log stream put to the stream of the output.
+type kinesisDest struct {
+	*pusher
+	stopped atomic.Bool
+	// err is the error of the config of the output, the log events are not put
+	// to the stream.
+	err error
+}
+
+func (d *kinesisDest) Publish(events []logs.LogEvent) error {
+	if d.err != nil {
+		return d.err
+	}
+	if d.stopped.Load() {
+		return logs.ErrOutputStopped
+	}
+	for _, e := range events {
+		d.AddEvent(e)
+	}
+	return nil
+}
+
+// Description returns a one-sentence description on the Output
+func (k *KinesisLogs) Description() string {
+	return "Configuration for the Kinesis Data Firehose or Kinesis Data Streams log events output."
+}
+
+var sampleConfig = `
+  ## Amazon REGION
+  region = "us-east-1"
+
+  ## Amazon Credentials
+  ## Credentials are loaded in the following order
+  ## 1) Assumed credentials via STS if role_arn is specified
+  ## 2) explicit credentials from 'access_key' and 'secret_key'
+  ## 3) shared profile from 'profile'
+  ## 4) environment variables
+  ## 5) shared credentials file
+  ## 6) EC2 Instance Profile
+  #access_key = ""
+  #secret_key = ""
+  #token = ""
+  #role_arn = ""
+  #profile = ""
+  #shared_credential_file = ""
+
+  ## Put the log events to a Kinesis Data Firehose delivery stream (firehose) or
+  ## to a Kinesis data stream (kinesis)
+  service = "firehose"
+  stream_name = "<stream_name>"
+
+  ## Put as many log events as fit in each record, as JSON lines
+  aggregate = false
+`
+
+// SampleConfig returns the default configuration of the Output
+func (k *KinesisLogs) SampleConfig() string {
+	return sampleConfig
+}
+
+func init() {
+	outputs.Add("kinesislogs", func() telegraf.Output {
+		return &KinesisLogs{
+			Service:            ServiceFirehose,
+			ForceFlushInterval: internal.Duration{Duration: defaultFlushTimeout},
+			pusherStopChan:     make(chan struct{}),
+			dests:              make(map[Target]*kinesisDest),
+		}
+	})
+}
@@ -0,0 +1,269 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesislogs
+
+import (
+	"encoding/json"
+	"math/rand"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/influxdata/telegraf"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/profiler"
+)
+
+const (
+	truncatedSuffix = "[Truncated...]"
+)
+
+var (
+	seededRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// record is the JSON line of a log event in a record.
+type record struct {
+	LogGroup  string `json:"log_group"`
+	LogStream string `json:"log_stream"`
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+// pusher batches the log events of a target into the records of the requests,
+// one log event for each record or, with Aggregate, as many log events as fit
+// in a record.
+type pusher struct {
+	Target
+	putter        putter
+	limits        limits
+	partitionKey  string
+	Aggregate     bool
+	FlushTimeout  time.Duration
+	RetryDuration time.Duration
+	Log           telegraf.Logger
+
+	eventsCh      chan logs.LogEvent
+	records       [][]byte
+	bufferedSize  int
+	doneCallbacks []func()
+	flushTimer    *time.Timer
+	lastSentTime  time.Time
+	stop          <-chan struct{}
+	wg            *sync.WaitGroup
+}
+
+func NewPusher(target Target, putter putter, partitionKey string, aggregate bool, flushTimeout time.Duration, retryDuration time.Duration, logger telegraf.Logger, stop <-chan struct{}, wg *sync.WaitGroup) *pusher {
+	p := &pusher{
+		Target:        target,
+		putter:        putter,
+		limits:        putter.limits(),
+		partitionKey:  partitionKey,
+		Aggregate:     aggregate,
+		FlushTimeout:  flushTimeout,
+		RetryDuration: retryDuration,
+		Log:           logger,
+		eventsCh:      make(chan logs.LogEvent, 100),
+		flushTimer:    time.NewTimer(flushTimeout),
+		stop:          stop,
+		wg:            wg,
+	}
+	p.wg.Add(1)
+	go p.start()
+	return p
+}
+
+func (p *pusher) AddEvent(e logs.LogEvent) {
+	select {
+	case p.eventsCh <- e:
+	case <-p.stop:
+	}
+}
+
+func (p *pusher) start() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case e := <-p.eventsCh:
+			// Start timer when first event of the batch is added (happens after a flush timer timeout)
+			if len(p.records) == 0 {
+				p.resetFlushTimer()
+			}
+			line := p.encode(e)
+			if line == nil {
+				continue
+			}
+			p.add(line)
+			p.doneCallbacks = append(p.doneCallbacks, e.Done)
+
+		case <-p.flushTimer.C:
+			if time.Since(p.lastSentTime) >= p.FlushTimeout && len(p.records) > 0 {
+				p.send()
+			} else {
+				p.resetFlushTimer()
+			}
+		case <-p.stop:
+			if len(p.records) > 0 {
+				p.send()
+			}
+			return
+		}
+	}
+}
+
+// encode returns the JSON line of the log event, with its message truncated so
+// that it fits in a record.
+func (p *pusher) encode(e logs.LogEvent) []byte {
+	t := e.Time()
+	if t.IsZero() {
+		t = time.Now()
+	}
+	r := record{
+		LogGroup:  p.Group,
+		LogStream: p.Stream,
+		Timestamp: t.UnixMilli(),
+		Message:   e.Message(),
+	}
+	maxSize := p.limits.recordSize - len(p.partitionKey)
+	line, err := json.Marshal(r)
+	if err == nil && len(line)+1 > maxSize {
+		// the escaped message is at least as long as the message, so cutting the
+		// excess from the message is enough
+		r.Message = truncate(r.Message, len(r.Message)-(len(line)+1-maxSize)-len(truncatedSuffix)) + truncatedSuffix
+		line, err = json.Marshal(r)
+	}
+	if err != nil || len(line)+1 > maxSize {
+		p.Log.Errorf("Unable to fit the log event of %v/%v in a record, the log event is dropped: %v", p.Group, p.Stream, err)
+		return nil
+	}
+	return append(line, '\n')
+}
+
+// truncate returns the first n bytes of the message, without splitting a rune.
+func truncate(msg string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	for n > 0 && !utf8.RuneStart(msg[n]) {
+		n--
+	}
+	return msg[:n]
+}
+
+// add adds the line to the last record when aggregating and it fits, or to a
+// new record otherwise, and sends the batch first when the request is full.
+func (p *pusher) add(line []byte) {
+	if p.Aggregate && len(p.records) > 0 {
+		last := len(p.records) - 1
+		if len(p.records[last])+len(line)+len(p.partitionKey) <= p.limits.recordSize && p.bufferedSize+len(line) <= p.limits.batchSize {
+			p.records[last] = append(p.records[last], line...)
+			p.bufferedSize += len(line)
+			return
+		}
+	}
+	size := len(line) + len(p.partitionKey)
+	if len(p.records) == p.limits.batchRecords || p.bufferedSize+size > p.limits.batchSize {
+		p.send()
+	}
+	p.records = append(p.records, line)
+	p.bufferedSize += size
+}
+
+func (p *pusher) reset() {
+	for i := 0; i < len(p.records); i++ {
+		p.records[i] = nil
+	}
+	p.records = p.records[:0]
+	for i := 0; i < len(p.doneCallbacks); i++ {
+		p.doneCallbacks[i] = nil
+	}
+	p.doneCallbacks = p.doneCallbacks[:0]
+	p.bufferedSize = 0
+}
+
+// send puts the records of the batch, and retries the records that failed until
+// they are all put.
+func (p *pusher) send() {
+	defer p.resetFlushTimer() // Reset the flush timer after sending the request
+
+	records := p.records
+	startTime := time.Now()
+
+	retryCount := 0
+	for {
+		failed, err := p.putter.put(records, p.partitionKey)
+		if err == nil {
+			for i := len(p.doneCallbacks) - 1; i >= 0; i-- {
+				done := p.doneCallbacks[i]
+				done()
+			}
+
+			p.Log.Debugf("Pusher published %v log events of group: %v stream: %v in %v records with size %v KB in %v.", len(p.doneCallbacks), p.Group, p.Stream, len(p.records), p.bufferedSize/1024, time.Since(startTime))
+			p.addStats("rawSize", float64(p.bufferedSize))
+			p.addStats("records", float64(len(p.records)))
+
+			p.reset()
+			p.lastSentTime = time.Now()
+			return
+		}
+
+		if len(failed) > 0 {
+			// only the records that failed are retried
+			retried := make([][]byte, 0, len(failed))
+			for _, i := range failed {
+				retried = append(retried, records[i])
+			}
+			records = retried
+			p.Log.Warnf("Failed to put the records of %v/%v: %v", p.Group, p.Stream, err)
+		} else if !p.putter.retryable(err) {
+			p.Log.Errorf("Error received when sending logs of %v/%v: %v, will not retry the request", p.Group, p.Stream, err)
+			p.reset()
+			return
+		} else {
+			p.Log.Errorf("Error received when sending logs of %v/%v: %v", p.Group, p.Stream, err)
+		}
+
+		wait := retryWait(retryCount)
+		if time.Since(startTime)+wait > p.RetryDuration {
+			p.Log.Errorf("All %v retries of %v/%v failed, %v records dropped.", retryCount, p.Group, p.Stream, len(records))
+			p.reset()
+			return
+		}
+
+		p.Log.Warnf("Retried %v time, going to sleep %v before retrying.", retryCount, wait)
+		p.addStats("retries", 1)
+
+		select {
+		case <-p.stop:
+			p.Log.Errorf("Stop requested after %v retries of %v/%v failed, %v records dropped.", retryCount, p.Group, p.Stream, len(records))
+			p.reset()
+			return
+		case <-time.After(wait):
+		}
+
+		retryCount++
+	}
+}
+
+func retryWait(n int) time.Duration {
+	const base = 200 * time.Millisecond
+	// Max wait time is 1 minute (jittered)
+	d := 1 * time.Minute
+	if n < 5 {
+		d = base * time.Duration(1<<int64(n))
+	}
+	return time.Duration(seededRand.Int63n(int64(d/2)) + int64(d/2))
+}
+
+func (p *pusher) resetFlushTimer() {
+	p.flushTimer.Stop()
+	p.flushTimer.Reset(p.FlushTimeout)
+}
+
+func (p *pusher) addStats(statsName string, value float64) {
+	statsKey := []string{"kinesislogs", p.Group, statsName}
+	profiler.Profiler.AddStats(statsKey, value)
+}
@@ -0,0 +1,205 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesislogs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type evt struct {
+	msg  string
+	t    time.Time
+	done atomic.Bool
+}
+
+func (e *evt) Message() string { return e.msg }
+func (e *evt) Time() time.Time { return e.t }
+func (e *evt) Done()           { e.done.Store(true) }
+
+// mockPutter records the batches, and fails the records of the responses in
+// order.
+type mockPutter struct {
+	mu        sync.Mutex
+	l         limits
+	batches   [][][]byte
+	keys      []string
+	responses []mockResponse
+}
+
+type mockResponse struct {
+	failed []int
+	err    error
+}
+
+func (m *mockPutter) put(records [][]byte, partitionKey string) ([]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	batch := make([][]byte, len(records))
+	for i, r := range records {
+		batch[i] = append([]byte(nil), r...)
+	}
+	m.batches = append(m.batches, batch)
+	m.keys = append(m.keys, partitionKey)
+	if len(m.responses) == 0 {
+		return nil, nil
+	}
+	r := m.responses[0]
+	m.responses = m.responses[1:]
+	return r.failed, r.err
+}
+
+func (m *mockPutter) limits() limits {
+	return m.l
+}
+
+func (m *mockPutter) retryable(err error) bool {
+	return !strings.Contains(err.Error(), "not retryable")
+}
+
+func (m *mockPutter) getBatches() [][][]byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.batches
+}
+
+// newTestPusher returns a pusher that flushes its batch 100ms after the first
+// log event, and stops once the test is done.
+func newTestPusher(t *testing.T, putter putter, aggregate bool) *pusher {
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	p := NewPusher(Target{Group: "G", Stream: "S"}, putter, "", aggregate, 100*time.Millisecond, time.Minute, &testutil.Logger{}, stop, &wg)
+	t.Cleanup(func() {
+		close(stop)
+		wg.Wait()
+	})
+	return p
+}
+
+func waitBatches(t *testing.T, m *mockPutter, n int) [][][]byte {
+	require.Eventually(t, func() bool { return len(m.getBatches()) >= n }, 5*time.Second, 10*time.Millisecond)
+	// no other batch is sent
+	time.Sleep(200 * time.Millisecond)
+	batches := m.getBatches()
+	require.Len(t, batches, n)
+	return batches
+}
+
+func messages(t *testing.T, data []byte) []string {
+	var msgs []string
+	for _, line := range bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n")) {
+		var r record
+		require.NoError(t, json.Unmarshal(line, &r))
+		msgs = append(msgs, r.Message)
+	}
+	return msgs
+}
+
+func TestPusherBatchRecords(t *testing.T) {
+	m := &mockPutter{l: limits{recordSize: 1024, batchSize: 1024 * 1024, batchRecords: 3}}
+	p := newTestPusher(t, m, false)
+
+	events := make([]*evt, 7)
+	for i := range events {
+		events[i] = &evt{msg: strings.Repeat("a", i+1), t: time.UnixMilli(1000)}
+		p.AddEvent(events[i])
+	}
+
+	batches := waitBatches(t, m, 3)
+	assert.Len(t, batches[0], 3)
+	assert.Len(t, batches[1], 3)
+	assert.Len(t, batches[2], 1)
+	assert.Equal(t, `{"log_group":"G","log_stream":"S","timestamp":1000,"message":"a"}`+"\n", string(batches[0][0]))
+	for _, e := range events {
+		assert.True(t, e.done.Load())
+	}
+}
+
+func TestPusherBatchSize(t *testing.T) {
+	m := &mockPutter{l: limits{recordSize: 1024, batchSize: 300, batchRecords: 500}}
+	p := newTestPusher(t, m, false)
+
+	// each record is about 160 bytes, so a request has one record
+	for i := 0; i < 3; i++ {
+		p.AddEvent(&evt{msg: strings.Repeat("a", 100)})
+	}
+	waitBatches(t, m, 3)
+}
+
+func TestPusherAggregate(t *testing.T) {
+	m := &mockPutter{l: limits{recordSize: 300, batchSize: 1024 * 1024, batchRecords: 500}}
+	p := newTestPusher(t, m, true)
+
+	for _, msg := range []string{"one", "two", "three", strings.Repeat("a", 100)} {
+		p.AddEvent(&evt{msg: msg})
+	}
+
+	batches := waitBatches(t, m, 1)
+	require.Len(t, batches[0], 2)
+	assert.Equal(t, []string{"one", "two", "three"}, messages(t, batches[0][0]))
+	assert.Equal(t, []string{strings.Repeat("a", 100)}, messages(t, batches[0][1]))
+}
+
+func TestPusherTruncate(t *testing.T) {
+	m := &mockPutter{l: limits{recordSize: 200, batchSize: 1024 * 1024, batchRecords: 500}}
+	p := newTestPusher(t, m, false)
+
+	p.AddEvent(&evt{msg: strings.Repeat("é", 200)})
+
+	batches := waitBatches(t, m, 1)
+	assert.LessOrEqual(t, len(batches[0][0]), 200)
+	msg := messages(t, batches[0][0])[0]
+	assert.True(t, strings.HasSuffix(msg, truncatedSuffix))
+	assert.True(t, strings.HasPrefix(msg, "éé"))
+}
+
+func TestPusherPartialFailure(t *testing.T) {
+	m := &mockPutter{
+		l:         limits{recordSize: 1024, batchSize: 1024 * 1024, batchRecords: 500},
+		responses: []mockResponse{{failed: []int{1, 2}, err: errors.New("2 of 3 records failed")}, {failed: []int{0}, err: errors.New("1 of 2 records failed")}},
+	}
+	p := newTestPusher(t, m, false)
+
+	events := []*evt{{msg: "one"}, {msg: "two"}, {msg: "three"}}
+	for _, e := range events {
+		p.AddEvent(e)
+	}
+
+	batches := waitBatches(t, m, 3)
+	assert.Len(t, batches[0], 3)
+	// only the records that failed are retried
+	require.Len(t, batches[1], 2)
+	assert.Equal(t, []string{"two"}, messages(t, batches[1][0]))
+	assert.Equal(t, []string{"three"}, messages(t, batches[1][1]))
+	require.Len(t, batches[2], 1)
+	assert.Equal(t, []string{"two"}, messages(t, batches[2][0]))
+	for _, e := range events {
+		assert.True(t, e.done.Load())
+	}
+}
+
+func TestPusherNotRetryable(t *testing.T) {
+	m := &mockPutter{
+		l:         limits{recordSize: 1024, batchSize: 1024 * 1024, batchRecords: 500},
+		responses: []mockResponse{{err: errors.New("stream not found, not retryable")}},
+	}
+	p := newTestPusher(t, m, false)
+
+	e := &evt{msg: "one"}
+	p.AddEvent(e)
+
+	waitBatches(t, m, 1)
+	// the log event is not done, so it is read again after a restart
+	assert.False(t, e.done.Load())
+}
@@ -0,0 +1,150 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kinesislogs
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/firehose"
+	"github.com/aws/aws-sdk-go/service/kinesis"
+)
+
+const (
+	ServiceFirehose = "firehose"
+	ServiceKinesis  = "kinesis"
+)
+
+type FirehoseService interface {
+	PutRecordBatch(*firehose.PutRecordBatchInput) (*firehose.PutRecordBatchOutput, error)
+}
+
+type KinesisService interface {
+	PutRecords(*kinesis.PutRecordsInput) (*kinesis.PutRecordsOutput, error)
+}
+
+// limits are the quotas of the requests of a service.
+type limits struct {
+	// recordSize is the max size of a record, including its partition key.
+	recordSize int
+	// batchSize is the max size of a request, including the partition keys.
+	batchSize int
+	// batchRecords is the max number of records of a request.
+	batchRecords int
+}
+
+var (
+	// https://docs.aws.amazon.com/firehose/latest/APIReference/API_PutRecordBatch.html
+	firehoseLimits = limits{recordSize: 1000 * 1024, batchSize: 4 * 1024 * 1024, batchRecords: 500}
+	// https://docs.aws.amazon.com/kinesis/latest/APIReference/API_PutRecords.html
+	kinesisLimits = limits{recordSize: 1024 * 1024, batchSize: 5 * 1024 * 1024, batchRecords: 500}
+)
+
+// putter puts the records of a batch to a stream.
+type putter interface {
+	// put returns the indexes of the records that failed, which are retried.
+	put(records [][]byte, partitionKey string) (failed []int, err error)
+	limits() limits
+	// retryable reports whether the request is retried after the error.
+	retryable(err error) bool
+}
+
+type firehosePutter struct {
+	service FirehoseService
+	stream  string
+}
+
+func (f *firehosePutter) put(records [][]byte, _ string) ([]int, error) {
+	input := &firehose.PutRecordBatchInput{
+		DeliveryStreamName: aws.String(f.stream),
+		Records:            make([]*firehose.Record, len(records)),
+	}
+	for i, data := range records {
+		input.Records[i] = &firehose.Record{Data: data}
+	}
+	output, err := f.service.PutRecordBatch(input)
+	if err != nil {
+		return nil, err
+	}
+	if aws.Int64Value(output.FailedPutCount) == 0 {
+		return nil, nil
+	}
+	var failed []int
+	var last string
+	for i, r := range output.RequestResponses {
+		if r.ErrorCode != nil {
+			failed = append(failed, i)
+			last = fmt.Sprintf("%s: %s", aws.StringValue(r.ErrorCode), aws.StringValue(r.ErrorMessage))
+		}
+	}
+	return failed, partialFailureError(len(failed), len(records), last)
+}
+
+func (f *firehosePutter) limits() limits {
+	return firehoseLimits
+}
+
+func (f *firehosePutter) retryable(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		switch awsErr.Code() {
+		case firehose.ErrCodeResourceNotFoundException, firehose.ErrCodeInvalidArgumentException,
+			firehose.ErrCodeInvalidKMSResourceException:
+			return false
+		}
+	}
+	return true
+}
+
+type kinesisPutter struct {
+	service KinesisService
+	stream  string
+}
+
+func (k *kinesisPutter) put(records [][]byte, partitionKey string) ([]int, error) {
+	input := &kinesis.PutRecordsInput{
+		StreamName: aws.String(k.stream),
+		Records:    make([]*kinesis.PutRecordsRequestEntry, len(records)),
+	}
+	for i, data := range records {
+		input.Records[i] = &kinesis.PutRecordsRequestEntry{Data: data, PartitionKey: aws.String(partitionKey)}
+	}
+	output, err := k.service.PutRecords(input)
+	if err != nil {
+		return nil, err
+	}
+	if aws.Int64Value(output.FailedRecordCount) == 0 {
+		return nil, nil
+	}
+	var failed []int
+	var last string
+	for i, r := range output.Records {
+		if r.ErrorCode != nil {
+			failed = append(failed, i)
+			last = fmt.Sprintf("%s: %s", aws.StringValue(r.ErrorCode), aws.StringValue(r.ErrorMessage))
+		}
+	}
+	return failed, partialFailureError(len(failed), len(records), last)
+}
+
+func (k *kinesisPutter) limits() limits {
+	return kinesisLimits
+}
+
+func (k *kinesisPutter) retryable(err error) bool {
+	if awsErr, ok := err.(awserr.Error); ok {
+		switch awsErr.Code() {
+		case kinesis.ErrCodeResourceNotFoundException, kinesis.ErrCodeInvalidArgumentException,
+			kinesis.ErrCodeAccessDeniedException, kinesis.ErrCodeKMSAccessDeniedException,
+			kinesis.ErrCodeKMSDisabledException, kinesis.ErrCodeKMSNotFoundException,
+			kinesis.ErrCodeKMSInvalidStateException:
+			return false
+		}
+	}
+	return true
+}
+
+func partialFailureError(failed, total int, last string) error {
+	return fmt.Errorf("%d of %d records failed, last error %s", failed, total, last)
+}
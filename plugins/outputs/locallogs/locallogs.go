@@ -0,0 +1,245 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package locallogs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/outputs"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+const (
+	OutputFile   = "file"
+	OutputStdout = "stdout"
+
+	defaultMaxFileSizeMB = 100
+	defaultMaxFiles      = 5
+	fileExtension        = ".log"
+	// fileNameHashLength is the number of hex digits of the log group name hash
+	// added to the file names.
+	fileNameHashLength = 8
+)
+
+var (
+	// fileNameCharacters matches the characters of the log group names that are
+	// replaced in the file names.
+	fileNameCharacters = regexp.MustCompile(`[^A-Za-z0-9_.#-]`)
+)
+
+// LocalLogs writes the log events to local files or the standard output as JSON
+// lines, instead of sending them to CloudWatch Logs.
+type LocalLogs struct {
+	//The output of the log events, file or stdout.
+	Output string `toml:"output"`
+	//The directory of the files, one file for each log group.
+	Directory string `toml:"directory"`
+	//The size in megabytes after which a file is rotated.
+	MaxFileSizeMB int `toml:"max_file_size_mb"`
+	//The number of rotated files kept for each log group.
+	MaxFiles int `toml:"max_files"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	mu      sync.Mutex
+	closed  bool
+	stdout  io.Writer
+	writers map[string]io.WriteCloser
+	dests   map[target]*localDest
+}
+
+type target struct {
+	group, stream string
+}
+
+// record is the JSON line of a log event.
+type record struct {
+	LogGroup  string `json:"log_group"`
+	LogStream string `json:"log_stream"`
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+var _ logs.LogBackend = (*LocalLogs)(nil)
+
+func (l *LocalLogs) Connect() error {
+	switch l.Output {
+	case OutputFile:
+		if l.Directory == "" {
+			return fmt.Errorf("directory is required with output %s", OutputFile)
+		}
+	case OutputStdout:
+	default:
+		return fmt.Errorf("output %s is not supported", l.Output)
+	}
+	return nil
+}
+
+func (l *LocalLogs) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	var errs []string
+	for _, w := range l.writers {
+		if err := w.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	l.writers = make(map[string]io.WriteCloser)
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to close the log files: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// Write drops the metrics, only the log events are written.
+func (l *LocalLogs) Write(_ []telegraf.Metric) error {
+	return nil
+}
+
+func (l *LocalLogs) CreateDest(group, stream string, _ int, _ string) logs.LogDest {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	t := target{group: group, stream: stream}
+	if d, ok := l.dests[t]; ok {
+		return d
+	}
+	d := &localDest{target: t, output: l}
+	l.dests[t] = d
+	return d
+}
+
+// write writes the line to the output of the log group.
+func (l *LocalLogs) write(group string, line []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return logs.ErrOutputStopped
+	}
+	var w io.Writer
+	if l.Output == OutputStdout {
+		w = l.stdout
+	} else {
+		w = l.writer(group)
+	}
+	_, err := w.Write(line)
+	return err
+}
+
+// writer returns the rotated file of the log group. The writers are kept by
+// file name so that a file is only ever rotated by a single writer.
+func (l *LocalLogs) writer(group string) io.Writer {
+	name := fileName(group)
+	if w, ok := l.writers[name]; ok {
+		return w
+	}
+	w := &lumberjack.Logger{
+		Filename:   filepath.Join(l.Directory, name),
+		MaxSize:    l.MaxFileSizeMB,
+		MaxBackups: l.MaxFiles,
+	}
+	l.writers[name] = w
+	return w
+}
+
+// fileName returns the name of the file of the log group. If characters of
+// the log group name are replaced, a hash of the log group name is added so
+// that log groups like /a/b, a/b and a_b are written to different files, e.g.
+// aws_lambda_app_54dd5e18.log for /aws/lambda/app.
+func fileName(group string) string {
+	name := fileNameCharacters.ReplaceAllString(strings.Trim(group, "/"), "_")
+	if name == "" {
+		name = "default"
+	}
+	if name != group {
+		sum := sha256.Sum256([]byte(group))
+		name += "_" + hex.EncodeToString(sum[:])[:fileNameHashLength]
+	}
+	return name + fileExtension
+}
+
+// localDest is a log stream written to the output of the local logs.
+type localDest struct {
+	target
+	output *LocalLogs
+}
+
+func (d *localDest) Publish(events []logs.LogEvent) error {
+	for _, e := range events {
+		t := e.Time()
+		if t.IsZero() {
+			t = time.Now()
+		}
+		line, err := json.Marshal(record{
+			LogGroup:  d.group,
+			LogStream: d.stream,
+			Timestamp: t.UnixMilli(),
+			Message:   e.Message(),
+		})
+		if err != nil {
+			d.output.Log.Errorf("Failed to marshal the log event of %s/%s: %v", d.group, d.stream, err)
+			continue
+		}
+		line = append(line, '\n')
+		if err = d.output.write(d.group, line); err == logs.ErrOutputStopped {
+			return err
+		} else if err != nil {
+			// the log event is not done, so it is read again after a restart
+			d.output.Log.Errorf("Failed to write the log event of %s/%s: %v", d.group, d.stream, err)
+			continue
+		}
+		e.Done()
+	}
+	return nil
+}
+
+// Description returns a one-sentence description on the Output
+func (l *LocalLogs) Description() string {
+	return "Configuration for the local files or standard output log events output."
+}
+
+var sampleConfig = `
+  ## Write the log events to local files (file) or to the standard output (stdout)
+  ## as JSON lines with their log group, log stream and timestamp
+  output = "file"
+
+  ## The directory of the files, one file for each log group
+  directory = "/opt/aws/amazon-cloudwatch-agent/logs/locallogs"
+
+  ## The size in megabytes after which a file is rotated, and the number of
+  ## rotated files kept for each log group
+  max_file_size_mb = 100
+  max_files = 5
+`
+
+// SampleConfig returns the default configuration of the Output
+func (l *LocalLogs) SampleConfig() string {
+	return sampleConfig
+}
+
+func init() {
+	outputs.Add("locallogs", func() telegraf.Output {
+		return &LocalLogs{
+			Output:        OutputFile,
+			MaxFileSizeMB: defaultMaxFileSizeMB,
+			MaxFiles:      defaultMaxFiles,
+			stdout:        os.Stdout,
+			writers:       make(map[string]io.WriteCloser),
+			dests:         make(map[target]*localDest),
+		}
+	})
+}
@@ -0,0 +1,149 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package locallogs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/plugins/outputs"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+type testEvent struct {
+	msg  string
+	t    time.Time
+	done bool
+}
+
+func (e *testEvent) Message() string { return e.msg }
+func (e *testEvent) Time() time.Time { return e.t }
+func (e *testEvent) Done()           { e.done = true }
+
+func newLocalLogs(t *testing.T) *LocalLogs {
+	l := outputs.Outputs["locallogs"]().(*LocalLogs)
+	l.Log = &testutil.Logger{}
+	l.Directory = t.TempDir()
+	return l
+}
+
+func readRecords(t *testing.T, filename string) []record {
+	f, err := os.Open(filename)
+	require.NoError(t, err)
+	defer f.Close()
+	var records []record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 2*1024*1024)
+	for scanner.Scan() {
+		var r record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		records = append(records, r)
+	}
+	require.NoError(t, scanner.Err())
+	return records
+}
+
+func TestFileOutput(t *testing.T) {
+	l := newLocalLogs(t)
+	require.NoError(t, l.Connect())
+
+	ts := time.UnixMilli(1710072000123)
+	events := []*testEvent{{msg: "first", t: ts}, {msg: "second\nline", t: ts}}
+	dest := l.CreateDest("/aws/app", "i-0123", -1, "")
+	assert.Same(t, dest, l.CreateDest("/aws/app", "i-0123", -1, ""))
+	require.NoError(t, dest.Publish([]logs.LogEvent{events[0], events[1]}))
+	require.NoError(t, l.CreateDest("other group", "s", -1, "").Publish([]logs.LogEvent{&testEvent{msg: "third"}}))
+	require.NoError(t, l.Close())
+
+	for _, e := range events {
+		assert.True(t, e.done)
+	}
+	assert.Equal(t, []record{
+		{LogGroup: "/aws/app", LogStream: "i-0123", Timestamp: 1710072000123, Message: "first"},
+		{LogGroup: "/aws/app", LogStream: "i-0123", Timestamp: 1710072000123, Message: "second\nline"},
+	}, readRecords(t, filepath.Join(l.Directory, "aws_app_6269f7f4.log")))
+	records := readRecords(t, filepath.Join(l.Directory, "other_group_240b7fcb.log"))
+	require.Len(t, records, 1)
+	assert.Equal(t, "third", records[0].Message)
+	// the events without a timestamp are written with the current time
+	assert.InDelta(t, time.Now().UnixMilli(), records[0].Timestamp, float64(time.Minute.Milliseconds()))
+
+	// the events are not written once the output is closed
+	e := &testEvent{msg: "late"}
+	assert.Equal(t, logs.ErrOutputStopped, dest.Publish([]logs.LogEvent{e}))
+	assert.False(t, e.done)
+}
+
+func TestFileRotation(t *testing.T) {
+	l := newLocalLogs(t)
+	l.MaxFileSizeMB = 1
+	require.NoError(t, l.Connect())
+
+	msg := strings.Repeat("a", 300*1024)
+	dest := l.CreateDest("app", "s", -1, "")
+	for i := 0; i < 4; i++ {
+		require.NoError(t, dest.Publish([]logs.LogEvent{&testEvent{msg: msg}}))
+	}
+	require.NoError(t, l.Close())
+
+	matches, err := filepath.Glob(filepath.Join(l.Directory, "app*.log"))
+	require.NoError(t, err)
+	assert.Len(t, matches, 2)
+	assert.Len(t, readRecords(t, filepath.Join(l.Directory, "app.log")), 1)
+}
+
+func TestStdoutOutput(t *testing.T) {
+	l := newLocalLogs(t)
+	var buf bytes.Buffer
+	l.stdout = &buf
+	l.Output = OutputStdout
+	l.Directory = ""
+	require.NoError(t, l.Connect())
+
+	require.NoError(t, l.CreateDest("app", "s", -1, "").Publish([]logs.LogEvent{&testEvent{msg: "hello", t: time.UnixMilli(1000)}}))
+	require.NoError(t, l.Close())
+	assert.Equal(t, `{"log_group":"app","log_stream":"s","timestamp":1000,"message":"hello"}`+"\n", buf.String())
+}
+
+func TestConnect(t *testing.T) {
+	l := newLocalLogs(t)
+	l.Directory = ""
+	assert.EqualError(t, l.Connect(), "directory is required with output file")
+	l.Output = "kafka"
+	assert.EqualError(t, l.Connect(), "output kafka is not supported")
+}
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "aws_lambda_app_54dd5e18.log", fileName("/aws/lambda/app"))
+	assert.Equal(t, "app-1.prod#2.log", fileName("app-1.prod#2"))
+	assert.Equal(t, "default_8a5edab2.log", fileName("/"))
+	// log group names that only differ in the replaced characters
+	assert.Equal(t, "a_b_662b7b62.log", fileName("/a/b"))
+	assert.Equal(t, "a_b_c14cddc0.log", fileName("a/b"))
+	assert.Equal(t, "a_b.log", fileName("a_b"))
+}
+
+func TestFileNameCollision(t *testing.T) {
+	l := newLocalLogs(t)
+	require.NoError(t, l.Connect())
+	for _, group := range []string{"/a/b", "a/b", "a_b"} {
+		require.NoError(t, l.CreateDest(group, "s", -1, "").Publish([]logs.LogEvent{&testEvent{msg: group}}))
+	}
+	require.NoError(t, l.Close())
+	for _, group := range []string{"/a/b", "a/b", "a_b"} {
+		records := readRecords(t, filepath.Join(l.Directory, fileName(group)))
+		require.Len(t, records, 1)
+		assert.Equal(t, group, records[0].LogGroup)
+	}
+}
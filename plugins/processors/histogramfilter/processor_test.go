@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package histogramfilter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/seh1"
+)
+
+func TestProcessMetrics(t *testing.T) {
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("statsd_gauge")
+	gauge.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(1)
+	// a statsd timing metric is carried as an SEH1 distribution
+	timing := metrics.AppendEmpty()
+	timing.SetName("statsd_timing")
+	timing.SetUnit("Milliseconds")
+	dist := seh1.NewSEH1Distribution()
+	require.NoError(t, dist.AddEntry(120, 1))
+	require.NoError(t, dist.AddEntry(-3, 2))
+	dist.ConvertToOtel(timing.SetEmptyHistogram().DataPoints().AppendEmpty())
+
+	got, err := processMetrics(context.Background(), md)
+	require.NoError(t, err)
+	require.Equal(t, 1, got.MetricCount())
+	assert.Equal(t, "statsd_gauge", got.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+
+	// nothing is left to export
+	md = pmetric.NewMetrics()
+	timing.CopyTo(md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty())
+	_, err = processMetrics(context.Background(), md)
+	assert.ErrorIs(t, err, processorhelper.ErrSkipProcessingData)
+}
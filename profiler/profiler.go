@@ -12,7 +12,8 @@ import (
 
 var (
 	Profiler profiler = profiler{
-		stats: make(map[string]float64),
+		stats:  make(map[string]float64),
+		totals: make(map[string]float64),
 	}
 	noStatsInProfiler = "[no stats is available...]"
 )
@@ -20,6 +21,11 @@ var (
 type profiler struct {
 	sync.Mutex
 	stats map[string]float64
+	// totals are not cleared by ReportAndClear, so they can be read by
+	// consumers other than the debug dump. They are summed by plugin and stat
+	// name, so the number of totals does not grow with the log groups, streams
+	// or hosts in the keys.
+	totals map[string]float64
 }
 
 // use slice for key is enough now, could be expand to map if we need dimensions
@@ -28,6 +34,16 @@ func (p *profiler) AddStats(key []string, value float64) {
 	defer p.Unlock()
 	k := strings.Join(key, "_")
 	p.stats[k] += value
+	p.totals[totalKey(key)] += value
+}
+
+// totalKey is the plugin and stat name of the key, e.g. the key
+// cloudwatchlogs, <group>, rawSize is summed into cloudwatchlogs_rawSize.
+func totalKey(key []string) string {
+	if len(key) <= 2 {
+		return strings.Join(key, "_")
+	}
+	return key[0] + "_" + key[len(key)-1]
 }
 
 // GetStats for testing purposes
@@ -37,6 +53,18 @@ func (p *profiler) GetStats() map[string]float64 {
 	return p.stats
 }
 
+// GetTotals returns a copy of the stats accumulated since the agent started,
+// summed by plugin and stat name.
+func (p *profiler) GetTotals() map[string]float64 {
+	p.Lock()
+	defer p.Unlock()
+	totals := make(map[string]float64, len(p.totals))
+	for k, v := range p.totals {
+		totals[k] = v
+	}
+	return totals
+}
+
 func (p *profiler) ReportAndClear() {
 	p.Lock()
 	defer p.Unlock()
@@ -54,3 +54,23 @@ func TestProfilerGetStats(t *testing.T) {
 	_, ok = stats[name]
 	assert.False(t, ok)
 }
+
+func TestProfilerGetTotals(t *testing.T) {
+	name := t.Name() + "_StatsA"
+	Profiler.AddStats([]string{t.Name(), "StatsA"}, 1)
+	Profiler.ReportAndClear()
+	Profiler.AddStats([]string{t.Name(), "StatsA"}, 2)
+
+	totals := Profiler.GetTotals()
+	assert.Equal(t, 3.0, totals[name])
+	totals[name] = 0
+	assert.Equal(t, 3.0, Profiler.GetTotals()[name])
+
+	// the totals of keys with log groups or streams are summed by plugin and
+	// stat name
+	Profiler.AddStats([]string{t.Name(), "group_a", "StatsB"}, 1)
+	Profiler.AddStats([]string{t.Name(), "group_b", "stream", "StatsB"}, 2)
+	totals = Profiler.GetTotals()
+	assert.Equal(t, 3.0, totals[t.Name()+"_StatsB"])
+	assert.NotContains(t, totals, t.Name()+"_group_a_StatsB")
+}
@@ -0,0 +1,41 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agenthealth
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/scraperhelper"
+)
+
+var (
+	TypeStr, _ = component.NewType("agenthealth")
+)
+
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		TypeStr,
+		createDefaultConfig,
+		receiver.WithMetrics(createMetricsReceiver, component.StabilityLevelAlpha),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	cfg := &Config{ControllerConfig: scraperhelper.NewDefaultControllerConfig()}
+	cfg.CollectionInterval = time.Minute
+	return cfg
+}
+
+func createMetricsReceiver(_ context.Context, settings receiver.CreateSettings, cfg component.Config, consumer consumer.Metrics) (receiver.Metrics, error) {
+	rCfg := cfg.(*Config)
+	s, err := scraperhelper.NewScraper(TypeStr.String(), newHealthScraper().scrape)
+	if err != nil {
+		return nil, err
+	}
+	return scraperhelper.NewScraperControllerReceiver(&rCfg.ControllerConfig, settings, consumer, scraperhelper.AddScraper(s))
+}
@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agenthealth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func TestCreateMetricsReceiver(t *testing.T) {
+	factory := NewFactory()
+	assert.Equal(t, TypeStr, factory.Type())
+	cfg := factory.CreateDefaultConfig().(*Config)
+	assert.Equal(t, time.Minute, cfg.CollectionInterval)
+	assert.NoError(t, cfg.Validate())
+
+	r, err := factory.CreateMetricsReceiver(context.Background(), receivertest.NewNopCreateSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.NotNil(t, r)
+}
@@ -0,0 +1,123 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agenthealth
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/agent"
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/client"
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/provider"
+	"github.com/aws/amazon-cloudwatch-agent/profiler"
+)
+
+const (
+	attributeOperation = "Operation"
+
+	unitCount        = "Count"
+	unitPercent      = "%"
+	unitBytes        = "By"
+	unitMilliseconds = "ms"
+)
+
+// healthScraper converts the stats the agent collects about itself into
+// metrics. The process stats are gauges, while the operation and profiler
+// stats are the increase since the last scrape.
+type healthScraper struct {
+	processStats     func() agent.Stats
+	operationMetrics func() map[string]client.OperationMetrics
+	profilerTotals   func() map[string]float64
+
+	// lastTotals are the profiler totals from the last scrape.
+	lastTotals map[string]float64
+}
+
+func newHealthScraper() *healthScraper {
+	return &healthScraper{
+		processStats:     provider.GetLatestProcessStats,
+		operationMetrics: client.TakeOperationMetrics,
+		profilerTotals:   profiler.Profiler.GetTotals,
+		lastTotals:       make(map[string]float64),
+	}
+}
+
+func (s *healthScraper) scrape(context.Context) (pmetric.Metrics, error) {
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	now := pcommon.NewTimestampFromTime(time.Now())
+	s.addProcessMetrics(metrics, now)
+	s.addOperationMetrics(metrics, now)
+	s.addProfilerMetrics(metrics, now)
+	return md, nil
+}
+
+func (s *healthScraper) addProcessMetrics(metrics pmetric.MetricSlice, now pcommon.Timestamp) {
+	stats := s.processStats()
+	if stats.CpuPercent != nil {
+		addGauge(metrics, "CpuPercent", unitPercent, *stats.CpuPercent, now, nil)
+	}
+	if stats.MemoryBytes != nil {
+		addGauge(metrics, "MemoryBytes", unitBytes, float64(*stats.MemoryBytes), now, nil)
+	}
+	if stats.FileDescriptorCount != nil {
+		addGauge(metrics, "FileDescriptorCount", unitCount, float64(*stats.FileDescriptorCount), now, nil)
+	}
+	if stats.ThreadCount != nil {
+		addGauge(metrics, "ThreadCount", unitCount, float64(*stats.ThreadCount), now, nil)
+	}
+}
+
+func (s *healthScraper) addOperationMetrics(metrics pmetric.MetricSlice, now pcommon.Timestamp) {
+	byOperation := s.operationMetrics()
+	operations := make([]string, 0, len(byOperation))
+	for operation := range byOperation {
+		operations = append(operations, operation)
+	}
+	sort.Strings(operations)
+	for _, operation := range operations {
+		m := byOperation[operation]
+		attributes := map[string]string{attributeOperation: operation}
+		addGauge(metrics, "RequestCount", unitCount, float64(m.RequestCount), now, attributes)
+		addGauge(metrics, "ErrorCount", unitCount, float64(m.ErrorCount), now, attributes)
+		addGauge(metrics, "PayloadBytes", unitBytes, float64(m.PayloadBytes), now, attributes)
+		if m.RequestCount > 0 {
+			addGauge(metrics, "LatencyAverage", unitMilliseconds, float64(m.LatencySumMillis)/float64(m.RequestCount), now, attributes)
+			addGauge(metrics, "LatencyMaximum", unitMilliseconds, float64(m.LatencyMaxMillis), now, attributes)
+		}
+	}
+}
+
+// addProfilerMetrics reports the increase of the profiler totals, which are
+// summed by plugin and stat name, e.g. cloudwatchlogs_<group>_rawSize is
+// reported as cloudwatchlogs_rawSize, so the number of metrics does not grow
+// with the number of log groups.
+func (s *healthScraper) addProfilerMetrics(metrics pmetric.MetricSlice, now pcommon.Timestamp) {
+	totals := s.profilerTotals()
+	names := make([]string, 0, len(totals))
+	for name := range totals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		addGauge(metrics, name, unitCount, totals[name]-s.lastTotals[name], now, nil)
+	}
+	s.lastTotals = totals
+}
+
+func addGauge(metrics pmetric.MetricSlice, name, unit string, value float64, now pcommon.Timestamp, attributes map[string]string) {
+	m := metrics.AppendEmpty()
+	m.SetName(name)
+	m.SetUnit(unit)
+	dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetDoubleValue(value)
+	dp.SetTimestamp(now)
+	for k, v := range attributes {
+		dp.Attributes().PutStr(k, v)
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agenthealth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/agent"
+	"github.com/aws/amazon-cloudwatch-agent/extension/agenthealth/handler/stats/client"
+)
+
+type gauge struct {
+	unit       string
+	value      float64
+	attributes map[string]any
+}
+
+func collect(t *testing.T, md pmetric.Metrics) map[string][]gauge {
+	t.Helper()
+	got := make(map[string][]gauge)
+	metrics := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		m := metrics.At(i)
+		require.Equal(t, pmetric.MetricTypeGauge, m.Type())
+		dp := m.Gauge().DataPoints().At(0)
+		got[m.Name()] = append(got[m.Name()], gauge{unit: m.Unit(), value: dp.DoubleValue(), attributes: dp.Attributes().AsRaw()})
+	}
+	return got
+}
+
+func TestScrape(t *testing.T) {
+	totals := map[string]float64{
+		"cloudwatchlogs_rawSize": 150,
+		"cloudwatchlogs_retries": 1,
+	}
+	operations := map[string]client.OperationMetrics{
+		"PutLogEvents": {RequestCount: 4, ErrorCount: 1, LatencySumMillis: 100, LatencyMaxMillis: 40, PayloadBytes: 2048},
+	}
+	s := newHealthScraper()
+	s.processStats = func() agent.Stats {
+		return agent.Stats{CpuPercent: aws.Float64(1.5), MemoryBytes: aws.Uint64(1024), ThreadCount: aws.Int32(8)}
+	}
+	s.operationMetrics = func() map[string]client.OperationMetrics {
+		result := operations
+		operations = map[string]client.OperationMetrics{}
+		return result
+	}
+	s.profilerTotals = func() map[string]float64 {
+		result := make(map[string]float64, len(totals))
+		for k, v := range totals {
+			result[k] = v
+		}
+		return result
+	}
+
+	md, err := s.scrape(context.Background())
+	require.NoError(t, err)
+	got := collect(t, md)
+	assert.Equal(t, []gauge{{unit: "%", value: 1.5, attributes: map[string]any{}}}, got["CpuPercent"])
+	assert.Equal(t, []gauge{{unit: "By", value: 1024, attributes: map[string]any{}}}, got["MemoryBytes"])
+	assert.Equal(t, []gauge{{unit: "Count", value: 8, attributes: map[string]any{}}}, got["ThreadCount"])
+	assert.NotContains(t, got, "FileDescriptorCount")
+	operation := map[string]any{"Operation": "PutLogEvents"}
+	assert.Equal(t, []gauge{{unit: "Count", value: 4, attributes: operation}}, got["RequestCount"])
+	assert.Equal(t, []gauge{{unit: "Count", value: 1, attributes: operation}}, got["ErrorCount"])
+	assert.Equal(t, []gauge{{unit: "By", value: 2048, attributes: operation}}, got["PayloadBytes"])
+	assert.Equal(t, []gauge{{unit: "ms", value: 25, attributes: operation}}, got["LatencyAverage"])
+	assert.Equal(t, []gauge{{unit: "ms", value: 40, attributes: operation}}, got["LatencyMaximum"])
+	assert.Equal(t, 150.0, got["cloudwatchlogs_rawSize"][0].value)
+	assert.Equal(t, 1.0, got["cloudwatchlogs_retries"][0].value)
+
+	totals["cloudwatchlogs_rawSize"] = 180
+	md, err = s.scrape(context.Background())
+	require.NoError(t, err)
+	got = collect(t, md)
+	assert.NotContains(t, got, "RequestCount")
+	assert.Equal(t, 30.0, got["cloudwatchlogs_rawSize"][0].value)
+	assert.Equal(t, 0.0, got["cloudwatchlogs_retries"][0].value)
+}
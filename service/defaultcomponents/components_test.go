@@ -11,9 +11,9 @@ import (
 )
 
 const (
-	receiversCount  = 5
-	processorCount  = 8
-	exportersCount  = 5
+	receiversCount  = 6
+	processorCount  = 9
+	exportersCount  = 8
 	extensionsCount = 2
 )
 
@@ -22,11 +22,13 @@ func TestComponents(t *testing.T) {
 	assert.NoError(t, err)
 	receivers := factories.Receivers
 	assert.Len(t, receivers, receiversCount)
+	agenthealthType, _ := component.NewType("agenthealth")
 	awscontainerinsightreceiverType, _ := component.NewType("awscontainerinsightreceiver")
 	awsxrayType, _ := component.NewType("awsxray")
 	otlpType, _ := component.NewType("otlp")
 	tcplogType, _ := component.NewType("tcplog")
 	udplogType, _ := component.NewType("udplog")
+	assert.NotNil(t, receivers[agenthealthType])
 	assert.NotNil(t, receivers[awscontainerinsightreceiverType])
 	assert.NotNil(t, receivers[awsxrayType])
 	assert.NotNil(t, receivers[otlpType])
@@ -42,6 +44,7 @@ func TestComponents(t *testing.T) {
 	metricstransformType, _ := component.NewType("metricstransform")
 	transformType, _ := component.NewType("transform")
 	gpuattributesType, _ := component.NewType("gpuattributes")
+	histogramfilterType, _ := component.NewType("histogramfilter")
 	assert.NotNil(t, processors[awsapplicationsignalsType])
 	assert.NotNil(t, processors[batchType])
 	assert.NotNil(t, processors[cumulativetodeltaType])
@@ -49,6 +52,7 @@ func TestComponents(t *testing.T) {
 	assert.NotNil(t, processors[metricstransformType])
 	assert.NotNil(t, processors[transformType])
 	assert.NotNil(t, processors[gpuattributesType])
+	assert.NotNil(t, processors[histogramfilterType])
 
 	exporters := factories.Exporters
 	assert.Len(t, exporters, exportersCount)
@@ -56,15 +60,19 @@ func TestComponents(t *testing.T) {
 	awsemfType, _ := component.NewType("awsemf")
 	awscloudwatchType, _ := component.NewType("awscloudwatch")
 	loggingType, _ := component.NewType("logging")
+	otlphttpType, _ := component.NewType("otlphttp")
+	prometheusType, _ := component.NewType("prometheus")
 	assert.NotNil(t, exporters[awscloudwatchlogsType])
 	assert.NotNil(t, exporters[awsemfType])
 	assert.NotNil(t, exporters[awsemfType])
 	assert.NotNil(t, exporters[awscloudwatchType])
 	assert.NotNil(t, exporters[loggingType])
+	assert.NotNil(t, exporters[otlpType])
+	assert.NotNil(t, exporters[otlphttpType])
+	assert.NotNil(t, exporters[prometheusType])
 
 	extensions := factories.Extensions
 	assert.Len(t, extensions, extensionsCount)
-	agenthealthType, _ := component.NewType("agenthealth")
 	awsproxyType, _ := component.NewType("awsproxy")
 	assert.NotNil(t, extensions[agenthealthType])
 	assert.NotNil(t, extensions[awsproxyType])
@@ -0,0 +1,424 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cmdutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/template"
+)
+
+const (
+	// maxSuggestionDistance is the largest edit distance between an unknown
+	// key and a known key for the known key to be suggested.
+	maxSuggestionDistance = 3
+	// maxSchemaDepth guards against cyclic references in the schema.
+	maxSchemaDepth = 32
+	// strictPropertiesKeyword declares the keys that are ignored by the schema
+	// validation but known to the strict validation.
+	strictPropertiesKeyword = "strictProperties"
+)
+
+// KeyError is an unknown or misplaced key found by the strict validation
+// along with its position in the source file.
+type KeyError struct {
+	File   string
+	Line   int
+	Column int
+	// Path is the JSON path of the key, e.g. /agent/metrics_collection_interval
+	Path    string
+	Key     string
+	Message string
+}
+
+func (e *KeyError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+}
+
+// StrictValidationError contains all the key errors found in the JSON configs.
+type StrictValidationError struct {
+	Errors []*KeyError
+}
+
+func (e *StrictValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// StrictValidate reports every key in the JSON content that is not defined by
+// the schema at its location. Unlike the schema validation, this includes keys
+// in sections that allow additional properties. The file name is only used to
+// annotate the errors.
+func StrictValidate(file string, content []byte) ([]*KeyError, error) {
+	var input interface{}
+	if err := json.Unmarshal(content, &input); err != nil {
+		return nil, fmt.Errorf("unable to parse json, error: %v", err)
+	}
+	return StrictValidateResolved(file, content, input)
+}
+
+// StrictValidateResolved is StrictValidate for a JSON config whose templates
+// were resolved. The keys are looked up in the content of the file the config
+// was loaded from to annotate the errors, keys that are not in the file, e.g.
+// the keys of a block, have no position.
+func StrictValidateResolved(file string, content []byte, resolved interface{}) ([]*KeyError, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(config.GetJsonSchema()), &schema); err != nil {
+		return nil, fmt.Errorf("unable to parse json schema: %w", err)
+	}
+	positions, err := keyPositions(content)
+	if err != nil {
+		return nil, err
+	}
+	v := &strictValidator{
+		file:      file,
+		content:   content,
+		root:      schema,
+		positions: positions,
+		locations: make(map[string][]string),
+	}
+	v.indexLocations("", []map[string]interface{}{schema}, 0)
+	v.walk("", resolved, []map[string]interface{}{schema}, 0)
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		if v.errs[i].Line != v.errs[j].Line {
+			return v.errs[i].Line < v.errs[j].Line
+		}
+		return v.errs[i].Column < v.errs[j].Column
+	})
+	return v.errs, nil
+}
+
+type strictValidator struct {
+	file    string
+	content []byte
+	root    map[string]interface{}
+	// positions are the byte offsets of each key by JSON path.
+	positions map[string]int
+	// locations are the schema paths where each key is defined. Dynamic
+	// keys and array items are represented by a *.
+	locations map[string][]string
+	errs      []*KeyError
+}
+
+// walk checks the keys of every object in the value against the schema nodes
+// that apply to it.
+func (v *strictValidator) walk(path string, value interface{}, nodes []map[string]interface{}, depth int) {
+	nodes = v.resolve(nodes, depth)
+	if len(nodes) == 0 {
+		return
+	}
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		properties, additional, open := objectSchema(nodes)
+		for key, child := range typed {
+			// template directives are resolved before the config is merged
+			if key == template.BlockRefKey || key == template.ConditionKey {
+				continue
+			}
+			childPath := path + "/" + key
+			if childNodes, ok := properties[key]; ok {
+				v.walk(childPath, child, childNodes, depth+1)
+			} else if len(additional) > 0 {
+				v.walk(childPath, child, additional, depth+1)
+			} else if !open {
+				v.addError(path, key, properties)
+			}
+		}
+	case []interface{}:
+		for i, child := range typed {
+			v.walk(path+"/"+strconv.Itoa(i), child, itemSchemas(nodes, i), depth+1)
+		}
+	}
+}
+
+// resolve follows the references and flattens the combined schemas so that
+// each returned node can be checked directly.
+func (v *strictValidator) resolve(nodes []map[string]interface{}, depth int) []map[string]interface{} {
+	if depth > maxSchemaDepth {
+		return nil
+	}
+	var resolved []map[string]interface{}
+	for _, node := range nodes {
+		if ref, ok := node["$ref"].(string); ok {
+			if target := v.lookup(ref); target != nil {
+				resolved = append(resolved, v.resolve([]map[string]interface{}{target}, depth+1)...)
+			}
+		}
+		resolved = append(resolved, node)
+		for _, combinator := range []string{"allOf", "anyOf", "oneOf"} {
+			if subschemas, ok := node[combinator].([]interface{}); ok {
+				resolved = append(resolved, v.resolve(toSchemas(subschemas), depth+1)...)
+			}
+		}
+	}
+	return resolved
+}
+
+// lookup finds the schema for a local reference, e.g. #/definitions/agentDefinition
+func (v *strictValidator) lookup(ref string) map[string]interface{} {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+	node := v.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		next, ok := node[part].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// indexLocations records where each key is defined in the schema so that
+// misplaced keys can be pointed to the right section.
+func (v *strictValidator) indexLocations(path string, nodes []map[string]interface{}, depth int) {
+	nodes = v.resolve(nodes, depth)
+	if len(nodes) == 0 {
+		return
+	}
+	properties, additional, _ := objectSchema(nodes)
+	for key, childNodes := range properties {
+		if location := v.locations[key]; !contains(location, path) {
+			v.locations[key] = append(location, path)
+		}
+		v.indexLocations(path+"/"+key, childNodes, depth+1)
+	}
+	if len(additional) > 0 {
+		v.indexLocations(path+"/*", additional, depth+1)
+	}
+	if items := itemSchemas(nodes, 0); len(items) > 0 {
+		v.indexLocations(path+"/*", items, depth+1)
+	}
+}
+
+func (v *strictValidator) addError(path, key string, properties map[string][]map[string]interface{}) {
+	keyPath := path + "/" + key
+	var line, column int
+	if position, ok := v.positions[keyPath]; ok {
+		line, column = lineAndColumn(v.content, position)
+	}
+	section := path
+	if section == "" {
+		section = "/"
+	}
+	message := fmt.Sprintf("unknown key %q in %s", key, section)
+	if suggestion := closestKey(key, properties); suggestion != "" {
+		message = fmt.Sprintf("%s, did you mean %q?", message, suggestion)
+	} else if locations := v.locations[key]; len(locations) > 0 {
+		message = fmt.Sprintf("%s, the key is only valid in %s", message, strings.Join(formatLocations(locations), ", "))
+	}
+	v.errs = append(v.errs, &KeyError{
+		File:    v.file,
+		Line:    line,
+		Column:  column,
+		Path:    keyPath,
+		Key:     key,
+		Message: message,
+	})
+}
+
+// objectSchema merges the object keywords of the nodes. A node is open if it
+// accepts any key without declaring properties, e.g. a free-form object. The
+// strictProperties keyword declares keys that are only known to the strict
+// validation, so that declaring them does not reject configs that the schema
+// validation accepted before.
+func objectSchema(nodes []map[string]interface{}) (properties map[string][]map[string]interface{}, additional []map[string]interface{}, open bool) {
+	properties = make(map[string][]map[string]interface{})
+	for _, node := range nodes {
+		hasProperties := false
+		for _, keyword := range []string{"properties", strictPropertiesKeyword} {
+			declared, ok := node[keyword].(map[string]interface{})
+			hasProperties = hasProperties || ok
+			for key, child := range declared {
+				if schema, ok := child.(map[string]interface{}); ok {
+					properties[key] = append(properties[key], schema)
+				}
+			}
+		}
+		switch additionalProperties := node["additionalProperties"].(type) {
+		case map[string]interface{}:
+			additional = append(additional, additionalProperties)
+		case bool:
+			if additionalProperties && !hasProperties {
+				open = true
+			}
+		case nil:
+			if node["type"] == "object" && !hasProperties {
+				open = true
+			}
+		}
+	}
+	return properties, additional, open
+}
+
+// itemSchemas returns the schemas for the array item at the index.
+func itemSchemas(nodes []map[string]interface{}, index int) []map[string]interface{} {
+	var items []map[string]interface{}
+	for _, node := range nodes {
+		switch typed := node["items"].(type) {
+		case map[string]interface{}:
+			items = append(items, typed)
+		case []interface{}:
+			if index < len(typed) {
+				if schema, ok := typed[index].(map[string]interface{}); ok {
+					items = append(items, schema)
+				}
+			}
+		}
+	}
+	return items
+}
+
+func toSchemas(values []interface{}) []map[string]interface{} {
+	var schemas []map[string]interface{}
+	for _, value := range values {
+		if schema, ok := value.(map[string]interface{}); ok {
+			schemas = append(schemas, schema)
+		}
+	}
+	return schemas
+}
+
+// keyPositions returns the byte offset of every object key by its JSON path.
+func keyPositions(content []byte) (map[string]int, error) {
+	positions := make(map[string]int)
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	if err := scanValue(decoder, content, "", positions); err != nil {
+		return nil, fmt.Errorf("unable to parse json, error: %v", err)
+	}
+	return positions, nil
+}
+
+func scanValue(decoder *json.Decoder, content []byte, path string, positions map[string]int) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return nil
+	}
+	switch delim {
+	case '{':
+		for decoder.More() {
+			token, err = decoder.Token()
+			if err != nil {
+				return err
+			}
+			key, ok := token.(string)
+			if !ok {
+				return errors.New("invalid object key")
+			}
+			keyPath := path + "/" + key
+			positions[keyPath] = keyStart(content, int(decoder.InputOffset()))
+			if err = scanValue(decoder, content, keyPath, positions); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for i := 0; decoder.More(); i++ {
+			if err = scanValue(decoder, content, path+"/"+strconv.Itoa(i), positions); err != nil {
+				return err
+			}
+		}
+	}
+	// consume the closing delimiter
+	if _, err = decoder.Token(); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// keyStart finds the opening quote of the key that ends at the offset.
+func keyStart(content []byte, end int) int {
+	for i := end - 2; i >= 0; i-- {
+		if content[i] == '"' && (i == 0 || content[i-1] != '\\') {
+			return i
+		}
+	}
+	return 0
+}
+
+// lineAndColumn converts a byte offset to a 1-based line and column.
+func lineAndColumn(content []byte, offset int) (int, int) {
+	if offset > len(content) {
+		offset = len(content)
+	}
+	line := bytes.Count(content[:offset], []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(content[:offset], '\n')
+	return line, column
+}
+
+// closestKey returns the known key with the smallest edit distance to the
+// unknown key if it is close enough to be a likely typo.
+func closestKey(key string, properties map[string][]map[string]interface{}) string {
+	var closest string
+	best := maxSuggestionDistance + 1
+	for candidate := range properties {
+		distance := levenshtein(key, candidate)
+		if distance < best || (distance == best && candidate < closest) {
+			closest, best = candidate, distance
+		}
+	}
+	if best > maxSuggestionDistance || best >= len(key) {
+		return ""
+	}
+	return closest
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func formatLocations(locations []string) []string {
+	formatted := make([]string, len(locations))
+	for i, location := range locations {
+		if location == "" {
+			location = "/"
+		}
+		formatted[i] = location
+	}
+	sort.Strings(formatted)
+	return formatted
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
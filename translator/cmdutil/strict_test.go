@@ -0,0 +1,115 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cmdutil
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+)
+
+func TestStrictValidate(t *testing.T) {
+	content, err := os.ReadFile("testdata/strict.json")
+	require.NoError(t, err)
+	errs, err := StrictValidate("strict.json", content)
+	require.NoError(t, err)
+	require.Len(t, errs, 3)
+
+	assert.Equal(t, "/agent/metrics_collection_intervall", errs[0].Path)
+	assert.Equal(t, `strict.json:3:5: unknown key "metrics_collection_intervall" in /agent, did you mean "metrics_collection_interval"?`, errs[0].Error())
+
+	assert.Equal(t, "/logs/metrics_collection_interval", errs[1].Path)
+	assert.Equal(t, 19, errs[1].Line)
+	assert.Equal(t, 5, errs[1].Column)
+	assert.Contains(t, errs[1].Message, "the key is only valid in /agent, ")
+
+	assert.Equal(t, "/logs/logs_collected/files/collect_list/0/log_group_nme", errs[2].Path)
+	assert.Equal(t, 25, errs[2].Line)
+	assert.Equal(t, 13, errs[2].Column)
+	assert.Contains(t, errs[2].Message, `did you mean "log_group_name"?`)
+}
+
+// TestStrictValidateStrictProperties checks that the keys declared only for
+// the strict validation are known to it, including their nested keys.
+func TestStrictValidateStrictProperties(t *testing.T) {
+	errs, err := StrictValidate("strict.json", []byte(`{
+  "agent": {"run_as_user": "cwagent", "usage_data": false},
+  "logs": {
+    "metrics_collected": {
+      "emf": {"service_address": "udp://127.0.0.1:25888"},
+      "kubernetes": {"tag_service": true},
+      "application_signals": {"tls": {"cert_file": "cert.pem", "key_fle": "key.pem"}}
+    }
+  }
+}`))
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "/logs/metrics_collected/application_signals/tls/key_fle", errs[0].Path)
+}
+
+func TestStrictValidateInvalidJson(t *testing.T) {
+	_, err := StrictValidate("invalid.json", []byte(`{"agent": `))
+	assert.Error(t, err)
+}
+
+// TestStrictValidateIncludedFragments validates the fragments after the
+// templates are resolved, so the included fragments and blocks are checked.
+func TestStrictValidateIncludedFragments(t *testing.T) {
+	dir := t.TempDir()
+	inputDir := filepath.Join(dir, "json")
+	require.NoError(t, os.Mkdir(inputDir, 0755))
+	main := filepath.Join(inputDir, "main.json.tmp")
+	included := filepath.Join(dir, "included.json")
+	require.NoError(t, os.WriteFile(main, []byte(`{
+  "include": ["../included.json"],
+  "blocks": {"agent": {"metrics_collection_intervall": 60}},
+  "agent": {"$block": "agent"}
+}`), 0644))
+	require.NoError(t, os.WriteFile(included, []byte(`{
+  "logs": {
+    "force_flush_intervall": 5
+  }
+}`), 0644))
+
+	context.ResetContext()
+	defer context.ResetContext()
+	ctx := context.CurrentContext()
+	ctx.SetOs("linux")
+	ctx.SetMultiConfig("default")
+	ctx.SetInputJsonDirPath(inputDir)
+	ctx.SetStrictValidation(true)
+
+	_, err := GenerateMergedJsonConfigMap(ctx)
+	var strictErr *StrictValidationError
+	require.True(t, errors.As(err, &strictErr), "unexpected error %v", err)
+	require.Len(t, strictErr.Errors, 2)
+	assert.Equal(t, included, strictErr.Errors[0].File)
+	assert.Equal(t, "/logs/force_flush_intervall", strictErr.Errors[0].Path)
+	assert.Equal(t, 3, strictErr.Errors[0].Line)
+	// the key of the block has no position in the file that uses it
+	assert.Equal(t, main, strictErr.Errors[1].File)
+	assert.Equal(t, "/agent/metrics_collection_intervall", strictErr.Errors[1].Path)
+	assert.Equal(t, 0, strictErr.Errors[1].Line)
+}
+
+func TestStrictValidationError(t *testing.T) {
+	err := &StrictValidationError{Errors: []*KeyError{
+		{File: "a.json", Line: 1, Column: 2, Message: "first"},
+		{File: "b.json", Line: 3, Column: 4, Message: "second"},
+	}}
+	assert.Equal(t, "a.json:1:2: first\nb.json:3:4: second", err.Error())
+}
+
+func TestLevenshtein(t *testing.T) {
+	assert.Equal(t, 0, levenshtein("region", "region"))
+	assert.Equal(t, 2, levenshtein("regoin", "region"))
+	assert.Equal(t, 6, levenshtein("", "region"))
+	assert.Equal(t, 3, levenshtein("kitten", "sitting"))
+}
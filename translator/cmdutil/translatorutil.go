@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/xeipuuv/gojsonschema"
@@ -18,6 +19,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator/config"
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
 	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig"
+	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/template"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/registerrules"
 	"github.com/aws/amazon-cloudwatch-agent/translator/tocwconfig/toenvconfig"
 	"github.com/aws/amazon-cloudwatch-agent/translator/tocwconfig/totomlconfig"
@@ -109,6 +111,10 @@ func GenerateMergedJsonConfigMap(ctx *context.Context) (map[string]interface{},
 	// for the append operation when the existing file name and new .tmp file name have diff
 	// only for the ".tmp" suffix, i.e. it is override operation even it says append.
 	var jsonConfigMapMap = make(map[string]map[string]interface{})
+	// strict validation runs on each resolved fragment, so the errors can point
+	// to the position of the key in the file it came from.
+	sources := make(map[string]string)
+	contents := make(map[string][]byte)
 
 	if ctx.MultiConfig() == "append" || ctx.MultiConfig() == "remove" {
 		// backwards compatible for the old json config file
@@ -119,6 +125,7 @@ func GenerateMergedJsonConfigMap(ctx *context.Context) (map[string]interface{},
 		}
 		if jsonConfigMap != nil {
 			jsonConfigMapMap[ctx.InputJsonFilePath()] = jsonConfigMap
+			sources[ctx.InputJsonFilePath()] = ctx.InputJsonFilePath()
 		}
 	}
 
@@ -158,7 +165,9 @@ func GenerateMergedJsonConfigMap(ctx *context.Context) (map[string]interface{},
 						return err
 					}
 					if jsonConfigMap != nil {
-						jsonConfigMapMap[strings.TrimSuffix(path, context.TmpFileSuffix)] = jsonConfigMap
+						key := strings.TrimSuffix(path, context.TmpFileSuffix)
+						jsonConfigMapMap[key] = jsonConfigMap
+						sources[key] = path
 					}
 				}
 			} else {
@@ -171,6 +180,7 @@ func GenerateMergedJsonConfigMap(ctx *context.Context) (map[string]interface{},
 					if jsonConfigMap != nil {
 						if _, ok := jsonConfigMapMap[path]; !ok {
 							jsonConfigMapMap[path] = jsonConfigMap
+							sources[path] = path
 						}
 					}
 				}
@@ -191,6 +201,17 @@ func GenerateMergedJsonConfigMap(ctx *context.Context) (map[string]interface{},
 				return nil, fmt.Errorf("unable to get json map from environment variable %v with error: %v", config.CWConfigContent, err)
 			}
 			jsonConfigMapMap[config.CWConfigContent] = jm
+			contents[config.CWConfigContent] = []byte(jsonConfigContent)
+		}
+	}
+
+	if err = template.NewResolver(ctx.Os(), ctx.Mode()).Resolve(jsonConfigMapMap); err != nil {
+		return nil, fmt.Errorf("unable to resolve json config templates: %w", err)
+	}
+
+	if ctx.StrictValidation() {
+		if err = strictValidateFragments(jsonConfigMapMap, sources, contents); err != nil {
+			return nil, err
 		}
 	}
 
@@ -208,6 +229,40 @@ func GenerateMergedJsonConfigMap(ctx *context.Context) (map[string]interface{},
 	return mergedJsonConfigMap, nil
 }
 
+// strictValidateFragments runs the strict validation on each resolved fragment,
+// including the fragments included by other fragments, which are keyed by the
+// path of their file.
+func strictValidateFragments(jsonConfigMapMap map[string]map[string]interface{}, sources map[string]string, contents map[string][]byte) error {
+	paths := make([]string, 0, len(jsonConfigMapMap))
+	for path := range jsonConfigMapMap {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var keyErrors []*KeyError
+	for _, path := range paths {
+		source, ok := sources[path]
+		if !ok {
+			source = path
+		}
+		content, ok := contents[path]
+		if !ok {
+			var err error
+			if content, err = os.ReadFile(source); err != nil {
+				return err
+			}
+		}
+		errs, err := StrictValidateResolved(source, content, jsonConfigMapMap[path])
+		if err != nil {
+			return err
+		}
+		keyErrors = append(keyErrors, errs...)
+	}
+	if len(keyErrors) > 0 {
+		return &StrictValidationError{Errors: keyErrors}
+	}
+	return nil
+}
+
 func TranslateJsonMapToTomlConfig(jsonConfigValue interface{}) (interface{}, error) {
 	r := new(translate.Translator)
 	_, val := r.ApplyRule(jsonConfigValue)
@@ -233,6 +288,12 @@ func TranslateJsonMapToYamlConfig(jsonConfigValue interface{}) (interface{}, err
 	return result, nil
 }
 
+// ExplainJsonMapToYamlConfig describes the OTEL pipelines that the JSON config
+// is translated into without building the YAML config.
+func ExplainJsonMapToYamlConfig(jsonConfigValue interface{}) (*otel.Explanation, error) {
+	return otel.Explain(jsonConfigValue, context.CurrentContext().Os())
+}
+
 func ConfigToTomlFile(config interface{}, tomlConfigFilePath string) error {
 	res := totomlconfig.ToTomlConfig(config)
 	return os.WriteFile(tomlConfigFilePath, []byte(res), fileMode)
@@ -28,3 +28,18 @@ const (
 	ShortModeK8sEC2    = "K8E"
 	ShortModeK8sOnPrem = "K8OP"
 )
+
+// ShortMode returns the short mode of the agent mode, which is the same for the
+// aliases of a mode like onPrem and onPremise. Unknown modes are returned as is.
+func ShortMode(mode string) string {
+	switch mode {
+	case ModeEC2:
+		return ShortModeEC2
+	case ModeOnPrem, ModeOnPremise:
+		return ShortModeOnPrem
+	case ModeWithIRSA:
+		return ShortModeWithIRSA
+	default:
+		return mode
+	}
+}
@@ -49,6 +49,7 @@ type Context struct {
 	cloudWatchLogConfig map[string]interface{}
 	runInContainer      bool
 	agentLogFile        string
+	strictValidation    bool
 }
 
 func (ctx *Context) Os() string {
@@ -188,3 +189,11 @@ func (ctx *Context) GetAgentLogFile() string {
 func (ctx *Context) SetAgentLogFile(agentLogFile string) {
 	ctx.agentLogFile = agentLogFile
 }
+
+func (ctx *Context) StrictValidation() bool {
+	return ctx.strictValidation
+}
+
+func (ctx *Context) SetStrictValidation(strictValidation bool) {
+	ctx.strictValidation = strictValidation
+}
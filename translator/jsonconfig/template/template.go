@@ -0,0 +1,468 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package template
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/internal/retryer"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	"github.com/aws/amazon-cloudwatch-agent/translator/util"
+)
+
+const (
+	// IncludeKey is the top-level key with the paths of other JSON config
+	// fragments to load. Relative paths are resolved from the directory of
+	// the fragment that includes them.
+	IncludeKey = "include"
+	// VariablesKey is the top-level key with the variables that can be used
+	// in any string value of any fragment with {{name}}. The strings are only
+	// interpolated if a fragment declares variables, so configs without
+	// variables can use {{...}} literally.
+	VariablesKey = "variables"
+	// BlocksKey is the top-level key with the named blocks that can be
+	// reused in any fragment with {"$block": "name"}.
+	BlocksKey = "blocks"
+
+	// BlockRefKey replaces the object it is in with the named block. Other
+	// keys in the object override the keys in the block.
+	BlockRefKey = "$block"
+	// ConditionKey removes the object it is in unless the conditions match.
+	// The mode condition matches the aliases of the mode, e.g. onPrem and
+	// onPremise.
+	ConditionKey = "$if"
+
+	conditionOs   = "os"
+	conditionMode = "mode"
+
+	variableEnv     = "env"
+	variableImds    = "imds"
+	variableTag     = "tag"
+	variableDefault = "default"
+
+	imdsTagPrefix = "tags/instance/"
+	// maxDepth guards against cyclic block and variable references.
+	maxDepth = 32
+)
+
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+// Resolver expands the includes, blocks, conditions and variables in the JSON
+// config fragments before they are merged.
+type Resolver struct {
+	os   string
+	mode string
+	// getenv looks up environment variables.
+	getenv func(string) (string, bool)
+	// metadata looks up a path in the instance metadata service.
+	metadata func(string) (string, error)
+
+	definitions map[string]interface{}
+	variables   map[string]string
+	blocks      map[string]interface{}
+	// interpolate is whether any fragment declares variables.
+	interpolate bool
+}
+
+// NewResolver creates a resolver that evaluates conditions against the os and
+// mode and looks up variables in the environment and instance metadata.
+func NewResolver(os, mode string) *Resolver {
+	return &Resolver{
+		os:       os,
+		mode:     mode,
+		getenv:   lookupEnv,
+		metadata: imdsMetadata,
+	}
+}
+
+// Resolve expands the templates in each of the JSON config fragments in place.
+// The included fragments are added to the map by their paths. Variables and
+// blocks are shared by all fragments, so a fragment can use the ones defined
+// in another.
+func (r *Resolver) Resolve(jsonConfigMapMap map[string]map[string]interface{}) error {
+	if err := r.loadIncludes(jsonConfigMapMap); err != nil {
+		return err
+	}
+	r.definitions = make(map[string]interface{})
+	r.variables = make(map[string]string)
+	r.blocks = make(map[string]interface{})
+	r.interpolate = false
+	for _, path := range sortedPaths(jsonConfigMapMap) {
+		fragment := jsonConfigMapMap[path]
+		if _, ok := fragment[VariablesKey]; ok {
+			r.interpolate = true
+		}
+		if err := collect(r.definitions, fragment[VariablesKey], VariablesKey, path); err != nil {
+			return err
+		}
+		if err := collect(r.blocks, fragment[BlocksKey], BlocksKey, path); err != nil {
+			return err
+		}
+		delete(fragment, VariablesKey)
+		delete(fragment, BlocksKey)
+	}
+	for _, path := range sortedPaths(jsonConfigMapMap) {
+		resolved, err := r.resolveValue(jsonConfigMapMap[path], 0)
+		if err != nil {
+			return fmt.Errorf("unable to resolve %v: %w", path, err)
+		}
+		fragment, _ := resolved.(map[string]interface{})
+		if fragment == nil {
+			fragment = map[string]interface{}{}
+		}
+		jsonConfigMapMap[path] = fragment
+	}
+	return nil
+}
+
+// loadIncludes adds the fragments included by other fragments to the map.
+// Fragments that are already in the map are only loaded once.
+func (r *Resolver) loadIncludes(jsonConfigMapMap map[string]map[string]interface{}) error {
+	pending := sortedPaths(jsonConfigMapMap)
+	for len(pending) > 0 {
+		path := pending[0]
+		pending = pending[1:]
+		fragment := jsonConfigMapMap[path]
+		includes, ok := fragment[IncludeKey]
+		if !ok {
+			continue
+		}
+		delete(fragment, IncludeKey)
+		paths, err := toStrings(includes)
+		if err != nil {
+			return fmt.Errorf("invalid %s in %v: %w", IncludeKey, path, err)
+		}
+		for _, include := range paths {
+			if !filepath.IsAbs(include) {
+				include = filepath.Join(filepath.Dir(path), include)
+			}
+			include = filepath.Clean(include)
+			if _, ok := jsonConfigMapMap[include]; ok {
+				continue
+			}
+			log.Printf("I! Including json config file %v from %v", include, path)
+			included, err := util.GetJsonMapFromFile(include)
+			if err != nil {
+				return fmt.Errorf("unable to include %v from %v: %w", include, path, err)
+			}
+			jsonConfigMapMap[include] = included
+			pending = append(pending, include)
+		}
+	}
+	return nil
+}
+
+// resolveValue evaluates the conditions and block references in the value
+// and replaces the variables in its strings.
+func (r *Resolver) resolveValue(value interface{}, depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, errors.New("maximum template depth exceeded, check for cyclic block references")
+	}
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		if ok, err := r.matches(typed); err != nil || !ok {
+			return nil, err
+		}
+		if _, ok := typed[BlockRefKey]; ok {
+			expanded, err := r.expandBlock(typed)
+			if err != nil {
+				return nil, err
+			}
+			return r.resolveValue(expanded, depth+1)
+		}
+		result := make(map[string]interface{}, len(typed))
+		for key, child := range typed {
+			resolved, err := r.resolveValue(child, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if resolved != nil || child == nil {
+				result[key] = resolved
+			}
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, 0, len(typed))
+		for _, child := range typed {
+			// a block of a list used as a list entry is spliced into the list
+			if ref, ok := child.(map[string]interface{}); ok && len(ref) == 1 {
+				if name, ok := ref[BlockRefKey].(string); ok {
+					if entries, ok := r.blocks[name].([]interface{}); ok {
+						resolved, err := r.resolveValue(deepCopy(entries), depth+1)
+						if err != nil {
+							return nil, err
+						}
+						result = append(result, resolved.([]interface{})...)
+						continue
+					}
+				}
+			}
+			resolved, err := r.resolveValue(child, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			if resolved != nil || child == nil {
+				result = append(result, resolved)
+			}
+		}
+		return result, nil
+	case string:
+		if !r.interpolate {
+			return typed, nil
+		}
+		return r.replaceVariables(typed, depth)
+	default:
+		return value, nil
+	}
+}
+
+// matches evaluates the conditions in the object. The condition key is
+// removed from the object if it matches.
+func (r *Resolver) matches(object map[string]interface{}) (bool, error) {
+	raw, ok := object[ConditionKey]
+	if !ok {
+		return true, nil
+	}
+	conditions, ok := raw.(map[string]interface{})
+	if !ok {
+		return false, fmt.Errorf("invalid %s, must be an object", ConditionKey)
+	}
+	for key, expected := range conditions {
+		var actual string
+		switch key {
+		case conditionOs:
+			actual = r.os
+		case conditionMode:
+			actual = r.mode
+		default:
+			return false, fmt.Errorf("invalid %s condition %q, valid conditions: %s, %s", ConditionKey, key, conditionOs, conditionMode)
+		}
+		values, err := toStrings(expected)
+		if err != nil {
+			return false, fmt.Errorf("invalid %s condition %q: %w", ConditionKey, key, err)
+		}
+		if key == conditionMode {
+			// the aliases of a mode, like onPrem and onPremise, match each other
+			actual = config.ShortMode(actual)
+			for i, value := range values {
+				values[i] = config.ShortMode(value)
+			}
+		}
+		if !contains(values, actual) {
+			return false, nil
+		}
+	}
+	delete(object, ConditionKey)
+	return true, nil
+}
+
+// expandBlock replaces the block reference with a copy of the block. The
+// other keys in the reference override the ones in the block.
+func (r *Resolver) expandBlock(ref map[string]interface{}) (interface{}, error) {
+	name, ok := ref[BlockRefKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s, must be a string", BlockRefKey)
+	}
+	block, ok := r.blocks[name]
+	if !ok {
+		return nil, fmt.Errorf("undefined block %q", name)
+	}
+	block = deepCopy(block)
+	if len(ref) == 1 {
+		return block, nil
+	}
+	object, ok := block.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("block %q is not an object and cannot be overridden", name)
+	}
+	for key, value := range ref {
+		if key != BlockRefKey {
+			object[key] = deepCopy(value)
+		}
+	}
+	return object, nil
+}
+
+// replaceVariables replaces the {{name}} variables in the string.
+func (r *Resolver) replaceVariables(value string, depth int) (string, error) {
+	var errs error
+	result := variablePattern.ReplaceAllStringFunc(value, func(match string) string {
+		name := variablePattern.FindStringSubmatch(match)[1]
+		resolved, err := r.variable(name, depth+1)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			return match
+		}
+		return resolved
+	})
+	return result, errs
+}
+
+// variable resolves the variable by name. The definition is either a string,
+// which can use other variables, or an object with the source to look it up
+// from and an optional default.
+func (r *Resolver) variable(name string, depth int) (string, error) {
+	if value, ok := r.variables[name]; ok {
+		return value, nil
+	}
+	if depth > maxDepth {
+		return "", fmt.Errorf("maximum template depth exceeded resolving variable %q, check for cyclic variable references", name)
+	}
+	definition, ok := r.definitions[name]
+	if !ok {
+		return "", fmt.Errorf("undefined variable %q", name)
+	}
+	var value string
+	switch typed := definition.(type) {
+	case string:
+		resolved, err := r.replaceVariables(typed, depth)
+		if err != nil {
+			return "", err
+		}
+		value = resolved
+	case map[string]interface{}:
+		resolved, err := r.lookup(name, typed)
+		if err != nil {
+			return "", err
+		}
+		value = resolved
+	default:
+		value = fmt.Sprintf("%v", typed)
+	}
+	r.variables[name] = value
+	return value, nil
+}
+
+// lookup finds the value of the variable from its source.
+func (r *Resolver) lookup(name string, definition map[string]interface{}) (string, error) {
+	var found bool
+	var value string
+	var err error
+	if key, ok := definition[variableEnv].(string); ok {
+		value, found = r.getenv(key)
+	} else if path, ok := definition[variableImds].(string); ok {
+		value, err = r.metadata(path)
+		found = err == nil
+	} else if key, ok := definition[variableTag].(string); ok {
+		value, err = r.metadata(imdsTagPrefix + key)
+		found = err == nil
+	} else if _, ok = definition[variableDefault]; !ok {
+		return "", fmt.Errorf("invalid variable %q, must set one of %s, %s, %s or %s", name, variableEnv, variableImds, variableTag, variableDefault)
+	}
+	if found {
+		return value, nil
+	}
+	if defaultValue, ok := definition[variableDefault]; ok {
+		return fmt.Sprintf("%v", defaultValue), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve variable %q: %w", name, err)
+	}
+	return "", fmt.Errorf("unable to resolve variable %q", name)
+}
+
+// collect adds the named definitions from a fragment. The same name can be
+// defined in multiple fragments as long as the definitions are the same.
+func collect(definitions map[string]interface{}, section interface{}, sectionKey, path string) error {
+	if section == nil {
+		return nil
+	}
+	named, ok := section.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("invalid %s in %v, must be an object", sectionKey, path)
+	}
+	for name, definition := range named {
+		if existing, ok := definitions[name]; ok && !reflect.DeepEqual(existing, definition) {
+			return fmt.Errorf("conflicting definitions of %q in %s, found another in %v", name, sectionKey, path)
+		}
+		definitions[name] = definition
+	}
+	return nil
+}
+
+func deepCopy(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(typed))
+		for key, child := range typed {
+			result[key] = deepCopy(child)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(typed))
+		for i, child := range typed {
+			result[i] = deepCopy(child)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+func toStrings(value interface{}) ([]string, error) {
+	switch typed := value.(type) {
+	case string:
+		return []string{typed}, nil
+	case []interface{}:
+		values := make([]string, len(typed))
+		for i, entry := range typed {
+			s, ok := entry.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a string, found %v", entry)
+			}
+			values[i] = s
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("expected a string or a list of strings, found %v", value)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedPaths(jsonConfigMapMap map[string]map[string]interface{}) []string {
+	paths := make([]string, 0, len(jsonConfigMapMap))
+	for path := range jsonConfigMapMap {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func lookupEnv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+func imdsMetadata(path string) (string, error) {
+	ses, err := session.NewSession()
+	if err != nil {
+		return "", err
+	}
+	md := ec2metadata.New(ses, &aws.Config{
+		LogLevel: configaws.SDKLogLevel(),
+		Logger:   configaws.SDKLogger{},
+		Retryer:  retryer.NewIMDSRetryer(retryer.GetDefaultRetryNumber()),
+	})
+	return md.GetMetadata(path)
+}
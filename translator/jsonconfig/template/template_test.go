@@ -0,0 +1,203 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package template
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/util"
+)
+
+func newTestResolver(os, mode string) *Resolver {
+	r := NewResolver(os, mode)
+	r.getenv = func(key string) (string, bool) {
+		if key == "ROLE" {
+			return "web", true
+		}
+		return "", false
+	}
+	r.metadata = func(path string) (string, error) {
+		switch path {
+		case "placement/availability-zone":
+			return "us-east-1a", nil
+		case "tags/instance/Team":
+			return "payments", nil
+		}
+		return "", errors.New("not found")
+	}
+	return r
+}
+
+func TestResolve(t *testing.T) {
+	fragment, err := util.GetJsonMapFromJsonBytes([]byte(`{
+		"include": "testdata/shared.json",
+		"variables": {
+			"role": {"env": "ROLE"},
+			"az": {"imds": "placement/availability-zone"},
+			"team": {"tag": "Team"},
+			"stage": {"env": "STAGE", "default": "prod"}
+		},
+		"metrics": {
+			"append_dimensions": {
+				"Team": "{{team}}",
+				"AvailabilityZone": "{{ az }}",
+				"Stage": "{{stage}}"
+			},
+			"metrics_collected": {
+				"cpu": {"measurement": ["usage_idle"]},
+				"LogicalDisk": {"$if": {"os": "windows"}, "measurement": ["% Free Space"]},
+				"disk": {"$if": {"os": ["linux", "darwin"]}, "measurement": ["used_percent"]}
+			}
+		},
+		"logs": {
+			"logs_collected": {
+				"files": {
+					"collect_list": [
+						{"$block": "syslog", "log_stream_name": "{instance_id}"},
+						{"$block": "common_logs"},
+						{"$if": {"mode": "onPremise"}, "file_path": "/var/log/onprem.log"}
+					]
+				}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+	path := filepath.Join(".", "fragment.json")
+	jsonConfigMapMap := map[string]map[string]interface{}{path: fragment}
+
+	require.NoError(t, newTestResolver("linux", "ec2").Resolve(jsonConfigMapMap))
+	require.Len(t, jsonConfigMapMap, 2)
+	assert.Equal(t, map[string]interface{}{}, jsonConfigMapMap[filepath.Join("testdata", "shared.json")])
+
+	got := jsonConfigMapMap[path]
+	assert.NotContains(t, got, IncludeKey)
+	assert.NotContains(t, got, VariablesKey)
+	assert.NotContains(t, got, BlocksKey)
+	metrics := got["metrics"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"Team":             "payments",
+		"AvailabilityZone": "us-east-1a",
+		"Stage":            "prod",
+	}, metrics["append_dimensions"])
+	assert.Equal(t, map[string]interface{}{
+		"cpu":  map[string]interface{}{"measurement": []interface{}{"usage_idle"}},
+		"disk": map[string]interface{}{"measurement": []interface{}{"used_percent"}},
+	}, metrics["metrics_collected"])
+	collectList := got["logs"].(map[string]interface{})["logs_collected"].(map[string]interface{})["files"].(map[string]interface{})["collect_list"]
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"file_path":       "/var/log/syslog",
+			"log_group_name":  "/fleet/web/syslog",
+			"log_stream_name": "{instance_id}",
+		},
+		map[string]interface{}{
+			"file_path":      "/var/log/messages",
+			"log_group_name": "/fleet/web/messages",
+		},
+	}, collectList)
+}
+
+func TestResolveErrors(t *testing.T) {
+	testCases := map[string]struct {
+		fragments map[string]string
+		wantErr   string
+	}{
+		"WithUndefinedVariable": {
+			fragments: map[string]string{"a": `{"variables": {}, "agent": {"region": "{{region}}"}}`},
+			wantErr:   `undefined variable "region"`,
+		},
+		"WithUnresolvedVariable": {
+			fragments: map[string]string{"a": `{"variables": {"team": {"tag": "Missing"}}, "agent": {"region": "{{team}}"}}`},
+			wantErr:   `unable to resolve variable "team": not found`,
+		},
+		"WithCyclicVariables": {
+			fragments: map[string]string{"a": `{"variables": {"a": "{{b}}", "b": "{{a}}"}, "agent": {"region": "{{a}}"}}`},
+			wantErr:   "cyclic variable references",
+		},
+		"WithUndefinedBlock": {
+			fragments: map[string]string{"a": `{"agent": {"$block": "missing"}}`},
+			wantErr:   `undefined block "missing"`,
+		},
+		"WithCyclicBlocks": {
+			fragments: map[string]string{"a": `{"blocks": {"a": {"$block": "b"}, "b": {"$block": "a"}}, "agent": {"$block": "a"}}`},
+			wantErr:   "cyclic block references",
+		},
+		"WithConflictingVariables": {
+			fragments: map[string]string{
+				"a": `{"variables": {"role": "web"}}`,
+				"b": `{"variables": {"role": "worker"}}`,
+			},
+			wantErr: `conflicting definitions of "role" in variables, found another in b`,
+		},
+		"WithInvalidCondition": {
+			fragments: map[string]string{"a": `{"agent": {"$if": {"region": "us-east-1"}}}`},
+			wantErr:   `invalid $if condition "region"`,
+		},
+		"WithMissingInclude": {
+			fragments: map[string]string{"a": `{"include": ["missing.json"]}`},
+			wantErr:   "unable to include missing.json from a",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			jsonConfigMapMap := make(map[string]map[string]interface{})
+			for path, content := range testCase.fragments {
+				fragment, err := util.GetJsonMapFromJsonBytes([]byte(content))
+				require.NoError(t, err)
+				jsonConfigMapMap[path] = fragment
+			}
+			err := newTestResolver("linux", "ec2").Resolve(jsonConfigMapMap)
+			assert.ErrorContains(t, err, testCase.wantErr)
+		})
+	}
+}
+
+func TestResolveSharedAcrossFragments(t *testing.T) {
+	jsonConfigMapMap := map[string]map[string]interface{}{
+		"a": {"variables": map[string]interface{}{"role": "worker"}},
+		"b": {"agent": map[string]interface{}{"$if": map[string]interface{}{"os": "windows"}}, "logs": map[string]interface{}{"log_stream_name": "{{role}}"}},
+	}
+	require.NoError(t, newTestResolver("linux", "ec2").Resolve(jsonConfigMapMap))
+	assert.Equal(t, map[string]interface{}{}, jsonConfigMapMap["a"])
+	assert.Equal(t, map[string]interface{}{"logs": map[string]interface{}{"log_stream_name": "worker"}}, jsonConfigMapMap["b"])
+}
+
+func TestResolveWithoutVariables(t *testing.T) {
+	jsonConfigMapMap := map[string]map[string]interface{}{
+		"a": {"logs": map[string]interface{}{"log_stream_name": "{{instance_id}}"}},
+		"b": {"metrics": map[string]interface{}{"namespace": "{{ literal }}"}},
+	}
+	require.NoError(t, newTestResolver("linux", "ec2").Resolve(jsonConfigMapMap))
+	assert.Equal(t, "{{instance_id}}", jsonConfigMapMap["a"]["logs"].(map[string]interface{})["log_stream_name"])
+	assert.Equal(t, "{{ literal }}", jsonConfigMapMap["b"]["metrics"].(map[string]interface{})["namespace"])
+}
+
+func TestResolveModeAliases(t *testing.T) {
+	testCases := map[string]struct {
+		mode      string
+		condition interface{}
+		want      bool
+	}{
+		"OnPremMatchesOnPremise": {mode: "onPrem", condition: "onPremise", want: true},
+		"OnPremiseMatchesOnPrem": {mode: "onPremise", condition: "onPrem", want: true},
+		"OnPremiseInList":        {mode: "onPremise", condition: []interface{}{"ec2", "onPrem"}, want: true},
+		"EC2":                    {mode: "ec2", condition: "ec2", want: true},
+		"EC2DoesNotMatchOnPrem":  {mode: "ec2", condition: "onPrem", want: false},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			jsonConfigMapMap := map[string]map[string]interface{}{
+				"a": {"agent": map[string]interface{}{"$if": map[string]interface{}{"mode": testCase.condition}, "debug": true}},
+			}
+			require.NoError(t, newTestResolver("linux", testCase.mode).Resolve(jsonConfigMapMap))
+			_, ok := jsonConfigMapMap["a"]["agent"]
+			assert.Equal(t, testCase.want, ok)
+		})
+	}
+}
@@ -303,6 +303,90 @@ func TestBasicConfig(t *testing.T) {
 	}
 }
 
+func TestAgentHealthMetricsConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "agent_health_metrics_config", "linux", expectedEnvVars, "")
+}
+
+func TestPrometheusExporterConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "prometheus_exporter_config", "linux", expectedEnvVars, "")
+}
+
+func TestOtlpExporterConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "otlp_exporter_config", "linux", expectedEnvVars, "")
+}
+
+func TestJournaldConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "journald_config", "linux", expectedEnvVars, "")
+}
+
+func TestSyslogConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "syslog_config", "linux", expectedEnvVars, "")
+}
+
+func TestLogInotifyConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "log_inotify_config", "linux", expectedEnvVars, "")
+}
+
+func TestLogTailAllFilesConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "log_tail_all_files_config", "linux", expectedEnvVars, "")
+}
+
+func TestLogTimestampFormatsConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "log_timestamp_formats_config", "linux", expectedEnvVars, "")
+}
+
+func TestLogMultiLineConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "log_multi_line_config", "linux", expectedEnvVars, "")
+}
+
+func TestLogEnrichmentConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "log_enrichment_config", "linux", expectedEnvVars, "")
+}
+
+func TestLogLocalLogsConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "log_local_logs_config", "linux", expectedEnvVars, "")
+}
+
+func TestLogKinesisLogsConfig(t *testing.T) {
+	resetContext(t)
+	context.CurrentContext().SetMode(config.ModeEC2)
+	expectedEnvVars := map[string]string{}
+	checkTranslation(t, "log_kinesis_logs_config", "linux", expectedEnvVars, "")
+}
+
 func TestInvalidInputConfig(t *testing.T) {
 	resetContext(t)
 	context.CurrentContext().SetMode(config.ModeEC2)
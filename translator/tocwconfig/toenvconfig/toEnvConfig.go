@@ -6,6 +6,7 @@ package toenvconfig
 import (
 	"encoding/json"
 	"log"
+	"strconv"
 
 	"github.com/aws/amazon-cloudwatch-agent/cfg/commonconfig"
 	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
@@ -19,6 +20,11 @@ const (
 	debugKey          = "debug"
 	awsSdkLogLevelKey = "aws_sdk_log_level"
 	usageDataKey      = "usage_data"
+	configPollingKey  = "config_polling"
+	locationKey       = "location"
+	intervalKey       = "interval"
+	signatureKey      = "signature"
+	publicKeyKey      = "public_key"
 )
 
 func ToEnvConfig(jsonConfigValue map[string]interface{}) []byte {
@@ -41,6 +47,26 @@ func ToEnvConfig(jsonConfigValue map[string]interface{}) []byte {
 		if usageData, ok := agentMap[usageDataKey].(bool); ok && !usageData {
 			envVars[envconfig.CWAGENT_USAGE_DATA] = "FALSE"
 		}
+
+		// Set CWAGENT_CONFIG_POLL_* so the agent polls for new config versions, verifies them like the config-downloader
+		// and translates them in the mode of this translation
+		if configPolling, ok := agentMap[configPollingKey].(map[string]interface{}); ok {
+			if location, ok := configPolling[locationKey].(string); ok && location != "" {
+				envVars[envconfig.CWAGENT_CONFIG_POLL_LOCATION] = location
+				envVars[envconfig.CWAGENT_CONFIG_POLL_MODE] = context.CurrentContext().Mode()
+				if interval, ok := configPolling[intervalKey].(float64); ok {
+					envVars[envconfig.CWAGENT_CONFIG_POLL_INTERVAL] = strconv.Itoa(int(interval))
+				}
+				for key, envVar := range map[string]string{
+					signatureKey: envconfig.CWAGENT_CONFIG_POLL_SIGNATURE,
+					publicKeyKey: envconfig.CWAGENT_CONFIG_POLL_PUBLIC_KEY,
+				} {
+					if value, ok := configPolling[key].(string); ok && value != "" {
+						envVars[envVar] = value
+					}
+				}
+			}
+		}
 	}
 
 	proxy := util.GetHttpProxy(context.CurrentContext().Proxy())
@@ -0,0 +1,67 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package toenvconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+)
+
+func TestToEnvConfigWithConfigPolling(t *testing.T) {
+	testCases := map[string]struct {
+		configPolling map[string]interface{}
+		want          map[string]string
+	}{
+		"WithLocationAndInterval": {
+			configPolling: map[string]interface{}{"location": "ssm:AmazonCloudWatch-linux", "interval": float64(600)},
+			want: map[string]string{
+				envconfig.CWAGENT_CONFIG_POLL_LOCATION: "ssm:AmazonCloudWatch-linux",
+				envconfig.CWAGENT_CONFIG_POLL_INTERVAL: "600",
+				envconfig.CWAGENT_CONFIG_POLL_MODE:     "ec2",
+			},
+		},
+		"WithLocation": {
+			configPolling: map[string]interface{}{"location": "s3://bucket/agent.json"},
+			want: map[string]string{
+				envconfig.CWAGENT_CONFIG_POLL_LOCATION: "s3://bucket/agent.json",
+				envconfig.CWAGENT_CONFIG_POLL_MODE:     "ec2",
+			},
+		},
+		"WithVerification": {
+			configPolling: map[string]interface{}{
+				"location":   "s3://bucket/agent.json",
+				"signature":  "s3://bucket/agent.json.sig",
+				"public_key": "/opt/aws/amazon-cloudwatch-agent/etc/config.pem",
+			},
+			want: map[string]string{
+				envconfig.CWAGENT_CONFIG_POLL_LOCATION:   "s3://bucket/agent.json",
+				envconfig.CWAGENT_CONFIG_POLL_MODE:       "ec2",
+				envconfig.CWAGENT_CONFIG_POLL_SIGNATURE:  "s3://bucket/agent.json.sig",
+				envconfig.CWAGENT_CONFIG_POLL_PUBLIC_KEY: "/opt/aws/amazon-cloudwatch-agent/etc/config.pem",
+			},
+		},
+		"WithoutLocation": {
+			configPolling: map[string]interface{}{"interval": float64(600)},
+			want:          map[string]string{},
+		},
+	}
+	// the proxy and CA bundle are set from the environment of the translation
+	for _, envVar := range []string{envconfig.HTTP_PROXY, envconfig.HTTPS_PROXY, envconfig.NO_PROXY, envconfig.AWS_CA_BUNDLE, "http_proxy", "https_proxy", "no_proxy"} {
+		t.Setenv(envVar, "")
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got := map[string]string{}
+			require.NoError(t, json.Unmarshal(ToEnvConfig(map[string]interface{}{
+				"agent": map[string]interface{}{"config_polling": testCase.configPolling},
+			}), &got))
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
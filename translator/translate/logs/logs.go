@@ -18,6 +18,8 @@ var ChildRule = map[string]Rule{}
 const (
 	SectionKey             = "logs"
 	Output_Cloudwatch_Logs = "cloudwatchlogs"
+	Output_Local_Logs      = "locallogs"
+	Output_Kinesis_Logs    = "kinesislogs"
 )
 
 func GetCurPath() string {
@@ -32,6 +34,12 @@ func RegisterRule(fieldname string, r Rule) {
 type Logs struct {
 	FileStateFolder string
 	MetadataInfo    map[string]string
+	// LocalLogsDestination is set when a log file has the local logs output as
+	// its destination.
+	LocalLogsDestination bool
+	// KinesisLogsDestination is set when a log file has the kinesis logs output
+	// as its destination.
+	KinesisLogsDestination bool
 }
 
 var GlobalLogConfig = Logs{}
@@ -42,6 +50,9 @@ func (l *Logs) ApplyRule(input interface{}) (returnKey string, returnVal interfa
 	inputs := map[string]interface{}{}
 	processors := map[string]interface{}{}
 	cloudwatchConfig := map[string]interface{}{}
+	var localLogsInfo, kinesisLogsInfo map[string]interface{}
+	GlobalLogConfig.LocalLogsDestination = false
+	GlobalLogConfig.KinesisLogsDestination = false
 	GlobalLogConfig.MetadataInfo = util.GetMetadataInfo(util.Ec2MetadataInfoProvider)
 
 	//Check if this plugin exist in the input instance
@@ -74,12 +85,30 @@ func (l *Logs) ApplyRule(input interface{}) (returnKey string, returnVal interfa
 					inputs = translator.MergeTwoUniqueMaps(inputs, val.(map[string]interface{}))
 				} else if key == Output_Cloudwatch_Logs {
 					cloudwatchConfig = translator.MergeTwoUniqueMaps(cloudwatchConfig, val.(map[string]interface{}))
+				} else if key == Output_Local_Logs {
+					localLogsInfo = val.(map[string]interface{})
+				} else if key == Output_Kinesis_Logs {
+					kinesisLogsInfo = val.(map[string]interface{})
 				}
 			}
 		}
 
 		cloudwatchInfo := map[string]interface{}{}
 		cloudwatchInfo["cloudwatchlogs"] = []interface{}{cloudwatchConfig}
+		// the collect_list rules run with logs_collected, so the destinations are
+		// only known once all the rules are applied
+		if localLogsInfo == nil && GlobalLogConfig.LocalLogsDestination {
+			localLogsInfo = localLogsConfig(map[string]interface{}{})
+		}
+		if localLogsInfo != nil {
+			cloudwatchInfo[Output_Local_Logs] = []interface{}{localLogsInfo}
+		}
+		// the stream of the kinesis logs output has no default
+		if kinesisLogsInfo != nil {
+			cloudwatchInfo[Output_Kinesis_Logs] = []interface{}{kinesisLogsInfo}
+		} else if GlobalLogConfig.KinesisLogsDestination {
+			translator.AddErrorMessages(GetCurPath()+KinesisLogsSectionKey, "kinesislogs is required by the log files with the kinesislogs destination.")
+		}
 		result["outputs"] = cloudwatchInfo
 
 		if len(inputs) > 0 {
@@ -17,6 +17,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator"
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs"
 )
 
 func TestFileConfig(t *testing.T) {
@@ -61,6 +62,30 @@ func TestFileConfigOverride(t *testing.T) {
 	assert.Equal(t, expectVal, val)
 }
 
+func TestFileConfigNameTemplates(t *testing.T) {
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{"collect_list":[{"file_path":"/var/log/**.log",
+            "file_path_regex":"^/var/log/(?P<app>[^/]+)/",
+            "log_group_name":"/app/{app}/{date}","log_stream_name":"{path:-1}"}]}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+	// the placeholders are resolved by the plugin for each file
+	expectVal := []interface{}{map[string]interface{}{
+		"file_path":         "/var/log/**.log",
+		"file_path_regex":   "^/var/log/(?P<app>[^/]+)/",
+		"from_beginning":    true,
+		"log_group_name":    "/app/{app}/{date}",
+		"log_stream_name":   "{path:-1}",
+		"log_group_class":   "",
+		"pipe":              false,
+		"retention_in_days": -1,
+	}}
+	assert.Equal(t, expectVal, val)
+}
+
 func TestTimestampFormat(t *testing.T) {
 	f := new(FileConfig)
 	var input interface{}
@@ -946,3 +971,193 @@ func TestConflictingLogGroupClass(t *testing.T) {
 	assert.Equal(t, "Under path : /logs/logs_collected/files/collect_list/ | Error : Different log_group_class values can't be set for the same log group: test1", translator.ErrorMessages[len(translator.ErrorMessages)-1])
 	assert.Equal(t, expectVal, val)
 }
+
+func TestContainerLogFormat(t *testing.T) {
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{"collect_list":[{"file_path":"/var/log/containers/*.log",
+            "container_log_format":"cri"}]}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+	// the log group and log stream names are set for each pod by the plugin
+	expectVal := []interface{}{map[string]interface{}{
+		"file_path":            "/var/log/containers/*.log",
+		"container_log_format": "cri",
+		"from_beginning":       true,
+		"log_group_class":      "",
+		"pipe":                 false,
+		"retention_in_days":    -1,
+	}}
+	assert.Equal(t, expectVal, val)
+
+	translator.ResetMessages()
+	e = json.Unmarshal([]byte(`{"collect_list":[{"file_path":"/var/log/containers/*.log",
+            "container_log_format":"podman"}]}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	f.ApplyRule(input)
+	assert.Len(t, translator.ErrorMessages, 1)
+	translator.ResetMessages()
+}
+
+func TestBackfill(t *testing.T) {
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{"collect_list":[{"file_path":"/var/log/app.log*",
+            "backfill_since":"72h","backfill_until":"2024-03-10T06:00:00Z","backfill_rate_limit":500}]}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+	expectVal := []interface{}{map[string]interface{}{
+		"file_path":           "/var/log/app.log*",
+		"backfill_since":      "72h",
+		"backfill_until":      "2024-03-10T06:00:00Z",
+		"backfill_rate_limit": 500,
+		"from_beginning":      true,
+		"log_group_class":     "",
+		"pipe":                false,
+		"retention_in_days":   -1,
+	}}
+	assert.Equal(t, expectVal, val)
+
+	translator.ResetMessages()
+	e = json.Unmarshal([]byte(`{"collect_list":[{"file_path":"/var/log/app.log*",
+            "backfill_since":"last week"}]}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val = f.ApplyRule(input)
+	assert.Len(t, translator.ErrorMessages, 1)
+	assert.NotContains(t, val.([]interface{})[0], "backfill_since")
+	translator.ResetMessages()
+}
+
+func TestMultiLine(t *testing.T) {
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{"collect_list":[{"file_path":"/var/log/app.log",
+            "multi_line_continue_pattern":"^\\s","multi_line_end_pattern":"^END","multi_line_max_lines":500,
+            "multi_line_max_bytes":65536,"multi_line_flush_timeout":2,"multi_line_preset":"java"}]}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+	expectVal := []interface{}{map[string]interface{}{
+		"file_path":                   "/var/log/app.log",
+		"multi_line_continue_pattern": "^\\s",
+		"multi_line_end_pattern":      "^END",
+		"multi_line_max_lines":        500,
+		"multi_line_max_bytes":        65536,
+		"multi_line_flush_timeout":    "2s",
+		"multi_line_preset":           "java",
+		"from_beginning":              true,
+		"log_group_class":             "",
+		"pipe":                        false,
+		"retention_in_days":           -1,
+	}}
+	assert.Equal(t, expectVal, val)
+
+	translator.ResetMessages()
+	e = json.Unmarshal([]byte(`{"collect_list":[{"file_path":"/var/log/app.log",
+            "multi_line_end_pattern":"(","multi_line_preset":"ruby"}]}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val = f.ApplyRule(input)
+	assert.Len(t, translator.ErrorMessages, 2)
+	assert.NotContains(t, val.([]interface{})[0], "multi_line_end_pattern")
+	assert.NotContains(t, val.([]interface{})[0], "multi_line_preset")
+	translator.ResetMessages()
+}
+
+func TestEnrichment(t *testing.T) {
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{"collect_list":[{"file_path":"/var/log/app.log",
+            "enrichment_format":"json","enrichment_fields":["instance_id","file_path"],"enrichment_ec2_tags":["Name","Team"]}]}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+	expectVal := []interface{}{map[string]interface{}{
+		"file_path":           "/var/log/app.log",
+		"enrichment_format":   "json",
+		"enrichment_fields":   []string{"instance_id", "file_path"},
+		"enrichment_ec2_tags": []string{"Name", "Team"},
+		"from_beginning":      true,
+		"log_group_class":     "",
+		"pipe":                false,
+		"retention_in_days":   -1,
+	}}
+	assert.Equal(t, expectVal, val)
+
+	translator.ResetMessages()
+	e = json.Unmarshal([]byte(`{"collect_list":[{"file_path":"/var/log/app.log",
+            "enrichment_format":"xml","enrichment_ec2_tags":[1]}]}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val = f.ApplyRule(input)
+	assert.Len(t, translator.ErrorMessages, 2)
+	assert.NotContains(t, val.([]interface{})[0], "enrichment_format")
+	assert.NotContains(t, val.([]interface{})[0], "enrichment_ec2_tags")
+	translator.ResetMessages()
+}
+
+func TestDestination(t *testing.T) {
+	logs.GlobalLogConfig.LocalLogsDestination = false
+	logs.GlobalLogConfig.KinesisLogsDestination = false
+	defer func() {
+		logs.GlobalLogConfig.LocalLogsDestination = false
+		logs.GlobalLogConfig.KinesisLogsDestination = false
+	}()
+
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{"collect_list":[{"file_path":"/var/log/app.log","destination":"cloudwatchlogs"}]}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+	expectVal := []interface{}{map[string]interface{}{
+		"file_path":         "/var/log/app.log",
+		"destination":       "cloudwatchlogs",
+		"from_beginning":    true,
+		"log_group_class":   "",
+		"pipe":              false,
+		"retention_in_days": -1,
+	}}
+	assert.Equal(t, expectVal, val)
+	assert.False(t, logs.GlobalLogConfig.LocalLogsDestination)
+
+	e = json.Unmarshal([]byte(`{"collect_list":[{"file_path":"/var/log/app.log","destination":"locallogs"}]}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val = f.ApplyRule(input)
+	assert.Equal(t, "locallogs", val.([]interface{})[0].(map[string]interface{})["destination"])
+	assert.True(t, logs.GlobalLogConfig.LocalLogsDestination)
+	assert.False(t, logs.GlobalLogConfig.KinesisLogsDestination)
+
+	e = json.Unmarshal([]byte(`{"collect_list":[{"file_path":"/var/log/app.log","destination":"kinesislogs"}]}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val = f.ApplyRule(input)
+	assert.Equal(t, "kinesislogs", val.([]interface{})[0].(map[string]interface{})["destination"])
+	assert.True(t, logs.GlobalLogConfig.KinesisLogsDestination)
+
+	translator.ResetMessages()
+	e = json.Unmarshal([]byte(`{"collect_list":[{"file_path":"/var/log/app.log","destination":"kafka"}]}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val = f.ApplyRule(input)
+	assert.Len(t, translator.ErrorMessages, 1)
+	assert.NotContains(t, val.([]interface{})[0], "destination")
+	translator.ResetMessages()
+}
@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const BackfillRateLimitSectionKey = "backfill_rate_limit"
+
+type BackfillRateLimit struct {
+}
+
+func (b *BackfillRateLimit) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if _, ok := im[BackfillRateLimitSectionKey]; !ok {
+		return
+	}
+	return translator.DefaultIntegralCase(BackfillRateLimitSectionKey, float64(0), input)
+}
+
+func init() {
+	b := new(BackfillRateLimit)
+	r := []Rule{b}
+	RegisterRule(BackfillRateLimitSectionKey, r)
+}
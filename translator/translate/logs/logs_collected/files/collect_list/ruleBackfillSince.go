@@ -0,0 +1,50 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const BackfillSinceSectionKey = "backfill_since"
+
+type BackfillSince struct {
+}
+
+// The content of the files is backfilled with the log events after the time,
+// which is either in RFC3339 format or a duration before the agent starts.
+func (b *BackfillSince) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return applyBackfillTimeRule(BackfillSinceSectionKey, input)
+}
+
+func applyBackfillTimeRule(key string, input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(key, "", input)
+	if returnVal == "" {
+		return
+	}
+	val, ok := returnVal.(string)
+	if !ok || !isBackfillTime(val) {
+		translator.AddErrorMessages(GetCurPath()+key, fmt.Sprintf("%s value %v is not a time in RFC3339 format or a duration.", key, returnVal))
+		return "", nil
+	}
+	returnKey = key
+	return
+}
+
+func isBackfillTime(val string) bool {
+	if _, err := time.ParseDuration(val); err == nil {
+		return true
+	}
+	_, err := time.Parse(time.RFC3339, val)
+	return err == nil
+}
+
+func init() {
+	b := new(BackfillSince)
+	r := []Rule{b}
+	RegisterRule(BackfillSinceSectionKey, r)
+}
@@ -0,0 +1,20 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+const BackfillUntilSectionKey = "backfill_until"
+
+type BackfillUntil struct {
+}
+
+// The log events after the time are not backfilled.
+func (b *BackfillUntil) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return applyBackfillTimeRule(BackfillUntilSectionKey, input)
+}
+
+func init() {
+	b := new(BackfillUntil)
+	r := []Rule{b}
+	RegisterRule(BackfillUntilSectionKey, r)
+}
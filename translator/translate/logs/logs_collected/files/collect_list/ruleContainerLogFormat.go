@@ -0,0 +1,44 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	ContainerLogFormatSectionKey = "container_log_format"
+
+	ContainerLogFormatAuto   = "auto"   //detected for each line
+	ContainerLogFormatCRI    = "cri"    //the logging format of containerd and CRI-O
+	ContainerLogFormatDocker = "docker" //the json-file logging driver of docker
+)
+
+type ContainerLogFormat struct {
+}
+
+// The container logs are parsed and enriched with the metadata of their
+// Kubernetes pod by the plugin.
+func (c *ContainerLogFormat) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(ContainerLogFormatSectionKey, "", input)
+	if returnVal == "" {
+		return
+	}
+	switch returnVal {
+	case ContainerLogFormatAuto, ContainerLogFormatCRI, ContainerLogFormatDocker:
+	default:
+		translator.AddErrorMessages(GetCurPath()+ContainerLogFormatSectionKey, fmt.Sprintf("container_log_format value %v is not a valid value.", returnVal))
+		return
+	}
+	returnKey = ContainerLogFormatSectionKey
+	return
+}
+
+func init() {
+	c := new(ContainerLogFormat)
+	r := []Rule{c}
+	RegisterRule(ContainerLogFormatSectionKey, r)
+}
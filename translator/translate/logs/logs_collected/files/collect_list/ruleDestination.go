@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs"
+)
+
+const DestinationSectionKey = "destination"
+
+type Destination struct {
+}
+
+// The destination is the output of the log events of the file, the cloudwatchlogs
+// output of the plugin when not set.
+func (d *Destination) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(DestinationSectionKey, "", input)
+	if returnVal == "" {
+		return
+	}
+	switch returnVal {
+	case logs.Output_Cloudwatch_Logs:
+	case logs.Output_Local_Logs:
+		logs.GlobalLogConfig.LocalLogsDestination = true
+	case logs.Output_Kinesis_Logs:
+		logs.GlobalLogConfig.KinesisLogsDestination = true
+	default:
+		translator.AddErrorMessages(GetCurPath()+DestinationSectionKey, fmt.Sprintf("destination value %v is not a valid value.", returnVal))
+		return
+	}
+	returnKey = DestinationSectionKey
+	return
+}
+
+func init() {
+	d := new(Destination)
+	r := []Rule{d}
+	RegisterRule(DestinationSectionKey, r)
+}
@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const EnrichmentEC2TagsSectionKey = "enrichment_ec2_tags"
+
+type EnrichmentEC2Tags struct {
+}
+
+func (e *EnrichmentEC2Tags) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, val := translator.DefaultCase(EnrichmentEC2TagsSectionKey, "", input)
+	if val == "" {
+		return
+	}
+	values, ok := val.([]interface{})
+	if !ok {
+		translator.AddErrorMessages(GetCurPath()+EnrichmentEC2TagsSectionKey, fmt.Sprintf("enrichment_ec2_tags value %v is not a list.", val))
+		return
+	}
+	var res []string
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok || s == "" {
+			translator.AddErrorMessages(GetCurPath()+EnrichmentEC2TagsSectionKey, fmt.Sprintf("enrichment_ec2_tags value %v is not a valid value.", v))
+			return
+		}
+		res = append(res, s)
+	}
+	return EnrichmentEC2TagsSectionKey, res
+}
+
+func init() {
+	e := new(EnrichmentEC2Tags)
+	r := []Rule{e}
+	RegisterRule(EnrichmentEC2TagsSectionKey, r)
+}
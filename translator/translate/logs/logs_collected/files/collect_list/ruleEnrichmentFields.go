@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const EnrichmentFieldsSectionKey = "enrichment_fields"
+
+type EnrichmentFields struct {
+}
+
+func (e *EnrichmentFields) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, val := translator.DefaultCase(EnrichmentFieldsSectionKey, "", input)
+	if val == "" {
+		return
+	}
+	values, ok := val.([]interface{})
+	if !ok {
+		translator.AddErrorMessages(GetCurPath()+EnrichmentFieldsSectionKey, fmt.Sprintf("enrichment_fields value %v is not a list.", val))
+		return
+	}
+	var res []string
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok || s == "" {
+			translator.AddErrorMessages(GetCurPath()+EnrichmentFieldsSectionKey, fmt.Sprintf("enrichment_fields value %v is not a valid value.", v))
+			return
+		}
+		res = append(res, s)
+	}
+	return EnrichmentFieldsSectionKey, res
+}
+
+func init() {
+	e := new(EnrichmentFields)
+	r := []Rule{e}
+	RegisterRule(EnrichmentFieldsSectionKey, r)
+}
@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	EnrichmentFormatSectionKey = "enrichment_format"
+
+	EnrichmentFormatJSON     = "json"      //the log event is the message field of a json envelope
+	EnrichmentFormatKeyValue = "key_value" //key=value pairs are appended to the log event
+)
+
+type EnrichmentFormat struct {
+}
+
+// The log events are enriched with the metadata of the host and the file by
+// the plugin.
+func (e *EnrichmentFormat) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(EnrichmentFormatSectionKey, "", input)
+	if returnVal == "" {
+		return
+	}
+	switch returnVal {
+	case EnrichmentFormatJSON, EnrichmentFormatKeyValue:
+	default:
+		translator.AddErrorMessages(GetCurPath()+EnrichmentFormatSectionKey, fmt.Sprintf("enrichment_format value %v is not a valid value.", returnVal))
+		return
+	}
+	returnKey = EnrichmentFormatSectionKey
+	return
+}
+
+func init() {
+	e := new(EnrichmentFormat)
+	r := []Rule{e}
+	RegisterRule(EnrichmentFormatSectionKey, r)
+}
@@ -0,0 +1,30 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const FilePathRegexSectionKey = "file_path_regex"
+
+type FilePathRegex struct {
+}
+
+// The named groups of the regex are matched against the path of each file and
+// can be used in the log group and log stream names.
+func (f *FilePathRegex) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(FilePathRegexSectionKey, "", input)
+	if returnVal == "" {
+		return
+	}
+	returnKey = FilePathRegexSectionKey
+	return
+}
+
+func init() {
+	f := new(FilePathRegex)
+	r := []Rule{f}
+	RegisterRule(FilePathRegexSectionKey, r)
+}
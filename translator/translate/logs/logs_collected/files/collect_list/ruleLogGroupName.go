@@ -20,7 +20,7 @@ func (l *LogGroupName) ApplyRule(input interface{}) (returnKey string, returnVal
 		return
 	}
 	returnKey = "log_group_name"
-	returnVal = util.ResolvePlaceholder(returnVal.(string), logs.GlobalLogConfig.MetadataInfo)
+	returnVal = util.ResolveMetadataPlaceholder(returnVal.(string), logs.GlobalLogConfig.MetadataInfo)
 	return
 }
 
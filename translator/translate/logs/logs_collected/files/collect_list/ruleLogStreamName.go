@@ -18,7 +18,7 @@ func (l *LogStreamName) ApplyRule(input interface{}) (returnKey string, returnVa
 		return
 	}
 	returnKey = key
-	returnVal = util.ResolvePlaceholder(val.(string), logs.GlobalLogConfig.MetadataInfo)
+	returnVal = util.ResolveMetadataPlaceholder(val.(string), logs.GlobalLogConfig.MetadataInfo)
 	return
 }
 
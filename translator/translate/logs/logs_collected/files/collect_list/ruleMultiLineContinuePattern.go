@@ -0,0 +1,35 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const MultiLineContinuePatternSectionKey = "multi_line_continue_pattern"
+
+type MultiLineContinuePattern struct {
+}
+
+func (m *MultiLineContinuePattern) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(MultiLineContinuePatternSectionKey, "", input)
+	if returnVal == "" {
+		return
+	}
+	if _, err := regexp.Compile(returnVal.(string)); err != nil {
+		translator.AddErrorMessages(GetCurPath()+MultiLineContinuePatternSectionKey, fmt.Sprintf("multi_line_continue_pattern %v is invalid", returnVal))
+		return
+	}
+	returnKey = MultiLineContinuePatternSectionKey
+	return
+}
+
+func init() {
+	m := new(MultiLineContinuePattern)
+	r := []Rule{m}
+	RegisterRule(MultiLineContinuePatternSectionKey, r)
+}
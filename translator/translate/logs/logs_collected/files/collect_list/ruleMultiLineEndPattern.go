@@ -0,0 +1,35 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const MultiLineEndPatternSectionKey = "multi_line_end_pattern"
+
+type MultiLineEndPattern struct {
+}
+
+func (m *MultiLineEndPattern) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(MultiLineEndPatternSectionKey, "", input)
+	if returnVal == "" {
+		return
+	}
+	if _, err := regexp.Compile(returnVal.(string)); err != nil {
+		translator.AddErrorMessages(GetCurPath()+MultiLineEndPatternSectionKey, fmt.Sprintf("multi_line_end_pattern %v is invalid", returnVal))
+		return
+	}
+	returnKey = MultiLineEndPatternSectionKey
+	return
+}
+
+func init() {
+	m := new(MultiLineEndPattern)
+	r := []Rule{m}
+	RegisterRule(MultiLineEndPatternSectionKey, r)
+}
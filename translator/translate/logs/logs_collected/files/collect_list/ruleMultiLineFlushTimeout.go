@@ -0,0 +1,28 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const MultiLineFlushTimeoutSectionKey = "multi_line_flush_timeout"
+
+type MultiLineFlushTimeout struct {
+}
+
+// The flush timeout is in seconds in the json config.
+func (m *MultiLineFlushTimeout) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if _, ok := im[MultiLineFlushTimeoutSectionKey]; !ok {
+		return
+	}
+	return translator.DefaultTimeIntervalCase(MultiLineFlushTimeoutSectionKey, float64(0), input)
+}
+
+func init() {
+	m := new(MultiLineFlushTimeout)
+	r := []Rule{m}
+	RegisterRule(MultiLineFlushTimeoutSectionKey, r)
+}
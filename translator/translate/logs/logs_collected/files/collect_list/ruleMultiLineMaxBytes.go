@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const MultiLineMaxBytesSectionKey = "multi_line_max_bytes"
+
+type MultiLineMaxBytes struct {
+}
+
+func (m *MultiLineMaxBytes) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if _, ok := im[MultiLineMaxBytesSectionKey]; !ok {
+		return
+	}
+	return translator.DefaultIntegralCase(MultiLineMaxBytesSectionKey, float64(0), input)
+}
+
+func init() {
+	m := new(MultiLineMaxBytes)
+	r := []Rule{m}
+	RegisterRule(MultiLineMaxBytesSectionKey, r)
+}
@@ -0,0 +1,44 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	MultiLinePresetSectionKey = "multi_line_preset"
+
+	MultiLinePresetJava   = "java"
+	MultiLinePresetPython = "python"
+	MultiLinePresetGo     = "go"
+)
+
+type MultiLinePreset struct {
+}
+
+// The presets group the lines of the stack traces of the language, their
+// patterns are defined by the plugin.
+func (m *MultiLinePreset) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(MultiLinePresetSectionKey, "", input)
+	if returnVal == "" {
+		return
+	}
+	switch returnVal {
+	case MultiLinePresetJava, MultiLinePresetPython, MultiLinePresetGo:
+	default:
+		translator.AddErrorMessages(GetCurPath()+MultiLinePresetSectionKey, fmt.Sprintf("multi_line_preset value %v is not a valid value.", returnVal))
+		return
+	}
+	returnKey = MultiLinePresetSectionKey
+	return
+}
+
+func init() {
+	m := new(MultiLinePreset)
+	r := []Rule{m}
+	RegisterRule(MultiLinePresetSectionKey, r)
+}
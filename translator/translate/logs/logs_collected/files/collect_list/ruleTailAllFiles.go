@@ -0,0 +1,32 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const TailAllFilesSectionKey = "tail_all_files"
+
+type TailAllFiles struct {
+}
+
+func (l *TailAllFiles) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(TailAllFilesSectionKey, "", input)
+	if returnVal == "" {
+		return
+	}
+	returnKey = TailAllFilesSectionKey
+	var ok bool
+	if returnVal, ok = returnVal.(bool); !ok {
+		returnVal = false
+	}
+	return
+}
+
+func init() {
+	l := new(TailAllFiles)
+	r := []Rule{l}
+	RegisterRule(TailAllFilesSectionKey, r)
+}
@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
+	// The IANA time zones are embedded for the hosts without a time zone database.
+	_ "time/tzdata"
 
 	"github.com/aws/amazon-cloudwatch-agent/translator"
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
@@ -126,6 +129,15 @@ var TimeFormatRegexEscapeMap = map[string]string{
 	"$": "\\$",
 }
 
+// TimestampFormatKeywords are the timestamp formats that are passed as is to
+// the agent, which parses them without a Golang layout or a regex.
+var TimestampFormatKeywords = map[string]bool{
+	"epoch_seconds": true,
+	"epoch_millis":  true,
+	"epoch_nanos":   true,
+	"iso8601":       true,
+}
+
 func checkAndReplace(input string, timestampFormatMap map[string]string) string {
 	res := input
 	for k, v := range timestampFormatMap {
@@ -150,6 +162,9 @@ func (t *TimestampRegex) ApplyRule(input interface{}) (returnKey string, returnV
 	} else if m["file_path"] == context.CurrentContext().GetAgentLogFile() {
 		fmt.Printf("timestamp_format set file_path : %s is the same as agent log file %s thus do not use timestamp_regex \n", m["file_path"], context.CurrentContext().GetAgentLogFile())
 		return "", ""
+	} else if TimestampFormatKeywords[val.(string)] {
+		//The agent has the regex of the timestamp formats that are keywords
+		return "", ""
 	} else {
 		//If user provide with the specific timestamp_format, use the one that user provide
 		res := checkAndReplace(val.(string), TimeFormatRegexEscapeMap)
@@ -186,6 +201,9 @@ func (t *TimestampLayout) ApplyRule(input interface{}) (returnKey string, return
 	} else if m["file_path"] == context.CurrentContext().GetAgentLogFile() {
 		fmt.Printf("timestamp_format set file_path : %s is the same as agent log file %s thus do not use timestamp_layout \n", m["file_path"], context.CurrentContext().GetAgentLogFile())
 		return "", ""
+	} else if TimestampFormatKeywords[val.(string)] {
+		returnKey = "timestamp_layout"
+		returnVal = []string{val.(string)}
 	} else {
 		res := checkAndReplace(val.(string), TimeFormatMap)
 		//If user provide with the specific timestamp_format, use the one that user provide
@@ -219,10 +237,18 @@ func (t *Timezone) ApplyRule(input interface{}) (returnKey string, returnVal int
 	} else {
 		//If user provide with the specific timestamp_format, use the one that user provide
 		returnKey = "timezone"
-		if val == "UTC" {
+		switch val {
+		case "UTC":
 			returnVal = "UTC"
-		} else {
+		case "Local", "LOCAL":
 			returnVal = "LOCAL"
+		default:
+			//Other time zones are the names of IANA time zones like America/New_York
+			if _, err := time.LoadLocation(val.(string)); err != nil || val == "" {
+				translator.AddErrorMessages(GetCurPath()+"timezone", fmt.Sprintf("Timezone %v is not a valid time zone", val))
+				return "", ""
+			}
+			returnVal = val
 		}
 	}
 	return
@@ -8,6 +8,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
 )
 
 func TestTimestampRegexRule(t *testing.T) {
@@ -93,6 +95,15 @@ func TestTimestampRegexRule(t *testing.T) {
 				value: "(foo)",
 			},
 		},
+		"WithEpochTimestampFormat": {
+			input: map[string]interface{}{
+				"timestamp_format": "epoch_millis",
+			},
+			want: &want{
+				key:   "",
+				value: "",
+			},
+		},
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -187,6 +198,15 @@ func TestTimestampLayoutxRule(t *testing.T) {
 				value: []string{"foo"},
 			},
 		},
+		"WithISO8601TimestampFormat": {
+			input: map[string]interface{}{
+				"timestamp_format": "iso8601",
+			},
+			want: &want{
+				key:   "timestamp_layout",
+				value: []string{"iso8601"},
+			},
+		},
 	}
 	for name, testCase := range testCases {
 		t.Run(name, func(t *testing.T) {
@@ -197,3 +217,61 @@ func TestTimestampLayoutxRule(t *testing.T) {
 		})
 	}
 }
+
+func TestTimezoneRule(t *testing.T) {
+	timezone := new(Timezone)
+	testCases := map[string]struct {
+		input   string
+		wantKey string
+		wantVal interface{}
+	}{
+		"UTC":     {input: "UTC", wantKey: "timezone", wantVal: "UTC"},
+		"Local":   {input: "Local", wantKey: "timezone", wantVal: "LOCAL"},
+		"LOCAL":   {input: "LOCAL", wantKey: "timezone", wantVal: "LOCAL"},
+		"IANA":    {input: "America/New_York", wantKey: "timezone", wantVal: "America/New_York"},
+		"Invalid": {input: "Mars/Olympus_Mons", wantKey: "", wantVal: ""},
+		"Empty":   {input: "", wantKey: "", wantVal: ""},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			translator.ResetMessages()
+			key, val := timezone.ApplyRule(map[string]interface{}{"timezone": testCase.input})
+			assert.Equal(t, testCase.wantKey, key)
+			assert.Equal(t, testCase.wantVal, val)
+			assert.Equal(t, testCase.wantKey == "", len(translator.ErrorMessages) > 0)
+		})
+	}
+}
+
+func TestTimestampFormatsRule(t *testing.T) {
+	translator.ResetMessages()
+	formats := new(TimestampFormats)
+	key, val := formats.ApplyRule(map[string]interface{}{
+		"timestamp_formats": []interface{}{
+			map[string]interface{}{"timestamp_format": "epoch_millis", "json_field": "meta.time"},
+			map[string]interface{}{"timestamp_format": "%d/%b/%Y:%H:%M:%S %z", "timezone": "Europe/Paris"},
+			map[string]interface{}{"timestamp_format": "iso8601", "timezone": "UTC"},
+			map[string]interface{}{"timezone": "UTC"},
+		},
+	})
+	assert.Equal(t, "timestamp_formats", key)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"timestamp_layout": []string{"epoch_millis"},
+			"json_field":       "meta.time",
+		},
+		map[string]interface{}{
+			"timestamp_layout": []string{"_2/Jan/2006:15:04:05 -0700"},
+			"timestamp_regex":  "(\\d{1,2}/\\w{3}/\\d{4}:\\d{2}:\\d{2}:\\d{2} [\\+-]\\d{4})",
+			"timezone":         "Europe/Paris",
+		},
+		map[string]interface{}{
+			"timestamp_layout": []string{"iso8601"},
+			"timezone":         "UTC",
+		},
+	}, val)
+	assert.Len(t, translator.ErrorMessages, 1)
+
+	key, _ = formats.ApplyRule(map[string]interface{}{})
+	assert.Equal(t, "", key)
+}
@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	TimestampFormatsSectionKey = "timestamp_formats"
+	JSONFieldSectionKey        = "json_field"
+)
+
+// TimestampFormats are the fallback timestamp formats of the file, which the
+// agent tries in order after the timestamp_format of the file. Each of them is
+// translated with the same rules as the timestamp_format of the file.
+type TimestampFormats struct {
+}
+
+func (t *TimestampFormats) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	val, ok := im[TimestampFormatsSectionKey]
+	if !ok {
+		return "", ""
+	}
+	rules := []Rule{new(TimestampLayout), new(TimestampRegex), new(Timezone)}
+	var res []interface{}
+	for _, format := range val.([]interface{}) {
+		formatMap, ok := format.(map[string]interface{})
+		if !ok {
+			translator.AddErrorMessages(GetCurPath()+TimestampFormatsSectionKey, fmt.Sprintf("Timestamp format %v is invalid", format))
+			continue
+		}
+		if _, ok := formatMap["timestamp_format"]; !ok {
+			translator.AddErrorMessages(GetCurPath()+TimestampFormatsSectionKey, fmt.Sprintf("Timestamp format %v is missing timestamp_format", format))
+			continue
+		}
+		result := map[string]interface{}{}
+		for _, rule := range rules {
+			key, val := rule.ApplyRule(formatMap)
+			if key != "" {
+				result[key] = val
+			}
+		}
+		if _, ok := result["timestamp_layout"]; !ok {
+			continue
+		}
+		_, jsonField := translator.DefaultCase(JSONFieldSectionKey, "", formatMap)
+		if jsonField != "" {
+			result[JSONFieldSectionKey] = jsonField
+		}
+		res = append(res, result)
+	}
+	returnKey = TimestampFormatsSectionKey
+	returnVal = res
+	return
+}
+
+func init() {
+	t := new(TimestampFormats)
+	r := []Rule{t}
+	RegisterRule(TimestampFormatsSectionKey, r)
+}
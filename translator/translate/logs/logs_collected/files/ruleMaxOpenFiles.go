@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const MaxOpenFilesSectionKey = "max_open_files"
+
+type MaxOpenFiles struct {
+}
+
+func (m *MaxOpenFiles) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if _, ok := im[MaxOpenFilesSectionKey]; !ok {
+		return
+	}
+	return translator.DefaultIntegralCase(MaxOpenFilesSectionKey, float64(0), input)
+}
+
+func init() {
+	m := new(MaxOpenFiles)
+	RegisterRule(MaxOpenFilesSectionKey, m)
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const MaxWatchesSectionKey = "max_watches"
+
+type MaxWatches struct {
+}
+
+func (m *MaxWatches) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if _, ok := im[MaxWatchesSectionKey]; !ok {
+		return
+	}
+	return translator.DefaultIntegralCase(MaxWatchesSectionKey, float64(0), input)
+}
+
+func init() {
+	m := new(MaxWatches)
+	RegisterRule(MaxWatchesSectionKey, m)
+}
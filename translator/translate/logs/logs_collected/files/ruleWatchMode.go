@@ -0,0 +1,38 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	WatchModeSectionKey = "watch_mode"
+
+	WatchModePoll    = "poll"    //the file_path globs are evaluated every second
+	WatchModeInotify = "inotify" //the directories are watched for new files
+)
+
+type WatchMode struct {
+}
+
+func (w *WatchMode) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(WatchModeSectionKey, "", input)
+	if returnVal == "" {
+		return
+	}
+	if returnVal != WatchModePoll && returnVal != WatchModeInotify {
+		translator.AddErrorMessages(GetCurPath()+WatchModeSectionKey, fmt.Sprintf("watch_mode value %s is not a valid value.", returnVal))
+		return
+	}
+	returnKey = WatchModeSectionKey
+	return
+}
+
+func init() {
+	w := new(WatchMode)
+	RegisterRule(WatchModeSectionKey, w)
+}
@@ -0,0 +1,89 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectlist
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+func TestApplyRule(t *testing.T) {
+	c := new(CollectList)
+	var rawJsonString = `
+{
+    "collect_list": [
+      {
+        "units": ["sshd.service", "cron.service"],
+        "priority": "info",
+        "log_group_name": "journal/{_SYSTEMD_UNIT}",
+        "log_stream_name": "stream",
+        "retention_in_days": 7
+      },
+      {
+        "matches": ["_TRANSPORT=kernel"],
+        "event_format": "json",
+        "log_group_name": "kernel"
+      }
+    ]
+}
+`
+	var input interface{}
+
+	var expected = []interface{}{
+		map[string]interface{}{
+			"units":             []interface{}{"sshd.service", "cron.service"},
+			"priority":          "info",
+			"log_group_name":    "journal/{_SYSTEMD_UNIT}",
+			"log_stream_name":   "stream",
+			"retention_in_days": 7,
+			"log_group_class":   "",
+		},
+		map[string]interface{}{
+			"matches":           []interface{}{"_TRANSPORT=kernel"},
+			"event_format":      "json",
+			"log_group_name":    "kernel",
+			"retention_in_days": -1,
+			"log_group_class":   "",
+		},
+	}
+
+	var actual interface{}
+
+	err := json.Unmarshal([]byte(rawJsonString), &input)
+	if err == nil {
+		_, actual = c.ApplyRule(input)
+		assert.Equal(t, expected, actual)
+	} else {
+		panic(err)
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	testCases := map[string]struct {
+		input string
+		want  string
+	}{
+		"InvalidMatch": {
+			input: `{"collect_list": [{"matches": ["/usr/bin/sshd"], "log_group_name": "journal"}]}`,
+			want:  "Under path : /logs/logs_collected/journald/collect_list/matches | Error : match /usr/bin/sshd is not a valid FIELD=value journal match.",
+		},
+		"InvalidEventFormat": {
+			input: `{"collect_list": [{"event_format": "xml", "log_group_name": "journal"}]}`,
+			want:  "Under path : /logs/logs_collected/journald/collect_list/event_format | Error : event_format value xml is not a valid value.",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			translator.ResetMessages()
+			var input interface{}
+			assert.NoError(t, json.Unmarshal([]byte(testCase.input), &input))
+			new(CollectList).ApplyRule(input)
+			assert.Equal(t, testCase.want, translator.ErrorMessages[len(translator.ErrorMessages)-1])
+		})
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectlist
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	EventFormatSectionKey = "event_format"
+
+	EventFormatPlainText = "text" //the MESSAGE field of the entry
+	EventFormatJSON      = "json" //all fields of the entry
+)
+
+type EventFormat struct {
+}
+
+func (r *EventFormat) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(EventFormatSectionKey, "", input)
+	if returnVal == "" {
+		return
+	}
+	if returnVal != EventFormatPlainText && returnVal != EventFormatJSON {
+		translator.AddErrorMessages(GetCurPath()+EventFormatSectionKey, fmt.Sprintf("event_format value %s is not a valid value.", returnVal))
+		return
+	}
+	returnKey = EventFormatSectionKey
+	return
+}
+
+func init() {
+	r := new(EventFormat)
+	RegisterRule(EventFormatSectionKey, r)
+}
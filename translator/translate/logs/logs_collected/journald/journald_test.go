@@ -0,0 +1,51 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package journald
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+)
+
+func TestApplyRule(t *testing.T) {
+	j := new(Journald)
+	var rawJsonString = `
+{
+	"journald": {
+        "collect_list": [
+          {
+            "units": ["sshd.service"],
+            "log_group_name": "journal/{_SYSTEMD_UNIT}"
+          }
+        ]
+      }
+}
+`
+	var input interface{}
+
+	var expected = map[string]interface{}{
+		"journald": []interface{}{
+			map[string]interface{}{
+				"destination":       "cloudwatchlogs",
+				"file_state_folder": "/opt/aws/amazon-cloudwatch-agent/logs/state",
+			},
+		},
+	}
+
+	var actual interface{}
+
+	err := json.Unmarshal([]byte(rawJsonString), &input)
+	if err == nil {
+		context.CurrentContext().SetOs(config.OS_TYPE_LINUX)
+		_, actual = j.ApplyRule(input)
+		assert.Equal(t, expected, actual)
+	} else {
+		panic(err)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectlist
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+func TestApplyRule(t *testing.T) {
+	c := new(CollectList)
+	var rawJsonString = `
+{
+    "collect_list": [
+      {
+        "service_address": ":514",
+        "log_group_name": "syslog/{facility}",
+        "log_stream_name": "{syslog_hostname}",
+        "retention_in_days": 7,
+        "filters": [{"type": "exclude", "expression": "DEBUG"}]
+      },
+      {
+        "protocol": "tls",
+        "service_address": ":6514",
+        "format": "rfc5424",
+        "event_format": "json",
+        "max_message_size": 8192,
+        "tls": {
+          "cert_file": "/etc/ssl/syslog.pem",
+          "key_file": "/etc/ssl/syslog.key",
+          "ca_file": "/etc/ssl/ca.pem"
+        },
+        "log_group_name": "syslog/{app_name}"
+      }
+    ]
+}
+`
+	var input interface{}
+
+	var expected = []interface{}{
+		map[string]interface{}{
+			"service_address":   ":514",
+			"log_group_name":    "syslog/{facility}",
+			"log_stream_name":   "{syslog_hostname}",
+			"retention_in_days": 7,
+			"log_group_class":   "",
+			"filters": []interface{}{
+				map[string]interface{}{"type": "exclude", "expression": "DEBUG"},
+			},
+		},
+		map[string]interface{}{
+			"protocol":            "tls",
+			"service_address":     ":6514",
+			"format":              "rfc5424",
+			"event_format":        "json",
+			"max_message_size":    8192,
+			"tls_cert":            "/etc/ssl/syslog.pem",
+			"tls_key":             "/etc/ssl/syslog.key",
+			"tls_allowed_cacerts": []interface{}{"/etc/ssl/ca.pem"},
+			"log_group_name":      "syslog/{app_name}",
+			"retention_in_days":   -1,
+			"log_group_class":     "",
+		},
+	}
+
+	var actual interface{}
+
+	err := json.Unmarshal([]byte(rawJsonString), &input)
+	if err == nil {
+		_, actual = c.ApplyRule(input)
+		assert.Equal(t, expected, actual)
+	} else {
+		panic(err)
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	testCases := map[string]struct {
+		input string
+		want  string
+	}{
+		"InvalidProtocol": {
+			input: `{"collect_list": [{"protocol": "sctp", "service_address": ":514", "log_group_name": "syslog"}]}`,
+			want:  "Under path : /logs/logs_collected/syslog/collect_list/protocol | Error : protocol value sctp is not a valid value.",
+		},
+		"TLSWithoutCertificate": {
+			input: `{"collect_list": [{"protocol": "tls", "service_address": ":6514", "log_group_name": "syslog"}]}`,
+			want:  "Under path : /logs/logs_collected/syslog/collect_list/tls | Error : tls with cert_file and key_file is required for the tls protocol.",
+		},
+		"TLSWithoutKey": {
+			input: `{"collect_list": [{"protocol": "tls", "service_address": ":6514", "tls": {"cert_file": "/etc/ssl/syslog.pem"}, "log_group_name": "syslog"}]}`,
+			want:  "Under path : /logs/logs_collected/syslog/collect_list/tls | Error : tls requires both cert_file and key_file.",
+		},
+		"InvalidFormat": {
+			input: `{"collect_list": [{"format": "rfc3339", "service_address": ":514", "log_group_name": "syslog"}]}`,
+			want:  "Under path : /logs/logs_collected/syslog/collect_list/format | Error : format value rfc3339 is not a valid value.",
+		},
+		"InvalidEventFormat": {
+			input: `{"collect_list": [{"event_format": "xml", "service_address": ":514", "log_group_name": "syslog"}]}`,
+			want:  "Under path : /logs/logs_collected/syslog/collect_list/event_format | Error : event_format value xml is not a valid value.",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			translator.ResetMessages()
+			var input interface{}
+			assert.NoError(t, json.Unmarshal([]byte(testCase.input), &input))
+			new(CollectList).ApplyRule(input)
+			assert.Contains(t, translator.ErrorMessages, testCase.want)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectlist
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	EventFormatSectionKey = "event_format"
+
+	EventFormatRaw       = "raw"  //the message as received
+	EventFormatPlainText = "text" //the MSG part of the message
+	EventFormatJSON      = "json" //all fields of the message
+)
+
+type EventFormat struct {
+}
+
+func (r *EventFormat) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(EventFormatSectionKey, "", input)
+	if returnVal == "" {
+		return
+	}
+	if returnVal != EventFormatRaw && returnVal != EventFormatPlainText && returnVal != EventFormatJSON {
+		translator.AddErrorMessages(GetCurPath()+EventFormatSectionKey, fmt.Sprintf("event_format value %s is not a valid value.", returnVal))
+		return
+	}
+	returnKey = EventFormatSectionKey
+	return
+}
+
+func init() {
+	r := new(EventFormat)
+	RegisterRule(EventFormatSectionKey, r)
+}
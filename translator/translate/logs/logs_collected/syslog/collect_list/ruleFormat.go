@@ -0,0 +1,39 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectlist
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	FormatSectionKey = "format"
+
+	FormatAuto    = "auto"    //detected from each message
+	FormatRFC5424 = "rfc5424" //the syslog protocol
+	FormatRFC3164 = "rfc3164" //the BSD syslog protocol
+)
+
+type Format struct {
+}
+
+func (r *Format) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(FormatSectionKey, "", input)
+	if returnVal == "" {
+		return
+	}
+	if returnVal != FormatAuto && returnVal != FormatRFC5424 && returnVal != FormatRFC3164 {
+		translator.AddErrorMessages(GetCurPath()+FormatSectionKey, fmt.Sprintf("format value %s is not a valid value.", returnVal))
+		return
+	}
+	returnKey = FormatSectionKey
+	return
+}
+
+func init() {
+	r := new(Format)
+	RegisterRule(FormatSectionKey, r)
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectlist
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const MaxMessageSizeSectionKey = "max_message_size"
+
+type MaxMessageSize struct {
+}
+
+func (r *MaxMessageSize) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if _, ok := im[MaxMessageSizeSectionKey]; !ok {
+		return
+	}
+	return translator.DefaultIntegralCase(MaxMessageSizeSectionKey, float64(0), input)
+}
+
+func init() {
+	r := new(MaxMessageSize)
+	RegisterRule(MaxMessageSizeSectionKey, r)
+}
@@ -0,0 +1,45 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectlist
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	ProtocolSectionKey = "protocol"
+
+	ProtocolUDP = "udp"
+	ProtocolTCP = "tcp"
+	ProtocolTLS = "tls"
+)
+
+type Protocol struct {
+}
+
+func (r *Protocol) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, returnVal = translator.DefaultCase(ProtocolSectionKey, "", input)
+	if returnVal == "" {
+		return
+	}
+	if returnVal != ProtocolUDP && returnVal != ProtocolTCP && returnVal != ProtocolTLS {
+		translator.AddErrorMessages(GetCurPath()+ProtocolSectionKey, fmt.Sprintf("protocol value %s is not a valid value.", returnVal))
+		return
+	}
+	if returnVal == ProtocolTLS {
+		im := input.(map[string]interface{})
+		if _, ok := im[TLSSectionKey]; !ok {
+			translator.AddErrorMessages(GetCurPath()+TLSSectionKey, "tls with cert_file and key_file is required for the tls protocol.")
+		}
+	}
+	returnKey = ProtocolSectionKey
+	return
+}
+
+func init() {
+	r := new(Protocol)
+	RegisterRule(ProtocolSectionKey, r)
+}
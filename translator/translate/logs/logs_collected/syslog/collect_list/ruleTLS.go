@@ -0,0 +1,37 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectlist
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	TLSSectionKey = "tls"
+
+	certFileKey = "cert_file"
+	keyFileKey  = "key_file"
+	caFileKey   = "ca_file"
+)
+
+// setTLSConfig flattens the tls section into the listener config, where the
+// server certificate and the CA to verify client certificates are set.
+func setTLSConfig(input interface{}, result map[string]interface{}) {
+	im := input.(map[string]interface{})
+	tlsConfig, ok := im[TLSSectionKey].(map[string]interface{})
+	if !ok {
+		return
+	}
+	_, certFile := translator.DefaultCase(certFileKey, "", tlsConfig)
+	_, keyFile := translator.DefaultCase(keyFileKey, "", tlsConfig)
+	if certFile == "" || keyFile == "" {
+		translator.AddErrorMessages(GetCurPath()+TLSSectionKey, "tls requires both cert_file and key_file.")
+		return
+	}
+	result["tls_cert"] = certFile
+	result["tls_key"] = keyFile
+	if _, caFile := translator.DefaultCase(caFileKey, "", tlsConfig); caFile != "" {
+		result["tls_allowed_cacerts"] = []interface{}{caFile}
+	}
+}
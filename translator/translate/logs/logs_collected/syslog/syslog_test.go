@@ -0,0 +1,41 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package syslog
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRule(t *testing.T) {
+	s := new(Syslog)
+	var rawJsonString = `
+{
+	"syslog": {
+        "collect_list": [
+          {
+            "service_address": ":514",
+            "log_group_name": "syslog/{facility}"
+          }
+        ]
+      }
+}
+`
+	var input interface{}
+	require.NoError(t, json.Unmarshal([]byte(rawJsonString), &input))
+
+	var expected = map[string]interface{}{
+		"syslog": []interface{}{
+			map[string]interface{}{
+				"destination": "cloudwatchlogs",
+			},
+		},
+	}
+	key, actual := s.ApplyRule(input)
+	assert.Equal(t, "inputs", key)
+	assert.Equal(t, expected, actual)
+}
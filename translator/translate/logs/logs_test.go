@@ -10,6 +10,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/aws/amazon-cloudwatch-agent/translator"
 	"github.com/aws/amazon-cloudwatch-agent/translator/config"
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
@@ -200,3 +201,102 @@ func TestLogs_EndpointOverride(t *testing.T) {
 
 	ctx.SetMode(config.ModeEC2) //reset back to default mode
 }
+
+func TestLogs_LocalLogs(t *testing.T) {
+	l := new(Logs)
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.RegionType = "any"
+
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"logs":{"log_stream_name":"LOG_STREAM_NAME","locallogs":{"output":"stdout","max_files":2}}}`), &input)
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+
+	context.CurrentContext().SetMode(config.ModeEC2)
+	_, actual := l.ApplyRule(input)
+	expected := map[string]interface{}{
+		"outputs": map[string]interface{}{
+			"cloudwatchlogs": []interface{}{
+				map[string]interface{}{
+					"region":               "us-east-1",
+					"region_type":          "any",
+					"mode":                 "EC2",
+					"log_stream_name":      "LOG_STREAM_NAME",
+					"force_flush_interval": "5s",
+				},
+			},
+			"locallogs": []interface{}{
+				map[string]interface{}{
+					"output":           "stdout",
+					"directory":        "/opt/aws/amazon-cloudwatch-agent/logs/locallogs",
+					"max_file_size_mb": 100,
+					"max_files":        2,
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestLogs_KinesisLogs(t *testing.T) {
+	l := new(Logs)
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.RegionType = "any"
+
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"logs":{"log_stream_name":"LOG_STREAM_NAME","kinesislogs":{"service":"kinesis","stream_name":"app-stream","region":"us-west-2","endpoint_override":"http://localhost:4566","aggregate":true}}}`), &input)
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+
+	context.CurrentContext().SetMode(config.ModeEC2)
+	_, actual := l.ApplyRule(input)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"region":               "us-west-2",
+			"region_type":          "any",
+			"mode":                 "EC2",
+			"service":              "kinesis",
+			"stream_name":          "app-stream",
+			"endpoint_override":    "http://localhost:4566",
+			"aggregate":            true,
+			"force_flush_interval": "5s",
+		},
+	}, actual.(map[string]interface{})["outputs"].(map[string]interface{})["kinesislogs"])
+
+	translator.ResetMessages()
+	err = json.Unmarshal([]byte(`{"logs":{"kinesislogs":{"service":"sqs"}}}`), &input)
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+	l.ApplyRule(input)
+	assert.Len(t, translator.ErrorMessages, 2)
+	translator.ResetMessages()
+}
+
+func TestLogs_OtlpExporter(t *testing.T) {
+	l := new(Logs)
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.RegionType = "any"
+	context.CurrentContext().SetMode(config.ModeEC2)
+
+	var input interface{}
+	translator.ResetMessages()
+	err := json.Unmarshal([]byte(`{"logs":{"metrics_collected":{"emf":{}},"otlp_exporter":{"endpoint":"localhost:4317"}}}`), &input)
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+	l.ApplyRule(input)
+	assert.Empty(t, translator.ErrorMessages)
+
+	// the log events of logs_collected are not exported with the OTLP exporter
+	err = json.Unmarshal([]byte(`{"logs":{"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/messages"}]}},"otlp_exporter":{"endpoint":"localhost:4317"}}}`), &input)
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+	l.ApplyRule(input)
+	assert.Len(t, translator.ErrorMessages, 1)
+	assert.Contains(t, translator.ErrorMessages[0], "otlp_exporter only exports EMF and structured logs")
+	translator.ResetMessages()
+}
@@ -0,0 +1,71 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+)
+
+const (
+	KinesisLogsSectionKey = "kinesislogs"
+
+	KinesisLogsServiceFirehose = "firehose"
+	KinesisLogsServiceKinesis  = "kinesis"
+)
+
+type KinesisLogs struct {
+}
+
+// The kinesis logs output puts the log events of the collect_list entries with
+// the kinesislogs destination to a Firehose delivery stream or a Kinesis data
+// stream, with the credentials and the region of the cloudwatchlogs output.
+func (r *KinesisLogs) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	section, ok := im[KinesisLogsSectionKey].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	res := map[string]interface{}{}
+	res = translator.MergeTwoUniqueMaps(res, agent.Global_Config.Credentials)
+	_, creds := new(LogCreds).ApplyRule(input)
+	res = translator.MergeTwoUniqueMaps(res, creds.(map[string]interface{}))
+	res[agent.RegionType] = agent.Global_Config.RegionType
+	res[agent.Mode] = context.CurrentContext().ShortMode()
+
+	key, val := translator.DefaultCase(agent.RegionKey, agent.Global_Config.Region, section)
+	res[key] = val
+	key, val = translator.DefaultCase("service", KinesisLogsServiceFirehose, section)
+	switch val {
+	case KinesisLogsServiceFirehose, KinesisLogsServiceKinesis:
+	default:
+		translator.AddErrorMessages(GetCurPath()+KinesisLogsSectionKey+"/"+key, fmt.Sprintf("service value %v is not a valid value.", val))
+	}
+	res[key] = val
+	key, val = translator.DefaultCase("stream_name", "", section)
+	if val == "" {
+		translator.AddErrorMessages(GetCurPath()+KinesisLogsSectionKey+"/"+key, "stream_name is required.")
+	}
+	res[key] = val
+	if key, val = translator.DefaultCase("endpoint_override", "", section); val != "" {
+		res[key] = val
+	}
+	key, val = translator.DefaultCase("aggregate", false, section)
+	res[key] = val
+	key, val = translator.DefaultTimeIntervalCase("force_flush_interval", float64(5), section)
+	res[key] = val
+
+	returnKey = Output_Kinesis_Logs
+	returnVal = res
+	return
+}
+
+func init() {
+	r := new(KinesisLogs)
+	RegisterRule(KinesisLogsSectionKey, r)
+}
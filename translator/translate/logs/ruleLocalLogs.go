@@ -0,0 +1,58 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/util"
+)
+
+const (
+	LocalLogsSectionKey = "locallogs"
+
+	LocalLogsOutputFile   = "file"
+	LocalLogsOutputStdout = "stdout"
+)
+
+type LocalLogs struct {
+}
+
+// The local logs output writes the log events of the collect_list entries with
+// the locallogs destination to local files or the standard output.
+func (r *LocalLogs) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if _, ok := im[LocalLogsSectionKey]; !ok {
+		return
+	}
+	returnKey = Output_Local_Logs
+	returnVal = localLogsConfig(im[LocalLogsSectionKey])
+	return
+}
+
+// localLogsConfig returns the config of the local logs output with the defaults
+// of the keys not in the input.
+func localLogsConfig(input interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+	key, val := translator.DefaultCase("output", LocalLogsOutputFile, input)
+	switch val {
+	case LocalLogsOutputFile, LocalLogsOutputStdout:
+	default:
+		translator.AddErrorMessages(GetCurPath()+LocalLogsSectionKey+"/"+key, fmt.Sprintf("output value %v is not a valid value.", val))
+	}
+	res[key] = val
+	key, val = translator.DefaultCase("directory", util.GetLocalLogsFolder(), input)
+	res[key] = val
+	key, val = translator.DefaultIntegralCase("max_file_size_mb", float64(100), input)
+	res[key] = val
+	key, val = translator.DefaultIntegralCase("max_files", float64(5), input)
+	res[key] = val
+	return res
+}
+
+func init() {
+	r := new(LocalLogs)
+	RegisterRule(LocalLogsSectionKey, r)
+}
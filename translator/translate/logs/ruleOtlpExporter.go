@@ -0,0 +1,49 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"log"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	OtlpExporterSectionKey = "otlp_exporter"
+
+	metricsCollectedSectionKey = "metrics_collected"
+	logsCollectedSectionKey    = "logs_collected"
+	emfSectionKey              = "emf"
+	structuredLogSectionKey    = "structuredlog"
+)
+
+type OtlpExporter struct {
+}
+
+// The OTLP exporter of the logs section is only part of the EMF logs pipeline.
+// The log events of logs_collected are published by the cloudwatchlogs output
+// and are not exported with it, so the exporter is rejected without an EMF or
+// structured log receiver.
+func (r *OtlpExporter) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if _, ok := im[OtlpExporterSectionKey]; !ok {
+		return
+	}
+	metricsCollected, _ := im[metricsCollectedSectionKey].(map[string]interface{})
+	_, hasEmf := metricsCollected[emfSectionKey]
+	_, hasStructuredLog := metricsCollected[structuredLogSectionKey]
+	if !hasEmf && !hasStructuredLog {
+		translator.AddErrorMessages(GetCurPath()+OtlpExporterSectionKey, "otlp_exporter only exports EMF and structured logs, so metrics_collected must include emf or structuredlog.")
+		return
+	}
+	if _, ok := im[logsCollectedSectionKey]; ok {
+		log.Printf("W! The log events of logs_collected are not exported with logs otlp_exporter, only EMF and structured logs are.")
+	}
+	return
+}
+
+func init() {
+	r := new(OtlpExporter)
+	RegisterRule(OtlpExporterSectionKey, r)
+}
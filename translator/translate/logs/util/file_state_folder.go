@@ -9,7 +9,10 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator/util"
 )
 
-const File_State_Folder_Linux = "/opt/aws/amazon-cloudwatch-agent/logs/state"
+const (
+	File_State_Folder_Linux = "/opt/aws/amazon-cloudwatch-agent/logs/state"
+	Local_Logs_Folder_Linux = "/opt/aws/amazon-cloudwatch-agent/logs/locallogs"
+)
 
 func GetFileStateFolder() (fileStateFolder string) {
 	if translator.GetTargetPlatform() == config.OS_TYPE_WINDOWS {
@@ -19,3 +22,12 @@ func GetFileStateFolder() (fileStateFolder string) {
 	}
 	return
 }
+
+func GetLocalLogsFolder() (localLogsFolder string) {
+	if translator.GetTargetPlatform() == config.OS_TYPE_WINDOWS {
+		localLogsFolder = util.GetWindowsProgramDataPath() + "\\Amazon\\AmazonCloudWatchAgent\\Logs\\locallogs"
+	} else {
+		localLogsFolder = Local_Logs_Folder_Linux
+	}
+	return
+}
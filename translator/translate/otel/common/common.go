@@ -59,18 +59,25 @@ const (
 	Region                             = "region"
 	LogGroupName                       = "log_group_name"
 	LogStreamName                      = "log_stream_name"
+	HealthMetricsKey                   = "health_metrics"
+	PrometheusExporterKey              = "prometheus_exporter"
+	OtlpExporterKey                    = "otlp_exporter"
 )
 
 const (
 	PipelineNameHost             = "host"
 	PipelineNameHostDeltaMetrics = "hostDeltaMetrics"
 	PipelineNameEmfLogs          = "emf_logs"
+	PipelineNameAgentHealth      = "agenthealth"
 	AppSignals                   = "application_signals"
 	AppSignalsFallback           = "app_signals"
 	AppSignalsRules              = "rules"
 )
 
 var (
+	AgentHealthMetricsKey        = ConfigKey(AgentKey, HealthMetricsKey)
+	MetricsPrometheusExporterKey = ConfigKey(MetricsKey, PrometheusExporterKey)
+
 	AppSignalsTraces          = ConfigKey(TracesKey, TracesCollectedKey, AppSignals)
 	AppSignalsMetrics         = ConfigKey(LogsKey, MetricsCollectedKey, AppSignals)
 	AppSignalsTracesFallback  = ConfigKey(TracesKey, TracesCollectedKey, AppSignalsFallback)
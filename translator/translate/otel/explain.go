@@ -0,0 +1,548 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otel
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"gopkg.in/yaml.v3"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/mapstructure"
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	// tomlOwner is the owner of the output of the TOML rule registry.
+	tomlOwner = "toml"
+	// otelOwner is the owner of errors that stop the OTEL translation as a
+	// whole instead of a single pipeline or component.
+	otelOwner = "otel"
+)
+
+// Explanation describes the OTEL pipelines translated from a JSON config
+// along with the pipelines that were skipped, the JSON keys translated into
+// the TOML config instead, and the JSON keys that were not used by either.
+type Explanation struct {
+	Pipelines  []PipelineExplanation `json:"pipelines"`
+	Skipped    []SkippedPipeline     `json:"skipped,omitempty"`
+	TomlKeys   []string              `json:"toml_keys,omitempty"`
+	UnusedKeys []string              `json:"unused_keys,omitempty"`
+}
+
+// PipelineExplanation is a translated pipeline and its components.
+type PipelineExplanation struct {
+	ID         string                 `json:"id"`
+	JsonKeys   []string               `json:"json_keys,omitempty"`
+	Receivers  []ComponentExplanation `json:"receivers"`
+	Processors []ComponentExplanation `json:"processors"`
+	Exporters  []ComponentExplanation `json:"exporters"`
+	Extensions []ComponentExplanation `json:"extensions"`
+}
+
+// ComponentExplanation is a translated component. The config contains the
+// effective values, including defaults that were not set in the JSON.
+type ComponentExplanation struct {
+	ID       string         `json:"id"`
+	JsonKeys []string       `json:"json_keys,omitempty"`
+	Config   map[string]any `json:"config,omitempty"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// SkippedPipeline is a pipeline that was not translated and the reason why.
+type SkippedPipeline struct {
+	ID      string `json:"id"`
+	JsonKey string `json:"json_key,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+// translation is the output of each pipeline, component and the TOML rule
+// registry for a JSON config, keyed by owner.
+type translation map[string]string
+
+// Explain translates the JSON config like Translate, but instead of building
+// an OTEL config, it describes which pipelines and components were created,
+// which JSON keys they read, and why other pipelines were skipped.
+//
+// The keys that a pipeline, component or the TOML config reads are found by
+// removing or changing each key in the JSON config and translating it again.
+// A key is read by every owner whose output changes as a result. Keys that
+// don't change any output are reported as unused.
+func Explain(jsonConfig interface{}, os string) (*Explanation, error) {
+	m, ok := jsonConfig.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid json config")
+	}
+	explanation, base, err := explainPipelines(confmap.NewFromStringMap(copyValue(m).(map[string]interface{})), os)
+	if err != nil {
+		return nil, err
+	}
+	p := &keyProber{
+		os:      os,
+		state:   saveTranslatorState(),
+		base:    base,
+		readers: map[string][]string{},
+	}
+	defer p.state.restore()
+	writer := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(writer)
+
+	base[tomlOwner] = p.translateToml(copyValue(m))
+	p.unstable = unstableOwners(base, p.translate(m))
+	for _, key := range sortedKeys(m) {
+		_, unused := p.walk(m, []string{key}, key, m[key])
+		explanation.UnusedKeys = append(explanation.UnusedKeys, unused...)
+	}
+
+	otelKeys := map[string]bool{}
+	for owner, keys := range p.readers {
+		if owner == tomlOwner {
+			continue
+		}
+		for _, key := range keys {
+			otelKeys[key] = true
+		}
+	}
+	var tomlKeys []string
+	for _, key := range p.readers[tomlOwner] {
+		if !otelKeys[key] {
+			tomlKeys = append(tomlKeys, key)
+		}
+	}
+	explanation.TomlKeys = minimalKeys(tomlKeys)
+	for i := range explanation.Pipelines {
+		pe := &explanation.Pipelines[i]
+		pe.JsonKeys = minimalKeys(p.readers[pipelineOwner(pe.ID)])
+		for _, components := range []struct {
+			kind string
+			list []ComponentExplanation
+		}{
+			{"receivers", pe.Receivers},
+			{"processors", pe.Processors},
+			{"exporters", pe.Exporters},
+			{"extensions", pe.Extensions},
+		} {
+			for j := range components.list {
+				c := &components.list[j]
+				c.JsonKeys = minimalKeys(p.readers[componentOwner(pe.ID, components.kind, c.ID)])
+			}
+		}
+	}
+	return explanation, nil
+}
+
+// explainPipelines translates each pipeline and its components and records
+// the output of each of them.
+func explainPipelines(conf *confmap.Conf, os string) (*Explanation, translation, error) {
+	translators, err := pipelineTranslators(conf, os)
+	if err != nil {
+		return nil, nil, err
+	}
+	explanation := &Explanation{}
+	outputs := translation{}
+	translators.Range(func(pt common.Translator[*common.ComponentTranslators]) {
+		id := pt.ID().String()
+		pipeline, err := pt.Translate(conf)
+		if pipeline == nil {
+			reason := "no components configured"
+			if err != nil {
+				reason = err.Error()
+			}
+			var jsonKey string
+			var mke *common.MissingKeyError
+			if errors.As(err, &mke) {
+				jsonKey = mke.JsonKey
+			}
+			explanation.Skipped = append(explanation.Skipped, SkippedPipeline{
+				ID:      id,
+				JsonKey: jsonKey,
+				Reason:  reason,
+			})
+			outputs[pipelineOwner(id)] = "skipped: " + reason
+			return
+		}
+		pe := PipelineExplanation{
+			ID:         id,
+			Receivers:  explainComponents(conf, pipeline.Receivers, id, "receivers", outputs),
+			Processors: explainComponents(conf, pipeline.Processors, id, "processors", outputs),
+			Exporters:  explainComponents(conf, pipeline.Exporters, id, "exporters", outputs),
+			Extensions: explainComponents(conf, pipeline.Extensions, id, "extensions", outputs),
+		}
+		var ids []string
+		for _, components := range [][]ComponentExplanation{pe.Receivers, pe.Processors, pe.Exporters, pe.Extensions} {
+			for _, c := range components {
+				ids = append(ids, c.ID)
+			}
+			ids = append(ids, "|")
+		}
+		outputs[pipelineOwner(id)] = strings.Join(ids, ",")
+		explanation.Pipelines = append(explanation.Pipelines, pe)
+	})
+	return explanation, outputs, nil
+}
+
+// explainComponents translates each component in the map and records the
+// effective configuration or the translation error.
+func explainComponents(conf *confmap.Conf, translators common.TranslatorMap[component.Config], pipelineID, kind string, outputs translation) []ComponentExplanation {
+	var components []ComponentExplanation
+	if translators == nil {
+		return components
+	}
+	translators.Range(func(t common.Translator[component.Config]) {
+		ce := ComponentExplanation{ID: t.ID().String()}
+		cfg, err := t.Translate(conf)
+		if err != nil {
+			ce.Error = err.Error()
+		} else if ce.Config, err = mapstructure.Marshal(cfg); err != nil {
+			ce.Error = fmt.Sprintf("unable to marshal config: %v", err)
+		}
+		output := "error: " + ce.Error
+		if ce.Error == "" {
+			output = fmt.Sprint(ce.Config)
+		}
+		outputs[componentOwner(pipelineID, kind, ce.ID)] = output
+		components = append(components, ce)
+	})
+	return components
+}
+
+func pipelineOwner(pipelineID string) string {
+	return "pipeline/" + pipelineID
+}
+
+func componentOwner(pipelineID, kind, componentID string) string {
+	return strings.Join([]string{pipelineID, kind, componentID}, "/")
+}
+
+// translatorState is the global state of the TOML rule registry that is
+// modified by a translation.
+type translatorState struct {
+	agentConfig   agent.Agent
+	logConfig     logs.Logs
+	errorMessages []string
+	infoMessages  []string
+}
+
+func saveTranslatorState() translatorState {
+	return translatorState{
+		agentConfig:   agent.Global_Config,
+		logConfig:     logs.GlobalLogConfig,
+		errorMessages: translator.ErrorMessages,
+		infoMessages:  translator.InfoMessages,
+	}
+}
+
+func (s translatorState) restore() {
+	agent.Global_Config = s.agentConfig
+	logs.GlobalLogConfig = s.logConfig
+	translator.ErrorMessages = s.errorMessages
+	translator.InfoMessages = s.infoMessages
+}
+
+// keyProber finds the owners that read each key in the JSON config.
+type keyProber struct {
+	os    string
+	state translatorState
+	base  translation
+	// unstable owners have a different output each time they are translated,
+	// so they are ignored when comparing translations.
+	unstable map[string]bool
+	// readers are the keys read by each owner. Keys in lists are recorded
+	// as the key of the list.
+	readers map[string][]string
+}
+
+// walk probes the value at the path and the values nested under it. It
+// returns whether any of them is used and the shallowest unused keys.
+func (p *keyProber) walk(root map[string]interface{}, path []string, readKey string, value interface{}) (bool, []string) {
+	var childUsed bool
+	var unused []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			used, childUnused := p.walk(root, appendPath(path, key), common.ConfigKey(readKey, key), v[key])
+			childUsed = childUsed || used
+			unused = append(unused, childUnused...)
+		}
+	case []interface{}:
+		for i, child := range v {
+			used, childUnused := p.walk(root, appendPath(path, strconv.Itoa(i)), readKey, child)
+			childUsed = childUsed || used
+			unused = append(unused, childUnused...)
+		}
+	}
+	used := p.probe(root, path, readKey, nil, true)
+	if changed, ok := changeValue(value); ok {
+		used = p.probe(root, path, readKey, changed, false) || used
+	}
+	if !used && !childUsed {
+		return false, []string{strings.Join(path, confmap.KeyDelimiter)}
+	}
+	return true, unused
+}
+
+// probe translates the JSON config with the value at the path replaced, or
+// removed, and records the key as read by every owner whose output changed.
+func (p *keyProber) probe(root map[string]interface{}, path []string, readKey string, value interface{}, remove bool) bool {
+	probed := copyValue(root)
+	setValue(probed, path, value, remove)
+	var changed bool
+	for owner, output := range p.translate(probed) {
+		if p.unstable[owner] || p.base[owner] == output {
+			continue
+		}
+		changed = true
+		p.readers[owner] = append(p.readers[owner], readKey)
+	}
+	return changed
+}
+
+// translate translates the JSON config into the output of each owner. A
+// panic in the translation is recorded as the output of the owner that
+// panicked, since the probed value is the cause.
+func (p *keyProber) translate(m interface{}) translation {
+	outputs := translation{}
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				outputs[otelOwner] = fmt.Sprintf("panic: %v", r)
+			}
+		}()
+		conf := confmap.NewFromStringMap(copyValue(m).(map[string]interface{}))
+		_, pipelineOutputs, err := explainPipelines(conf, p.os)
+		if err != nil {
+			outputs[otelOwner] = "error: " + err.Error()
+			return
+		}
+		for owner, output := range pipelineOutputs {
+			outputs[owner] = output
+		}
+	}()
+	outputs[tomlOwner] = p.translateToml(copyValue(m))
+	for owner := range p.base {
+		if _, ok := outputs[owner]; !ok {
+			outputs[owner] = ""
+		}
+	}
+	return outputs
+}
+
+// translateToml applies the TOML rule registry to the JSON config and
+// restores the global state that the rules modify.
+func (p *keyProber) translateToml(m interface{}) (output string) {
+	defer p.state.restore()
+	defer func() {
+		if r := recover(); r != nil {
+			output = fmt.Sprintf("panic: %v", r)
+		}
+	}()
+	translator.ErrorMessages = nil
+	_, val := new(translate.Translator).ApplyRule(m)
+	if !translator.IsTranslateSuccess() {
+		return fmt.Sprintf("error: %v", translator.ErrorMessages)
+	}
+	return fmt.Sprint(val)
+}
+
+// unstableOwners returns the owners with a different output in each of the
+// translations of the same JSON config.
+func unstableOwners(first, second translation) map[string]bool {
+	unstable := map[string]bool{}
+	for owner, output := range first {
+		if second[owner] != output {
+			unstable[owner] = true
+		}
+	}
+	return unstable
+}
+
+// changeValue returns a different value of the same type.
+func changeValue(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case bool:
+		return !v, true
+	case float64:
+		return v + 1, true
+	case int:
+		return v + 1, true
+	case string:
+		return v + "_", true
+	}
+	return nil, false
+}
+
+// setValue replaces, or removes, the value at the path in place.
+func setValue(value interface{}, path []string, replacement interface{}, remove bool) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(path) > 1 {
+			v[path[0]] = setValue(v[path[0]], path[1:], replacement, remove)
+		} else if remove {
+			delete(v, path[0])
+		} else {
+			v[path[0]] = replacement
+		}
+		return v
+	case []interface{}:
+		i, err := strconv.Atoi(path[0])
+		if err != nil || i < 0 || i >= len(v) {
+			return v
+		}
+		if len(path) > 1 {
+			v[i] = setValue(v[i], path[1:], replacement, remove)
+		} else if remove {
+			return append(v[:i], v[i+1:]...)
+		} else {
+			v[i] = replacement
+		}
+		return v
+	}
+	return value
+}
+
+// copyValue deep copies the maps and lists in the JSON value.
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			c[key] = copyValue(child)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, child := range v {
+			c[i] = copyValue(child)
+		}
+		return c
+	}
+	return value
+}
+
+// minimalKeys sorts and deduplicates the keys and drops any key that has
+// another one of the keys nested under it.
+func minimalKeys(keys []string) []string {
+	var result []string
+	for _, key := range keys {
+		if containsNestedKey(key, keys) || contains(result, key) {
+			continue
+		}
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// containsNestedKey checks if one of the keys is nested under the key.
+func containsNestedKey(key string, keys []string) bool {
+	for _, other := range keys {
+		if strings.HasPrefix(other, key+confmap.KeyDelimiter) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(keys []string, key string) bool {
+	for _, other := range keys {
+		if other == key {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func appendPath(path []string, key string) []string {
+	return append(append([]string{}, path...), key)
+}
+
+// String formats the explanation as a human-readable pipeline graph.
+func (e *Explanation) String() string {
+	var sb strings.Builder
+	for _, p := range e.Pipelines {
+		fmt.Fprintf(&sb, "pipeline %s%s\n", p.ID, fromKeys(p.JsonKeys))
+		writeComponents(&sb, "receivers", p.Receivers)
+		writeComponents(&sb, "processors", p.Processors)
+		writeComponents(&sb, "exporters", p.Exporters)
+		writeComponents(&sb, "extensions", p.Extensions)
+	}
+	if len(e.Skipped) > 0 {
+		sb.WriteString("skipped pipelines:\n")
+		for _, s := range e.Skipped {
+			fmt.Fprintf(&sb, "  %s: %s\n", s.ID, s.Reason)
+		}
+	}
+	if len(e.TomlKeys) > 0 {
+		sb.WriteString("keys translated into the TOML config:\n")
+		for _, key := range e.TomlKeys {
+			fmt.Fprintf(&sb, "  %s\n", key)
+		}
+	}
+	if len(e.UnusedKeys) > 0 {
+		sb.WriteString("keys not used by any pipeline or the TOML config:\n")
+		for _, key := range e.UnusedKeys {
+			fmt.Fprintf(&sb, "  %s\n", key)
+		}
+	}
+	return sb.String()
+}
+
+func writeComponents(sb *strings.Builder, kind string, components []ComponentExplanation) {
+	if len(components) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "  %s:\n", kind)
+	for _, c := range components {
+		fmt.Fprintf(sb, "    %s%s\n", c.ID, fromKeys(c.JsonKeys))
+		if c.Error != "" {
+			fmt.Fprintf(sb, "      error: %s\n", c.Error)
+			continue
+		}
+		if len(c.Config) == 0 {
+			continue
+		}
+		out, err := yaml.Marshal(c.Config)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+			fmt.Fprintf(sb, "      %s\n", line)
+		}
+	}
+}
+
+func fromKeys(jsonKeys []string) string {
+	if len(jsonKeys) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(jsonKeys))
+	for i, key := range jsonKeys {
+		quoted[i] = strconv.Quote(key)
+	}
+	return fmt.Sprintf(" (from %s)", strings.Join(quoted, ", "))
+}
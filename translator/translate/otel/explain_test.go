@@ -0,0 +1,103 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+)
+
+func TestExplain(t *testing.T) {
+	agent.Global_Config.Region = "us-east-1"
+	translator.SetTargetPlatform("linux")
+
+	_, err := Explain("", "linux")
+	assert.ErrorContains(t, err, "invalid json config")
+
+	got, err := Explain(map[string]interface{}{
+		"agent": map[string]interface{}{
+			"metrics_collection_interval": 30.0,
+			"region":                      "us-east-1",
+			"bogusagent":                  true,
+		},
+		"metrics": map[string]interface{}{
+			"namespace": "CustomNamespace",
+			"typo_key":  "value",
+			"metrics_collected": map[string]interface{}{
+				"cpu": map[string]interface{}{
+					"measurement": []interface{}{"usage_idle"},
+					"bogus":       1.0,
+				},
+				"cpux": map[string]interface{}{
+					"measurement": []interface{}{"usage_idle"},
+				},
+			},
+		},
+		"logs": map[string]interface{}{
+			"logs_collected": map[string]interface{}{
+				"files": map[string]interface{}{
+					"collect_list": []interface{}{
+						map[string]interface{}{
+							"file_path":  "/var/log/messages",
+							"file_pathx": "/var/log/secure",
+						},
+					},
+				},
+			},
+			"force_flush_interval": 10.0,
+		},
+		"unknown": "value",
+	}, "linux")
+	require.NoError(t, err)
+	require.Len(t, got.Pipelines, 1)
+
+	host := got.Pipelines[0]
+	assert.Equal(t, "metrics/host", host.ID)
+	assert.Equal(t, []string{"metrics::metrics_collected::cpu"}, host.JsonKeys)
+	require.Len(t, host.Receivers, 1)
+	assert.Equal(t, "telegraf_cpu", host.Receivers[0].ID)
+	assert.Equal(t, []string{"agent::metrics_collection_interval", "metrics::metrics_collected::cpu"}, host.Receivers[0].JsonKeys)
+	assert.Equal(t, 30*time.Second, host.Receivers[0].Config["collection_interval"])
+	require.Len(t, host.Exporters, 1)
+	assert.Equal(t, "awscloudwatch", host.Exporters[0].ID)
+	assert.Equal(t, "CustomNamespace", host.Exporters[0].Config["namespace"])
+	assert.Equal(t, []string{"metrics::metrics_collected::cpu", "metrics::namespace"}, host.Exporters[0].JsonKeys)
+	assert.Empty(t, host.Exporters[0].Error)
+
+	var skipped []string
+	for _, s := range got.Skipped {
+		skipped = append(skipped, s.ID)
+		assert.NotEmpty(t, s.Reason)
+	}
+	assert.Contains(t, skipped, "metrics/hostDeltaMetrics")
+	assert.Contains(t, skipped, "traces/xray")
+	assert.Equal(t, []string{
+		"agent::region",
+		"logs::force_flush_interval",
+		"logs::logs_collected::files::collect_list::file_path",
+		"metrics::metrics_collected::cpu::measurement",
+	}, got.TomlKeys)
+	assert.Equal(t, []string{
+		"agent::bogusagent",
+		"logs::logs_collected::files::collect_list::0::file_pathx",
+		"metrics::metrics_collected::cpu::bogus",
+		"metrics::metrics_collected::cpux",
+		"metrics::typo_key",
+		"unknown",
+	}, got.UnusedKeys)
+
+	text := got.String()
+	assert.Contains(t, text, `pipeline metrics/host (from "metrics::metrics_collected::cpu")`)
+	assert.Contains(t, text, `telegraf_cpu (from "agent::metrics_collection_interval", "metrics::metrics_collected::cpu")`)
+	assert.Contains(t, text, "namespace: CustomNamespace")
+	assert.Contains(t, text, "skipped pipelines:")
+	assert.Contains(t, text, "keys translated into the TOML config:\n  agent::region\n")
+	assert.Contains(t, text, "keys not used by any pipeline or the TOML config:\n  agent::bogusagent\n")
+}
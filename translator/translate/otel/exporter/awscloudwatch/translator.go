@@ -21,10 +21,13 @@ import (
 
 const (
 	namespaceKey          = "namespace"
+	namespaceMappingsKey  = "namespace_mappings"
 	forceFlushIntervalKey = "force_flush_interval"
 	dropOriginalWildcard  = "*"
 
 	internalMaxValuesPerDatum = 5000
+
+	defaultHealthMetricsNamespace = "CWAgent/Health"
 )
 
 type translator struct {
@@ -46,6 +49,22 @@ func (t *translator) ID() component.ID {
 	return component.NewIDWithName(t.factory.Type(), t.name)
 }
 
+type agentHealthTranslator struct {
+	factory exporter.Factory
+}
+
+var _ common.Translator[component.Config] = (*agentHealthTranslator)(nil)
+
+// NewAgentHealthTranslator creates the exporter translator for the agent
+// health pipeline.
+func NewAgentHealthTranslator() common.Translator[component.Config] {
+	return &agentHealthTranslator{cloudwatch.NewFactory()}
+}
+
+func (t *agentHealthTranslator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), common.PipelineNameAgentHealth)
+}
+
 // Translate creates an exporter config based on the fields in the
 // metrics section of the JSON config.
 // TODO: remove dependency on global config.
@@ -76,10 +95,50 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 	if dropOriginalMetrics := getDropOriginalMetrics(conf); len(dropOriginalMetrics) != 0 {
 		cfg.DropOriginalConfigs = dropOriginalMetrics
 	}
+	if namespaceMappings := getNamespaceMappings(conf); len(namespaceMappings) != 0 {
+		cfg.NamespaceMappings = namespaceMappings
+	}
+	cfg.MiddlewareID = &agenthealth.MetricsID
+	return cfg, nil
+}
+
+// Translate creates an exporter config for the agent health metrics. It only
+// uses the connection settings from the metrics section, so the rollup and
+// drop settings for the user's metrics do not apply.
+func (t *agentHealthTranslator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if conf == nil || !conf.IsSet(common.AgentHealthMetricsKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.AgentHealthMetricsKey}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*cloudwatch.Config)
+	credentials := confmap.NewFromStringMap(agent.Global_Config.Credentials)
+	_ = credentials.Unmarshal(cfg)
+	cfg.RoleARN = getRoleARN(conf)
+	cfg.Region = agent.Global_Config.Region
+	cfg.Namespace = defaultHealthMetricsNamespace
+	if namespace, ok := common.GetString(conf, common.ConfigKey(common.AgentHealthMetricsKey, namespaceKey)); ok {
+		cfg.Namespace = namespace
+	}
+	if endpointOverride, ok := common.GetString(conf, common.ConfigKey(common.MetricsKey, common.EndpointOverrideKey)); ok {
+		cfg.EndpointOverride = endpointOverride
+	}
 	cfg.MiddlewareID = &agenthealth.MetricsID
 	return cfg, nil
 }
 
+func getNamespaceMappings(conf *confmap.Conf) map[string]string {
+	value, ok := conf.Get(common.ConfigKey(common.MetricsKey, namespaceMappingsKey)).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	namespaceMappings := make(map[string]string, len(value))
+	for prefix, namespace := range value {
+		if s, ok := namespace.(string); ok && s != "" {
+			namespaceMappings[prefix] = s
+		}
+	}
+	return namespaceMappings
+}
+
 func getRoleARN(conf *confmap.Conf) string {
 	key := common.ConfigKey(common.MetricsKey, common.CredentialsKey, common.RoleARNKey)
 	roleARN, ok := common.GetString(conf, key)
@@ -63,6 +63,22 @@ func TestTranslator(t *testing.T) {
 				RoleARN:            "global_arn",
 			},
 		},
+		"WithNamespaceMappings": {
+			input: map[string]interface{}{"metrics": map[string]interface{}{
+				"namespace_mappings": map[string]interface{}{
+					"app_": "App",
+					"bad":  1,
+				},
+			}},
+			want: &cloudwatch.Config{
+				Namespace:          "CWAgent",
+				Region:             "us-east-1",
+				ForceFlushInterval: time.Minute,
+				MaxValuesPerDatum:  150,
+				RoleARN:            "global_arn",
+				NamespaceMappings:  map[string]string{"app_": "App"},
+			},
+		},
 		"WithInvalidCredentialFields": {
 			input: map[string]interface{}{"metrics": map[string]interface{}{}},
 			credentials: map[string]interface{}{
@@ -159,6 +175,7 @@ func TestTranslator(t *testing.T) {
 				assert.Equal(t, testCase.want.SharedCredentialFilename, gotCfg.SharedCredentialFilename)
 				assert.Equal(t, testCase.want.MaxValuesPerDatum, gotCfg.MaxValuesPerDatum)
 				assert.Equal(t, testCase.want.RollupDimensions, gotCfg.RollupDimensions)
+				assert.Equal(t, testCase.want.NamespaceMappings, gotCfg.NamespaceMappings)
 				assert.NotNil(t, gotCfg.MiddlewareID)
 				assert.Equal(t, "agenthealth/metrics", gotCfg.MiddlewareID.String())
 				if testCase.wantWindows != nil && runtime.GOOS == "windows" {
@@ -171,6 +188,61 @@ func TestTranslator(t *testing.T) {
 	}
 }
 
+func TestTranslatorAgentHealth(t *testing.T) {
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.Role_arn = "global_arn"
+	cwt := NewAgentHealthTranslator()
+	require.EqualValues(t, "awscloudwatch/agenthealth", cwt.ID().String())
+	testCases := map[string]struct {
+		input         map[string]interface{}
+		wantNamespace string
+		wantEndpoint  string
+		wantErr       error
+	}{
+		"WithMissingKey": {
+			input: map[string]interface{}{"metrics": map[string]interface{}{}},
+			wantErr: &common.MissingKeyError{
+				ID:      cwt.ID(),
+				JsonKey: common.AgentHealthMetricsKey,
+			},
+		},
+		"WithDefaultNamespace": {
+			input: map[string]interface{}{
+				"agent": map[string]interface{}{"health_metrics": map[string]interface{}{}},
+			},
+			wantNamespace: "CWAgent/Health",
+		},
+		"WithNamespace": {
+			input: map[string]interface{}{
+				"agent": map[string]interface{}{"health_metrics": map[string]interface{}{"namespace": "Fleet/Agent"}},
+				"metrics": map[string]interface{}{
+					"namespace":              "CWAgent",
+					"endpoint_override":      "https://monitoring-fips.us-east-1.amazonaws.com",
+					"aggregation_dimensions": []interface{}{[]interface{}{"InstanceId"}},
+				},
+			},
+			wantNamespace: "Fleet/Agent",
+			wantEndpoint:  "https://monitoring-fips.us-east-1.amazonaws.com",
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := cwt.Translate(confmap.NewFromStringMap(testCase.input))
+			require.Equal(t, testCase.wantErr, err)
+			if testCase.wantErr == nil {
+				gotCfg, ok := got.(*cloudwatch.Config)
+				require.True(t, ok)
+				assert.Equal(t, testCase.wantNamespace, gotCfg.Namespace)
+				assert.Equal(t, testCase.wantEndpoint, gotCfg.EndpointOverride)
+				assert.Equal(t, "us-east-1", gotCfg.Region)
+				assert.Equal(t, "global_arn", gotCfg.RoleARN)
+				assert.Nil(t, gotCfg.RollupDimensions)
+				assert.Equal(t, "agenthealth/metrics", gotCfg.MiddlewareID.String())
+			}
+		})
+	}
+}
+
 func getJson(t *testing.T, path string) map[string]interface{} {
 	t.Helper()
 
@@ -0,0 +1,158 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlp
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/collector/exporter/otlphttpexporter"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/tls"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	ProtocolGRPC = "grpc"
+	ProtocolHTTP = "http"
+
+	endpointKey           = "endpoint"
+	protocolKey           = "protocol"
+	headersKey            = "headers"
+	compressionKey        = "compression"
+	tlsCAKey              = "tls_ca"
+	tlsCertKey            = "tls_cert"
+	tlsKeyKey             = "tls_key"
+	insecureSkipVerifyKey = "insecure_skip_verify"
+)
+
+var (
+	configKeys = map[component.DataType]string{
+		component.DataTypeMetrics: common.ConfigKey(common.MetricsKey, common.OtlpExporterKey),
+		component.DataTypeLogs:    common.ConfigKey(common.LogsKey, common.OtlpExporterKey),
+		component.DataTypeTraces:  common.ConfigKey(common.TracesKey, common.OtlpExporterKey),
+	}
+)
+
+type translator struct {
+	dataType component.DataType
+	factory  exporter.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+// IsSet returns true if an OTLP exporter is configured in the section of the
+// data type.
+func IsSet(conf *confmap.Conf, dataType component.DataType) bool {
+	key, ok := configKeys[dataType]
+	return ok && conf.IsSet(key)
+}
+
+// Protocol returns the configured protocol of the OTLP exporter for the data
+// type. Defaults to gRPC.
+func Protocol(conf *confmap.Conf, dataType component.DataType) string {
+	if protocol, ok := common.GetString(conf, common.ConfigKey(configKeys[dataType], protocolKey)); ok {
+		return protocol
+	}
+	return ProtocolGRPC
+}
+
+// NewTranslator creates a translator for the OTLP exporter of the data type.
+// The protocol determines whether the gRPC or HTTP exporter is used.
+func NewTranslator(dataType component.DataType, protocol string) common.Translator[component.Config] {
+	t := &translator{dataType: dataType, factory: otlpexporter.NewFactory()}
+	if protocol == ProtocolHTTP {
+		t.factory = otlphttpexporter.NewFactory()
+	}
+	return t
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.dataType.String())
+}
+
+// Translate creates an exporter config that sends the telemetry to the
+// configured OTLP endpoint.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	configKey, ok := configKeys[t.dataType]
+	if !ok {
+		return nil, fmt.Errorf("no config key defined for data type: %s", t.dataType)
+	}
+	if conf == nil || !conf.IsSet(configKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: configKey}
+	}
+	endpoint, ok := common.GetString(conf, common.ConfigKey(configKey, endpointKey))
+	if !ok {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.ConfigKey(configKey, endpointKey)}
+	}
+	headers := getHeaders(conf, common.ConfigKey(configKey, headersKey))
+	compression, hasCompression := common.GetString(conf, common.ConfigKey(configKey, compressionKey))
+	tlsSettings := toTLSSettings(getClientConfig(conf, common.ConfigKey(configKey, common.TlsKey)))
+	tlsSettings.Insecure = common.GetOrDefaultBool(conf, common.ConfigKey(configKey, common.InsecureKey), false)
+
+	switch cfg := t.factory.CreateDefaultConfig().(type) {
+	case *otlpexporter.Config:
+		cfg.Endpoint = endpoint
+		if headers != nil {
+			cfg.Headers = headers
+		}
+		if hasCompression {
+			cfg.Compression = configcompression.Type(compression)
+		}
+		cfg.TLSSetting = tlsSettings
+		return cfg, nil
+	case *otlphttpexporter.Config:
+		cfg.Endpoint = endpoint
+		if headers != nil {
+			cfg.Headers = headers
+		}
+		if hasCompression {
+			cfg.Compression = configcompression.Type(compression)
+		}
+		cfg.TLSSetting = tlsSettings
+		return cfg, nil
+	default:
+		return nil, fmt.Errorf("unsupported exporter config: %T", cfg)
+	}
+}
+
+func getHeaders(conf *confmap.Conf, key string) map[string]configopaque.String {
+	values, ok := conf.Get(key).(map[string]interface{})
+	if !ok || len(values) == 0 {
+		return nil
+	}
+	headers := make(map[string]configopaque.String, len(values))
+	for name, value := range values {
+		headers[name] = configopaque.String(fmt.Sprint(value))
+	}
+	return headers
+}
+
+// getClientConfig reads the tls section using the same keys as the TLS
+// settings of the agent's other clients.
+func getClientConfig(conf *confmap.Conf, key string) tls.ClientConfig {
+	var cfg tls.ClientConfig
+	cfg.TLSCA, _ = common.GetString(conf, common.ConfigKey(key, tlsCAKey))
+	cfg.TLSCert, _ = common.GetString(conf, common.ConfigKey(key, tlsCertKey))
+	cfg.TLSKey, _ = common.GetString(conf, common.ConfigKey(key, tlsKeyKey))
+	cfg.InsecureSkipVerify = common.GetOrDefaultBool(conf, common.ConfigKey(key, insecureSkipVerifyKey), false)
+	return cfg
+}
+
+func toTLSSettings(cfg tls.ClientConfig) configtls.ClientConfig {
+	return configtls.ClientConfig{
+		Config: configtls.Config{
+			CAFile:   cfg.TLSCA,
+			CertFile: cfg.TLSCert,
+			KeyFile:  cfg.TLSKey,
+		},
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package otlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/configopaque"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/collector/exporter/otlphttpexporter"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslatorGRPC(t *testing.T) {
+	tt := NewTranslator(component.DataTypeMetrics, ProtocolGRPC)
+	assert.EqualValues(t, "otlp/metrics", tt.ID().String())
+	testCases := map[string]struct {
+		input   map[string]interface{}
+		want    *otlpexporter.Config
+		wantErr error
+	}{
+		"WithoutOtlpExporter": {
+			input:   map[string]interface{}{"metrics": map[string]interface{}{}},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: "metrics::otlp_exporter"},
+		},
+		"WithoutEndpoint": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{"otlp_exporter": map[string]interface{}{}},
+			},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: "metrics::otlp_exporter::endpoint"},
+		},
+		"WithEndpoint": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{"otlp_exporter": map[string]interface{}{
+					"endpoint": "gateway:4317",
+				}},
+			},
+			want: func() *otlpexporter.Config {
+				cfg := otlpexporter.NewFactory().CreateDefaultConfig().(*otlpexporter.Config)
+				cfg.Endpoint = "gateway:4317"
+				return cfg
+			}(),
+		},
+		"WithSettings": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{"otlp_exporter": map[string]interface{}{
+					"endpoint":    "gateway:4317",
+					"headers":     map[string]interface{}{"x-team": "infra"},
+					"compression": "zstd",
+					"tls": map[string]interface{}{
+						"tls_ca":               "/etc/ssl/ca.pem",
+						"tls_cert":             "/etc/ssl/cert.pem",
+						"tls_key":              "/etc/ssl/key.pem",
+						"insecure_skip_verify": true,
+					},
+				}},
+			},
+			want: func() *otlpexporter.Config {
+				cfg := otlpexporter.NewFactory().CreateDefaultConfig().(*otlpexporter.Config)
+				cfg.Endpoint = "gateway:4317"
+				cfg.Headers = map[string]configopaque.String{"x-team": "infra"}
+				cfg.Compression = configcompression.TypeZstd
+				cfg.TLSSetting = configtls.ClientConfig{
+					Config: configtls.Config{
+						CAFile:   "/etc/ssl/ca.pem",
+						CertFile: "/etc/ssl/cert.pem",
+						KeyFile:  "/etc/ssl/key.pem",
+					},
+					InsecureSkipVerify: true,
+				}
+				return cfg
+			}(),
+		},
+		"WithInsecure": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{"otlp_exporter": map[string]interface{}{
+					"endpoint": "localhost:4317",
+					"insecure": true,
+				}},
+			},
+			want: func() *otlpexporter.Config {
+				cfg := otlpexporter.NewFactory().CreateDefaultConfig().(*otlpexporter.Config)
+				cfg.Endpoint = "localhost:4317"
+				cfg.TLSSetting.Insecure = true
+				return cfg
+			}(),
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.Translate(confmap.NewFromStringMap(testCase.input))
+			assert.Equal(t, testCase.wantErr, err)
+			if err == nil {
+				require.NotNil(t, got)
+				assert.Equal(t, testCase.want, got)
+				assert.NoError(t, got.(*otlpexporter.Config).Validate())
+			}
+		})
+	}
+}
+
+func TestTranslatorHTTP(t *testing.T) {
+	conf := confmap.NewFromStringMap(map[string]interface{}{
+		"traces": map[string]interface{}{"otlp_exporter": map[string]interface{}{
+			"endpoint": "https://gateway:4318",
+			"protocol": "http",
+			"tls":      map[string]interface{}{"tls_ca": "/etc/ssl/ca.pem"},
+		}},
+	})
+	assert.True(t, IsSet(conf, component.DataTypeTraces))
+	assert.False(t, IsSet(conf, component.DataTypeMetrics))
+	assert.Equal(t, ProtocolHTTP, Protocol(conf, component.DataTypeTraces))
+	assert.Equal(t, ProtocolGRPC, Protocol(conf, component.DataTypeMetrics))
+
+	tt := NewTranslator(component.DataTypeTraces, Protocol(conf, component.DataTypeTraces))
+	assert.EqualValues(t, "otlphttp/traces", tt.ID().String())
+	got, err := tt.Translate(conf)
+	require.NoError(t, err)
+	cfg, ok := got.(*otlphttpexporter.Config)
+	require.True(t, ok)
+	assert.Equal(t, "https://gateway:4318", cfg.Endpoint)
+	assert.Equal(t, "/etc/ssl/ca.pem", cfg.TLSSetting.CAFile)
+	// keeps the default compression
+	assert.Equal(t, configcompression.TypeGzip, cfg.Compression)
+	assert.NoError(t, cfg.Validate())
+}
@@ -0,0 +1,58 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package prometheus
+
+import (
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusexporter"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+	"go.opentelemetry.io/collector/exporter"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+const (
+	endpointKey         = "endpoint"
+	namespaceKey        = "namespace"
+	metricExpirationKey = "metric_expiration"
+
+	defaultEndpoint = "localhost:9464"
+)
+
+type translator struct {
+	name    string
+	factory exporter.Factory
+}
+
+var _ common.Translator[component.Config] = (*translator)(nil)
+
+func NewTranslator() common.Translator[component.Config] {
+	return &translator{factory: prometheusexporter.NewFactory()}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), t.name)
+}
+
+// Translate creates an exporter config that serves the metrics on a local
+// /metrics endpoint in the OpenMetrics format.
+func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if conf == nil || !conf.IsSet(common.MetricsPrometheusExporterKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.MetricsPrometheusExporterKey}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*prometheusexporter.Config)
+	cfg.Endpoint = defaultEndpoint
+	if endpoint, ok := common.GetString(conf, common.ConfigKey(common.MetricsPrometheusExporterKey, endpointKey)); ok {
+		cfg.Endpoint = endpoint
+	}
+	if namespace, ok := common.GetString(conf, common.ConfigKey(common.MetricsPrometheusExporterKey, namespaceKey)); ok {
+		cfg.Namespace = namespace
+	}
+	if expiration, ok := common.GetDuration(conf, common.ConfigKey(common.MetricsPrometheusExporterKey, metricExpirationKey)); ok {
+		cfg.MetricExpiration = expiration
+	}
+	cfg.EnableOpenMetrics = true
+	cfg.ResourceToTelemetrySettings.Enabled = true
+	return cfg, nil
+}
@@ -0,0 +1,67 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package prometheus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusexporter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	tt := NewTranslator()
+	assert.EqualValues(t, "prometheus", tt.ID().String())
+	testCases := map[string]struct {
+		input          map[string]interface{}
+		wantEndpoint   string
+		wantNamespace  string
+		wantExpiration time.Duration
+		wantErr        error
+	}{
+		"WithoutPrometheusExporter": {
+			input:   map[string]interface{}{"metrics": map[string]interface{}{}},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: common.MetricsPrometheusExporterKey},
+		},
+		"WithDefault": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{"prometheus_exporter": map[string]interface{}{}},
+			},
+			wantEndpoint:   "localhost:9464",
+			wantExpiration: 5 * time.Minute,
+		},
+		"WithSettings": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{"prometheus_exporter": map[string]interface{}{
+					"endpoint":          "127.0.0.1:9100",
+					"namespace":         "cwagent",
+					"metric_expiration": 60,
+				}},
+			},
+			wantEndpoint:   "127.0.0.1:9100",
+			wantNamespace:  "cwagent",
+			wantExpiration: time.Minute,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.Translate(confmap.NewFromStringMap(testCase.input))
+			assert.Equal(t, testCase.wantErr, err)
+			if err == nil {
+				cfg, ok := got.(*prometheusexporter.Config)
+				require.True(t, ok)
+				assert.Equal(t, testCase.wantEndpoint, cfg.Endpoint)
+				assert.Equal(t, testCase.wantNamespace, cfg.Namespace)
+				assert.Equal(t, testCase.wantExpiration, cfg.MetricExpiration)
+				assert.True(t, cfg.EnableOpenMetrics)
+				assert.True(t, cfg.ResourceToTelemetrySettings.Enabled)
+			}
+		})
+	}
+}
@@ -59,6 +59,7 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 	if usageData, ok := common.GetBool(conf, common.ConfigKey(common.AgentKey, usageDataKey)); ok {
 		cfg.IsUsageDataEnabled = cfg.IsUsageDataEnabled && usageData
 	}
+	cfg.IsHealthMetricsEnabled = conf.IsSet(common.AgentHealthMetricsKey)
 	cfg.Stats = agent.StatsConfig{
 		Operations: t.operations,
 		UsageFlags: map[agent.Flag]any{
@@ -63,6 +63,18 @@ func TestTranslate(t *testing.T) {
 				},
 			},
 		},
+		"WithHealthMetrics": {
+			input:          map[string]interface{}{"agent": map[string]interface{}{"usage_data": false, "health_metrics": map[string]interface{}{}}},
+			isEnvUsageData: true,
+			want: &agenthealth.Config{
+				IsUsageDataEnabled:     false,
+				IsHealthMetricsEnabled: true,
+				Stats: agent.StatsConfig{
+					Operations: operations,
+					UsageFlags: usageFlags,
+				},
+			},
+		},
 		"WithUsageData/BothTrue": {
 			input:          map[string]interface{}{"agent": map[string]interface{}{"usage_data": true}},
 			isEnvUsageData: true,
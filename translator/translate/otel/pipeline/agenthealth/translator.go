@@ -0,0 +1,51 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agenthealth
+
+import (
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/awscloudwatch"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/extension/agenthealth"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/ec2taggerprocessor"
+	agenthealthreceiver "github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/agenthealth"
+)
+
+type translator struct {
+}
+
+var _ common.Translator[*common.ComponentTranslators] = (*translator)(nil)
+
+// NewTranslator creates a pipeline that publishes the stats the agent
+// collects about itself as metrics in their own namespace.
+func NewTranslator() common.Translator[*common.ComponentTranslators] {
+	return &translator{}
+}
+
+func (t *translator) ID() component.ID {
+	return component.NewIDWithName(component.DataTypeMetrics, common.PipelineNameAgentHealth)
+}
+
+// Translate creates a pipeline if the health_metrics section exists in the
+// agent section.
+func (t *translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators, error) {
+	if conf == nil || !conf.IsSet(common.AgentHealthMetricsKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.AgentHealthMetricsKey}
+	}
+	translators := &common.ComponentTranslators{
+		Receivers:  common.NewTranslatorMap(agenthealthreceiver.NewTranslator()),
+		Processors: common.NewTranslatorMap[component.Config](),
+		Exporters:  common.NewTranslatorMap(awscloudwatch.NewAgentHealthTranslator()),
+		Extensions: common.NewTranslatorMap(agenthealth.NewTranslator(component.DataTypeMetrics, []string{agenthealth.OperationPutMetricData})),
+	}
+	// the InstanceId dimension is only available from the instance metadata
+	if context.CurrentContext().Mode() == config.ModeEC2 && !context.CurrentContext().RunInContainer() {
+		translators.Processors.Set(ec2taggerprocessor.NewAgentHealthTranslator())
+	}
+	return translators, nil
+}
@@ -0,0 +1,96 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agenthealth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/util/collections"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	type want struct {
+		receivers  []string
+		processors []string
+		exporters  []string
+		extensions []string
+	}
+	tt := NewTranslator()
+	assert.EqualValues(t, "metrics/agenthealth", tt.ID().String())
+	healthMetrics := map[string]interface{}{
+		"agent": map[string]interface{}{
+			"health_metrics": map[string]interface{}{"namespace": "Fleet/Agent"},
+		},
+	}
+	testCases := map[string]struct {
+		input          map[string]interface{}
+		mode           string
+		runInContainer bool
+		want           *want
+		wantErr        error
+	}{
+		"WithoutHealthMetrics": {
+			input:   map[string]interface{}{"metrics": map[string]interface{}{}},
+			mode:    config.ModeEC2,
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: common.AgentHealthMetricsKey},
+		},
+		"WithEC2": {
+			input: healthMetrics,
+			mode:  config.ModeEC2,
+			want: &want{
+				receivers:  []string{"agenthealth"},
+				processors: []string{"ec2tagger/agenthealth"},
+				exporters:  []string{"awscloudwatch/agenthealth"},
+				extensions: []string{"agenthealth/metrics"},
+			},
+		},
+		"WithOnPrem": {
+			input: healthMetrics,
+			mode:  config.ModeOnPrem,
+			want: &want{
+				receivers:  []string{"agenthealth"},
+				processors: []string{},
+				exporters:  []string{"awscloudwatch/agenthealth"},
+				extensions: []string{"agenthealth/metrics"},
+			},
+		},
+		"WithContainer": {
+			input:          healthMetrics,
+			mode:           config.ModeEC2,
+			runInContainer: true,
+			want: &want{
+				receivers:  []string{"agenthealth"},
+				processors: []string{},
+				exporters:  []string{"awscloudwatch/agenthealth"},
+				extensions: []string{"agenthealth/metrics"},
+			},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			context.ResetContext()
+			context.CurrentContext().SetMode(testCase.mode)
+			context.CurrentContext().SetRunInContainer(testCase.runInContainer)
+			got, err := tt.Translate(confmap.NewFromStringMap(testCase.input))
+			assert.Equal(t, testCase.wantErr, err)
+			if testCase.want == nil {
+				assert.Nil(t, got)
+			} else {
+				require.NotNil(t, got)
+				assert.Equal(t, testCase.want.receivers, collections.MapSlice(got.Receivers.Keys(), component.ID.String))
+				assert.Equal(t, testCase.want.processors, collections.MapSlice(got.Processors.Keys(), component.ID.String))
+				assert.Equal(t, testCase.want.exporters, collections.MapSlice(got.Exporters.Keys(), component.ID.String))
+				assert.Equal(t, testCase.want.extensions, collections.MapSlice(got.Extensions.Keys(), component.ID.String))
+			}
+		})
+	}
+}
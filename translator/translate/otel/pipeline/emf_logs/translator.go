@@ -11,6 +11,7 @@ import (
 
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/otel_aws_cloudwatch_logs"
+	otlpExporter "github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/otlp"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/extension/agenthealth"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/batchprocessor"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/tcp_logs"
@@ -52,6 +53,9 @@ func (t *translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators
 		Exporters:  common.NewTranslatorMap(otel_aws_cloudwatch_logs.NewTranslatorWithName(common.PipelineNameEmfLogs)),
 		Extensions: common.NewTranslatorMap(agenthealth.NewTranslator(component.DataTypeLogs, []string{agenthealth.OperationPutLogEvents})),
 	}
+	if otlpExporter.IsSet(conf, component.DataTypeLogs) {
+		translators.Exporters.Set(otlpExporter.NewTranslator(component.DataTypeLogs, otlpExporter.Protocol(conf, component.DataTypeLogs)))
+	}
 	if serviceAddress, ok := common.GetString(conf, serviceAddressEMFKey); ok {
 		if strings.Contains(serviceAddress, common.Udp) {
 			translators.Receivers.Set(udp_logs.NewTranslatorWithName(common.PipelineNameEmfLogs))
@@ -51,6 +51,25 @@ func TestTranslator(t *testing.T) {
 				extensions:   []string{"agenthealth/logs"},
 			},
 		},
+		"WithOtlpExporter": {
+			input: map[string]interface{}{
+				"logs": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"emf": nil,
+					},
+					"otlp_exporter": map[string]interface{}{
+						"endpoint": "gateway:4317",
+					},
+				},
+			},
+			want: &want{
+				pipelineType: "logs/emf_logs",
+				receivers:    []string{"tcplog/emf_logs", "udplog/emf_logs"},
+				processors:   []string{"batch/emf_logs"},
+				exporters:    []string{"awscloudwatchlogs/emf_logs", "otlp/logs"},
+				extensions:   []string{"agenthealth/logs"},
+			},
+		},
 		"WithStructuredLogKey": {
 			input: map[string]interface{}{
 				"logs": map[string]interface{}{
@@ -11,16 +11,26 @@ import (
 
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/awscloudwatch"
+	otlpExporter "github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/otlp"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/prometheus"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/extension/agenthealth"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/cumulativetodeltaprocessor"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/ec2taggerprocessor"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/histogramfilter"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor/metricsdecorator"
 	otlpReceiver "github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/otlp"
 )
 
+// exportSuffix is appended to the name of the pipeline for the exporters other
+// than CloudWatch.
+const exportSuffix = "Export"
+
 type translator struct {
 	name      string
 	receivers common.TranslatorMap[component.Config]
+	// export is set for the pipeline of the prometheus and otlp exporters,
+	// which drops the histograms the agent uses to carry its distributions.
+	export bool
 }
 
 var _ common.Translator[*common.ComponentTranslators] = (*translator)(nil)
@@ -32,10 +42,24 @@ func NewTranslator(
 	name string,
 	receivers common.TranslatorMap[component.Config],
 ) common.Translator[*common.ComponentTranslators] {
-	return &translator{name, receivers}
+	return &translator{name: name, receivers: receivers}
+}
+
+// NewExportTranslator creates a host pipeline translator for the prometheus
+// and otlp exporters. It shares the receivers of the host pipeline with the
+// same name, but only the CloudWatch exporter can read the SEH1 encoded
+// histograms of the statsd and collectd distributions, so they are dropped.
+func NewExportTranslator(
+	name string,
+	receivers common.TranslatorMap[component.Config],
+) common.Translator[*common.ComponentTranslators] {
+	return &translator{name: name, receivers: receivers, export: true}
 }
 
 func (t translator) ID() component.ID {
+	if t.export {
+		return component.NewIDWithName(component.DataTypeMetrics, t.name+exportSuffix)
+	}
 	return component.NewIDWithName(component.DataTypeMetrics, t.name)
 }
 
@@ -67,8 +91,27 @@ func (t translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators,
 	translators := common.ComponentTranslators{
 		Receivers:  t.receivers,
 		Processors: common.NewTranslatorMap[component.Config](),
-		Exporters:  common.NewTranslatorMap(awscloudwatch.NewTranslator()),
-		Extensions: common.NewTranslatorMap(agenthealth.NewTranslator(component.DataTypeMetrics, []string{agenthealth.OperationPutMetricData})),
+		Exporters:  common.NewTranslatorMap[component.Config](),
+		Extensions: common.NewTranslatorMap[component.Config](),
+	}
+
+	if t.export {
+		if conf.IsSet(common.MetricsPrometheusExporterKey) {
+			log.Printf("D! prometheus exporter required because prometheus_exporter is set")
+			translators.Exporters.Set(prometheus.NewTranslator())
+		}
+		if otlpExporter.IsSet(conf, component.DataTypeMetrics) {
+			log.Printf("D! otlp exporter required because otlp_exporter is set")
+			translators.Exporters.Set(otlpExporter.NewTranslator(component.DataTypeMetrics, otlpExporter.Protocol(conf, component.DataTypeMetrics)))
+		}
+		if translators.Exporters.Len() == 0 {
+			log.Printf("D! pipeline %s has no exporters", t.ID())
+			return nil, nil
+		}
+		translators.Processors.Set(histogramfilter.NewTranslator())
+	} else {
+		translators.Exporters.Set(awscloudwatch.NewTranslator())
+		translators.Extensions.Set(agenthealth.NewTranslator(component.DataTypeMetrics, []string{agenthealth.OperationPutMetricData}))
 	}
 
 	// we need to add delta processor because (only) diskio and net input plugins report delta metric
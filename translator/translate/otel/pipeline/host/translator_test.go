@@ -40,6 +40,7 @@ func TestTranslator(t *testing.T) {
 	testCases := map[string]struct {
 		input        map[string]interface{}
 		pipelineName string
+		export       bool
 		want         *want
 		wantErr      error
 	}{
@@ -81,6 +82,66 @@ func TestTranslator(t *testing.T) {
 				extensions: []string{"agenthealth/metrics"},
 			},
 		},
+		"WithPrometheusExporter": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{
+					"prometheus_exporter": map[string]interface{}{},
+				},
+			},
+			pipelineName: common.PipelineNameHost,
+			want: &want{
+				pipelineID: "metrics/host",
+				receivers:  []string{"nop", "other"},
+				processors: []string{},
+				exporters:  []string{"awscloudwatch"},
+				extensions: []string{"agenthealth/metrics"},
+			},
+		},
+		"WithPrometheusExporterExport": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{
+					"prometheus_exporter": map[string]interface{}{},
+				},
+			},
+			pipelineName: common.PipelineNameHost,
+			export:       true,
+			want: &want{
+				pipelineID: "metrics/hostExport",
+				receivers:  []string{"nop", "other"},
+				processors: []string{"histogramfilter"},
+				exporters:  []string{"prometheus"},
+				extensions: []string{},
+			},
+		},
+		"WithOtlpExporterExport": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{
+					"metrics_collected": map[string]interface{}{
+						"net": map[string]interface{}{},
+					},
+					"otlp_exporter": map[string]interface{}{
+						"endpoint": "https://gateway:4318",
+						"protocol": "http",
+					},
+				},
+			},
+			pipelineName: common.PipelineNameHostDeltaMetrics,
+			export:       true,
+			want: &want{
+				pipelineID: "metrics/hostDeltaMetricsExport",
+				receivers:  []string{"nop", "other"},
+				processors: []string{"histogramfilter", "cumulativetodelta/hostDeltaMetrics"},
+				exporters:  []string{"otlphttp/metrics"},
+				extensions: []string{},
+			},
+		},
+		"WithoutExportersExport": {
+			input: map[string]interface{}{
+				"metrics": map[string]interface{}{},
+			},
+			pipelineName: common.PipelineNameHost,
+			export:       true,
+		},
 		"WithMetricDecoration": {
 			input: map[string]interface{}{
 				"metrics": map[string]interface{}{
@@ -131,10 +192,14 @@ func TestTranslator(t *testing.T) {
 		nopType, _ := component.NewType("nop")
 		otherType, _ := component.NewType("other")
 		t.Run(name, func(t *testing.T) {
-			ht := NewTranslator(testCase.pipelineName, common.NewTranslatorMap[component.Config](
+			receivers := common.NewTranslatorMap[component.Config](
 				&testTranslator{id: component.NewID(nopType)},
 				&testTranslator{id: component.NewID(otherType)},
-			))
+			)
+			ht := NewTranslator(testCase.pipelineName, receivers)
+			if testCase.export {
+				ht = NewExportTranslator(testCase.pipelineName, receivers)
+			}
 			conf := confmap.NewFromStringMap(testCase.input)
 			got, err := ht.Translate(conf)
 			require.Equal(t, testCase.wantErr, err)
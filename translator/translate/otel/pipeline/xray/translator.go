@@ -12,6 +12,7 @@ import (
 
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
 	awsxrayexporter "github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/awsxray"
+	otlpExporter "github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/exporter/otlp"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/extension/agenthealth"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/processor"
 	awsxrayreceiver "github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/receiver/awsxray"
@@ -50,6 +51,9 @@ func (t *translator) Translate(conf *confmap.Conf) (*common.ComponentTranslators
 		Exporters:  common.NewTranslatorMap(awsxrayexporter.NewTranslator()),
 		Extensions: common.NewTranslatorMap(agenthealth.NewTranslator(component.DataTypeTraces, []string{agenthealth.OperationPutTraceSegments})),
 	}
+	if otlpExporter.IsSet(conf, component.DataTypeTraces) {
+		translators.Exporters.Set(otlpExporter.NewTranslator(component.DataTypeTraces, otlpExporter.Protocol(conf, component.DataTypeTraces)))
+	}
 	if conf.IsSet(xrayKey) {
 		translators.Receivers.Set(awsxrayreceiver.NewTranslator())
 	}
@@ -64,6 +64,24 @@ func TestTranslator(t *testing.T) {
 				extensions: []string{"agenthealth/traces"},
 			},
 		},
+		"WithOtlpExporter": {
+			input: map[string]interface{}{
+				"traces": map[string]interface{}{
+					"traces_collected": map[string]interface{}{
+						"xray": nil,
+					},
+					"otlp_exporter": map[string]interface{}{
+						"endpoint": "gateway:4317",
+					},
+				},
+			},
+			want: &want{
+				receivers:  []string{"awsxray"},
+				processors: []string{"batch/xray"},
+				exporters:  []string{"awsxray", "otlp/traces"},
+				extensions: []string{"agenthealth/traces"},
+			},
+		},
 		"WithXrayAndOtlpKey": {
 			input: map[string]interface{}{
 				"traces": map[string]interface{}{
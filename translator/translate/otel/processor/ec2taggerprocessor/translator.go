@@ -41,6 +41,22 @@ func (t *translator) ID() component.ID {
 	return component.NewIDWithName(t.factory.Type(), t.name)
 }
 
+type agentHealthTranslator struct {
+	factory processor.Factory
+}
+
+var _ common.Translator[component.Config] = (*agentHealthTranslator)(nil)
+
+// NewAgentHealthTranslator creates the processor translator for the agent
+// health pipeline.
+func NewAgentHealthTranslator() common.Translator[component.Config] {
+	return &agentHealthTranslator{ec2tagger.NewFactory()}
+}
+
+func (t *agentHealthTranslator) ID() component.ID {
+	return component.NewIDWithName(t.factory.Type(), common.PipelineNameAgentHealth)
+}
+
 // Translate creates an processor config based on the fields in the
 // Metrics section of the JSON config.
 func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
@@ -72,3 +88,18 @@ func (t *translator) Translate(conf *confmap.Conf) (component.Config, error) {
 
 	return cfg, nil
 }
+
+// Translate creates a processor config that adds the InstanceId dimension to
+// the agent health metrics.
+func (t *agentHealthTranslator) Translate(conf *confmap.Conf) (component.Config, error) {
+	if conf == nil || !conf.IsSet(common.AgentHealthMetricsKey) {
+		return nil, &common.MissingKeyError{ID: t.ID(), JsonKey: common.AgentHealthMetricsKey}
+	}
+	cfg := t.factory.CreateDefaultConfig().(*ec2tagger.Config)
+	credentials := confmap.NewFromStringMap(agent.Global_Config.Credentials)
+	_ = credentials.Unmarshal(cfg)
+	cfg.EC2MetadataTags = []string{"InstanceId"}
+	cfg.RefreshIntervalSeconds = time.Duration(0)
+	cfg.IMDSRetries = retryer.GetDefaultRetryNumber()
+	return cfg, nil
+}
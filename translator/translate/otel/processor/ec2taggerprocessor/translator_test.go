@@ -92,3 +92,23 @@ func TestTranslator(t *testing.T) {
 		})
 	}
 }
+
+func TestTranslatorAgentHealth(t *testing.T) {
+	etpTranslator := NewAgentHealthTranslator()
+	require.EqualValues(t, "ec2tagger/agenthealth", etpTranslator.ID().String())
+
+	_, err := etpTranslator.Translate(confmap.NewFromStringMap(map[string]interface{}{
+		"metrics": map[string]interface{}{"append_dimensions": map[string]interface{}{"ImageId": "${aws:ImageId}"}},
+	}))
+	require.Equal(t, &common.MissingKeyError{ID: etpTranslator.ID(), JsonKey: common.AgentHealthMetricsKey}, err)
+
+	got, err := etpTranslator.Translate(confmap.NewFromStringMap(map[string]interface{}{
+		"agent": map[string]interface{}{"health_metrics": map[string]interface{}{}},
+	}))
+	require.NoError(t, err)
+	gotCfg, ok := got.(*ec2tagger.Config)
+	require.True(t, ok)
+	require.Equal(t, []string{"InstanceId"}, gotCfg.EC2MetadataTags)
+	require.Empty(t, gotCfg.EC2InstanceTagKeys)
+	require.Equal(t, time.Duration(0), gotCfg.RefreshIntervalSeconds)
+}
@@ -0,0 +1,22 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package histogramfilter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/histogramfilter"
+)
+
+func TestTranslator(t *testing.T) {
+	tt := NewTranslator()
+	assert.Equal(t, "histogramfilter", tt.ID().String())
+	got, err := tt.Translate(confmap.New())
+	require.NoError(t, err)
+	assert.Equal(t, &histogramfilter.Config{}, got)
+}
@@ -0,0 +1,65 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agenthealth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/confmap"
+
+	"github.com/aws/amazon-cloudwatch-agent/receiver/agenthealth"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
+)
+
+func TestTranslator(t *testing.T) {
+	tt := NewTranslator()
+	assert.EqualValues(t, "agenthealth", tt.ID().String())
+	testCases := map[string]struct {
+		input        map[string]interface{}
+		wantInterval time.Duration
+		wantErr      error
+	}{
+		"WithoutHealthMetrics": {
+			input:   map[string]interface{}{"agent": map[string]interface{}{}},
+			wantErr: &common.MissingKeyError{ID: tt.ID(), JsonKey: common.AgentHealthMetricsKey},
+		},
+		"WithDefaultInterval": {
+			input: map[string]interface{}{
+				"agent": map[string]interface{}{"health_metrics": map[string]interface{}{}},
+			},
+			wantInterval: time.Minute,
+		},
+		"WithAgentInterval": {
+			input: map[string]interface{}{
+				"agent": map[string]interface{}{
+					"metrics_collection_interval": 30,
+					"health_metrics":              map[string]interface{}{},
+				},
+			},
+			wantInterval: 30 * time.Second,
+		},
+		"WithInterval": {
+			input: map[string]interface{}{
+				"agent": map[string]interface{}{
+					"metrics_collection_interval": 30,
+					"health_metrics":              map[string]interface{}{"metrics_collection_interval": 300},
+				},
+			},
+			wantInterval: 5 * time.Minute,
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.Translate(confmap.NewFromStringMap(testCase.input))
+			assert.Equal(t, testCase.wantErr, err)
+			if err == nil {
+				cfg, ok := got.(*agenthealth.Config)
+				assert.True(t, ok)
+				assert.Equal(t, testCase.wantInterval, cfg.CollectionInterval)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/common"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline/agenthealth"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline/applicationsignals"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline/containerinsights"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/otel/pipeline/emf_logs"
@@ -51,32 +52,10 @@ func Translate(jsonConfig interface{}, os string) (*otelcol.Config, error) {
 		log.Printf("W! CSM has already been deprecated")
 	}
 
-	adapterReceivers, err := adapter.FindReceiversInConfig(conf, os)
+	translators, err := pipelineTranslators(conf, os)
 	if err != nil {
-		return nil, fmt.Errorf("unable to find receivers in config: %w", err)
+		return nil, err
 	}
-
-	// split out delta receiver types
-	deltaMetricsReceivers := common.NewTranslatorMap[component.Config]()
-	hostReceivers := common.NewTranslatorMap[component.Config]()
-	adapterReceivers.Range(func(translator common.Translator[component.Config]) {
-		if translator.ID().Type() == receiverAdapter.Type(common.DiskIOKey) || translator.ID().Type() == receiverAdapter.Type(common.NetKey) {
-			deltaMetricsReceivers.Set(translator)
-		} else {
-			hostReceivers.Set(translator)
-		}
-	})
-	translators := common.NewTranslatorMap(
-		applicationsignals.NewTranslator(component.DataTypeTraces),
-		applicationsignals.NewTranslator(component.DataTypeMetrics),
-		host.NewTranslator(common.PipelineNameHost, hostReceivers),
-		host.NewTranslator(common.PipelineNameHostDeltaMetrics, deltaMetricsReceivers),
-		containerinsights.NewTranslator(),
-		prometheus.NewTranslator(),
-		emf_logs.NewTranslator(),
-		xray.NewTranslator(),
-	)
-	translators.Merge(registry)
 	pipelines, err := pipeline.NewTranslator(translators).Translate(conf)
 	if err != nil {
 		return nil, err
@@ -104,6 +83,41 @@ func Translate(jsonConfig interface{}, os string) (*otelcol.Config, error) {
 	return cfg, nil
 }
 
+// pipelineTranslators creates the ordered set of pipeline translators for
+// the JSON config, including any registered through RegisterPipeline.
+func pipelineTranslators(conf *confmap.Conf, os string) (common.TranslatorMap[*common.ComponentTranslators], error) {
+	adapterReceivers, err := adapter.FindReceiversInConfig(conf, os)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find receivers in config: %w", err)
+	}
+
+	// split out delta receiver types
+	deltaMetricsReceivers := common.NewTranslatorMap[component.Config]()
+	hostReceivers := common.NewTranslatorMap[component.Config]()
+	adapterReceivers.Range(func(translator common.Translator[component.Config]) {
+		if translator.ID().Type() == receiverAdapter.Type(common.DiskIOKey) || translator.ID().Type() == receiverAdapter.Type(common.NetKey) {
+			deltaMetricsReceivers.Set(translator)
+		} else {
+			hostReceivers.Set(translator)
+		}
+	})
+	translators := common.NewTranslatorMap(
+		applicationsignals.NewTranslator(component.DataTypeTraces),
+		applicationsignals.NewTranslator(component.DataTypeMetrics),
+		host.NewTranslator(common.PipelineNameHost, hostReceivers),
+		host.NewTranslator(common.PipelineNameHostDeltaMetrics, deltaMetricsReceivers),
+		host.NewExportTranslator(common.PipelineNameHost, hostReceivers),
+		host.NewExportTranslator(common.PipelineNameHostDeltaMetrics, deltaMetricsReceivers),
+		containerinsights.NewTranslator(),
+		prometheus.NewTranslator(),
+		emf_logs.NewTranslator(),
+		xray.NewTranslator(),
+		agenthealth.NewTranslator(),
+	)
+	translators.Merge(registry)
+	return translators, nil
+}
+
 // parseAgentLogLevel returns the logging level from the JSON config, or the
 // default value.
 func parseAgentLogLevel(conf *confmap.Conf) zapcore.Level {
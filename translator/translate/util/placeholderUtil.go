@@ -51,6 +51,15 @@ const (
 
 // resolve place holder for log group and log stream.
 func ResolvePlaceholder(placeholder string, metadata map[string]string) string {
+	tmpString := ResolveMetadataPlaceholder(placeholder, metadata)
+	tmpString = strings.Replace(tmpString, datePlaceholder, time.Now().Format("2006-01-02"), -1)
+	return tmpString
+}
+
+// ResolveMetadataPlaceholder resolves the place holders of the instance metadata
+// and leaves {date} to be resolved by the plugin when the log group or log
+// stream is created.
+func ResolveMetadataPlaceholder(placeholder string, metadata map[string]string) string {
 	tmpString := placeholder
 	if tmpString == "" {
 		tmpString = instanceIdPlaceholder
@@ -58,7 +67,6 @@ func ResolvePlaceholder(placeholder string, metadata map[string]string) string {
 	for k, v := range metadata {
 		tmpString = strings.Replace(tmpString, k, v, -1)
 	}
-	tmpString = strings.Replace(tmpString, datePlaceholder, time.Now().Format("2006-01-02"), -1)
 	return tmpString
 }
 
@@ -5,6 +5,7 @@ package util
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -52,6 +53,14 @@ func TestGetMetadataInfoEmptyAccountId(t *testing.T) {
 	assert.Equal(t, unknownAccountId, m[accountIdPlaceholder])
 }
 
+func TestResolvePlaceholder(t *testing.T) {
+	m := map[string]string{instanceIdPlaceholder: dummyInstanceId, hostnamePlaceholder: dummyHostName}
+	date := time.Now().Format("2006-01-02")
+	assert.Equal(t, dummyInstanceId, ResolvePlaceholder("", m))
+	assert.Equal(t, "app/"+dummyHostName+"/"+date, ResolvePlaceholder("app/{hostname}/{date}", m))
+	assert.Equal(t, "app/"+dummyHostName+"/{date}/{path:2}", ResolveMetadataPlaceholder("app/{hostname}/{date}/{path:2}", m))
+}
+
 func mockMetadataProvider(instanceId, hostname, privateIp, accountId string) func() *Metadata {
 	return func() *Metadata {
 		return &Metadata{